@@ -21,7 +21,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/pulumi/pulumi/pkg/backend"
-	"github.com/pulumi/pulumi/pkg/backend/cloud"
 	"github.com/pulumi/pulumi/pkg/diag/colors"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 )
@@ -39,7 +38,11 @@ func newCancelCmd() *cobra.Command {
 			"inconsistent state if a resource operation was pending when the update was canceled.\n" +
 			"\n" +
 			"After this command completes successfully, the stack will be ready for further\n" +
-			"updates.",
+			"updates.\n" +
+			"\n" +
+			"For stacks in the local backend, this command removes the lock held by the running\n" +
+			"update. If that update is running on this machine, it is also interrupted as if ^C\n" +
+			"had been pressed.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			// Use the stack provided or, if missing, default to the current one.
 			stack := ""
@@ -56,12 +59,6 @@ func newCancelCmd() *cobra.Command {
 				return err
 			}
 
-			// Ensure that we are targeting the Pulumi cloud.
-			backend, ok := s.Backend().(cloud.Backend)
-			if !ok {
-				return errors.New("the `cancel` command is not supported for local stacks")
-			}
-
 			// Ensure the user really wants to do this.
 			prompt := fmt.Sprintf("This will irreversibly cancel the currently running update for '%s'!", s.Name())
 			if !yes && !confirmPrompt(prompt, s.Name().String(), opts) {
@@ -69,7 +66,7 @@ func newCancelCmd() *cobra.Command {
 			}
 
 			// Cancel the update.
-			if err := backend.CancelCurrentUpdate(commandContext(), s.Name()); err != nil {
+			if err := s.Backend().CancelCurrentUpdate(commandContext(), s.Name()); err != nil {
 				return err
 			}
 
@@ -15,6 +15,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -29,9 +30,11 @@ import (
 	"golang.org/x/crypto/ssh/terminal"
 
 	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/workspace"
 )
 
@@ -67,7 +70,10 @@ func newConfigCmd() *cobra.Command {
 		&stack, "stack", "s", "",
 		"The name of the stack to operate on. Defaults to the current stack")
 
+	cmd.AddCommand(newConfigCpCmd(&stack))
+	cmd.AddCommand(newConfigExportCmd(&stack))
 	cmd.AddCommand(newConfigGetCmd(&stack))
+	cmd.AddCommand(newConfigImportCmd(&stack))
 	cmd.AddCommand(newConfigRmCmd(&stack))
 	cmd.AddCommand(newConfigSetCmd(&stack))
 	cmd.AddCommand(newConfigRefreshCmd(&stack))
@@ -76,10 +82,16 @@ func newConfigCmd() *cobra.Command {
 }
 
 func newConfigGetCmd(stack *string) *cobra.Command {
+	var path bool
+
 	getCmd := &cobra.Command{
 		Use:   "get <key>",
 		Short: "Get a single configuration value",
-		Args:  cmdutil.SpecificArgs([]string{"key"}),
+		Long: "Get a single configuration value.\n" +
+			"\n" +
+			"If --path is passed, the key is treated as a path to a value nested inside a structured\n" +
+			"configuration value, e.g. `vpc.subnets[0]` or `tags[\"Name\"]`.",
+		Args: cmdutil.SpecificArgs([]string{"key"}),
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			opts := backend.DisplayOptions{
 				Color: cmdutil.GetGlobalColorization(),
@@ -90,23 +102,33 @@ func newConfigGetCmd(stack *string) *cobra.Command {
 				return err
 			}
 
-			key, err := parseConfigKey(args[0])
+			key, keyPath, err := parseConfigKeyPath(args[0], path)
 			if err != nil {
 				return errors.Wrap(err, "invalid configuration key")
 			}
 
-			return getConfig(s, key)
+			return getConfig(s, key, keyPath)
 		}),
 	}
+	getCmd.PersistentFlags().BoolVar(
+		&path, "path", false,
+		"The key contains a path to a property in a structured value")
 
 	return getCmd
 }
 
 func newConfigRmCmd(stack *string) *cobra.Command {
+	var path bool
+
 	rmCmd := &cobra.Command{
 		Use:   "rm <key>",
 		Short: "Remove configuration value",
-		Args:  cmdutil.SpecificArgs([]string{"key"}),
+		Long: "Remove configuration value.\n" +
+			"\n" +
+			"If --path is passed, the key is treated as a path to a value nested inside a structured\n" +
+			"configuration value, and only that value is removed. Removing an array element shifts the\n" +
+			"elements that follow it.",
+		Args: cmdutil.SpecificArgs([]string{"key"}),
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			opts := backend.DisplayOptions{
 				Color: cmdutil.GetGlobalColorization(),
@@ -117,7 +139,7 @@ func newConfigRmCmd(stack *string) *cobra.Command {
 				return err
 			}
 
-			key, err := parseConfigKey(args[0])
+			key, keyPath, err := parseConfigKeyPath(args[0], path)
 			if err != nil {
 				return errors.Wrap(err, "invalid configuration key")
 			}
@@ -127,13 +149,20 @@ func newConfigRmCmd(stack *string) *cobra.Command {
 				return err
 			}
 
-			if ps.Config != nil {
+			if v, has := ps.Config[key]; has && len(keyPath) > 0 {
+				if ps.Config[key], err = removeConfigPath(v, keyPath); err != nil {
+					return errors.Wrapf(err, "could not remove '%s'", args[0])
+				}
+			} else if ps.Config != nil {
 				delete(ps.Config, key)
 			}
 
 			return workspace.SaveProjectStack(s.Name().StackName(), ps)
 		}),
 	}
+	rmCmd.PersistentFlags().BoolVar(
+		&path, "path", false,
+		"The key contains a path to a property in a structured value")
 
 	return rmCmd
 }
@@ -212,13 +241,19 @@ func newConfigRefreshCmd(stack *string) *cobra.Command {
 func newConfigSetCmd(stack *string) *cobra.Command {
 	var plaintext bool
 	var secret bool
+	var path bool
 
 	setCmd := &cobra.Command{
 		Use:   "set <key> [value]",
 		Short: "Set configuration value",
 		Long: "Configuration values can be accessed when a stack is being deployed and used to configure behavior. \n" +
 			"If a value is not present on the command line, pulumi will prompt for the value. Multi-line values\n" +
-			"may be set by piping a file to standard in.",
+			"may be set by piping a file to standard in.\n" +
+			"\n" +
+			"If --path is passed, the key is treated as a path to a value nested inside a structured\n" +
+			"configuration value, e.g. `vpc.subnets[0]` or `tags[\"Name\"]`. Any objects or arrays along the path\n" +
+			"that do not yet exist are created, and an array may be extended by setting the element just past\n" +
+			"its end. Secrets may be stored at any leaf of a structured value.",
 		Args: cmdutil.RangeArgs(1, 2),
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			opts := backend.DisplayOptions{
@@ -231,7 +266,7 @@ func newConfigSetCmd(stack *string) *cobra.Command {
 				return err
 			}
 
-			key, err := parseConfigKey(args[0])
+			key, keyPath, err := parseConfigKeyPath(args[0], path)
 			if err != nil {
 				return errors.Wrap(err, "invalid configuration key")
 			}
@@ -287,6 +322,12 @@ func newConfigSetCmd(stack *string) *cobra.Command {
 				return err
 			}
 
+			if len(keyPath) > 0 {
+				existing, has := ps.Config[key]
+				if v, err = setConfigPath(existing, has, keyPath, v); err != nil {
+					return errors.Wrapf(err, "could not set '%s'", args[0])
+				}
+			}
 			ps.Config[key] = v
 
 			return workspace.SaveProjectStack(s.Name().StackName(), ps)
@@ -299,6 +340,9 @@ func newConfigSetCmd(stack *string) *cobra.Command {
 	setCmd.PersistentFlags().BoolVar(
 		&secret, "secret", false,
 		"Encrypt the value instead of storing it in plaintext")
+	setCmd.PersistentFlags().BoolVar(
+		&path, "path", false,
+		"The key contains a path to a property in a structured value")
 
 	return setCmd
 }
@@ -318,6 +362,29 @@ func parseConfigKey(key string) (config.Key, error) {
 	return config.ParseKey(key)
 }
 
+// parseConfigKeyPath parses a configuration key. If path is true, the key may instead be a path to a value nested
+// inside a structured configuration value, e.g. `vpc.subnets[0]`; the path within the value is returned as well.
+func parseConfigKeyPath(key string, path bool) (config.Key, resource.PropertyPath, error) {
+	if !path {
+		k, err := parseConfigKey(key)
+		return k, nil, err
+	}
+
+	p, err := resource.ParsePropertyPath(key)
+	if err != nil {
+		return config.Key{}, nil, err
+	}
+	name, ok := p[0].(string)
+	if !ok {
+		return config.Key{}, nil, errors.New("a configuration path must begin with a key name")
+	}
+	k, err := parseConfigKey(name)
+	if err != nil {
+		return config.Key{}, nil, err
+	}
+	return k, p[1:], nil
+}
+
 func prettyKey(k config.Key) string {
 	proj, err := workspace.DetectProject()
 	if err != nil {
@@ -386,7 +453,7 @@ func listConfig(stack backend.Stack, showSecrets bool) error {
 	return nil
 }
 
-func getConfig(stack backend.Stack, key config.Key) error {
+func getConfig(stack backend.Stack, key config.Key, path resource.PropertyPath) error {
 	ps, err := workspace.DetectProjectStack(stack.Name().StackName())
 	if err != nil {
 		return err
@@ -408,6 +475,11 @@ func getConfig(stack backend.Stack, key config.Key) error {
 		if err != nil {
 			return errors.Wrap(err, "could not decrypt configuration value")
 		}
+		if len(path) > 0 {
+			if raw, err = getConfigPath(raw, path); err != nil {
+				return errors.Wrapf(err, "configuration value '%s'", prettyKey(key))
+			}
+		}
 		fmt.Printf("%v\n", raw)
 		return nil
 	}
@@ -416,6 +488,178 @@ func getConfig(stack backend.Stack, key config.Key) error {
 		"configuration key '%s' not found for stack '%s'", prettyKey(key), stack.Name())
 }
 
+// getConfigPath returns the value found at the given path inside the decrypted JSON representation of a structured
+// configuration value. Strings are returned as-is; objects and arrays are returned as JSON.
+func getConfigPath(raw string, path resource.PropertyPath) (string, error) {
+	var obj interface{}
+	if err := json.Unmarshal([]byte(raw), &obj); err != nil {
+		return "", errors.New("value is not an object or array")
+	}
+
+	for _, key := range path {
+		switch key := key.(type) {
+		case string:
+			m, ok := obj.(map[string]interface{})
+			if !ok {
+				return "", errors.Errorf("path '%s' not found", path)
+			}
+			if obj, ok = m[key]; !ok {
+				return "", errors.Errorf("path '%s' not found", path)
+			}
+		case int:
+			a, ok := obj.([]interface{})
+			if !ok || key >= len(a) {
+				return "", errors.Errorf("path '%s' not found", path)
+			}
+			obj = a[key]
+		}
+	}
+
+	if s, ok := obj.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// setConfigPath sets the value at the given path inside a structured configuration value, creating the value and any
+// objects or arrays along the path that do not yet exist. Secret leaves are stored in their encrypted form.
+func setConfigPath(existing config.Value, has bool, path resource.PropertyPath, v config.Value) (config.Value, error) {
+	leaf, err := v.ToObject()
+	if err != nil {
+		return config.Value{}, err
+	}
+	if v.Secure() && !v.Object() {
+		leaf = map[string]interface{}{"secure": leaf}
+	}
+
+	var root interface{}
+	if has {
+		if !existing.Object() {
+			return config.Value{}, errors.New("existing value is not an object or array")
+		}
+		if root, err = existing.ToObject(); err != nil {
+			return config.Value{}, err
+		}
+	}
+
+	var set func(container interface{}, path resource.PropertyPath) (interface{}, error)
+	set = func(container interface{}, path resource.PropertyPath) (interface{}, error) {
+		if len(path) == 0 {
+			return leaf, nil
+		}
+
+		switch key := path[0].(type) {
+		case string:
+			if container == nil {
+				container = make(map[string]interface{})
+			}
+			m, ok := container.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("cannot set property '%s' of a value that is not an object", key)
+			}
+			child, err := set(m[key], path[1:])
+			if err != nil {
+				return nil, err
+			}
+			m[key] = child
+			return m, nil
+		case int:
+			if container == nil {
+				container = []interface{}{}
+			}
+			a, ok := container.([]interface{})
+			if !ok {
+				return nil, errors.Errorf("cannot set element %d of a value that is not an array", key)
+			}
+			if key > len(a) {
+				return nil, errors.Errorf("array index %d out of range; the array has %d elements", key, len(a))
+			} else if key == len(a) {
+				a = append(a, nil)
+			}
+			child, err := set(a[key], path[1:])
+			if err != nil {
+				return nil, err
+			}
+			a[key] = child
+			return a, nil
+		}
+		contract.Failf("unexpected property path element %v", path[0])
+		return nil, nil
+	}
+	if root, err = set(root, path); err != nil {
+		return config.Value{}, err
+	}
+	return newConfigObjectValue(root)
+}
+
+// removeConfigPath removes the value at the given path inside a structured configuration value. Elements removed from
+// arrays are spliced out.
+func removeConfigPath(existing config.Value, path resource.PropertyPath) (config.Value, error) {
+	root, err := existing.ToObject()
+	if err != nil {
+		return config.Value{}, err
+	}
+
+	var remove func(container interface{}, path resource.PropertyPath) (interface{}, error)
+	remove = func(container interface{}, path resource.PropertyPath) (interface{}, error) {
+		switch key := path[0].(type) {
+		case string:
+			m, ok := container.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("property '%s' not found", key)
+			}
+			child, has := m[key]
+			if !has {
+				return nil, errors.Errorf("property '%s' not found", key)
+			}
+			if len(path) == 1 {
+				delete(m, key)
+				return m, nil
+			}
+			if m[key], err = remove(child, path[1:]); err != nil {
+				return nil, err
+			}
+			return m, nil
+		case int:
+			a, ok := container.([]interface{})
+			if !ok || key >= len(a) {
+				return nil, errors.Errorf("element %d not found", key)
+			}
+			if len(path) == 1 {
+				return append(a[:key], a[key+1:]...), nil
+			}
+			if a[key], err = remove(a[key], path[1:]); err != nil {
+				return nil, err
+			}
+			return a, nil
+		}
+		contract.Failf("unexpected property path element %v", path[0])
+		return nil, nil
+	}
+	if root, err = remove(root, path); err != nil {
+		return config.Value{}, err
+	}
+	return newConfigObjectValue(root)
+}
+
+// newConfigObjectValue creates a structured configuration value from its decoded JSON form, noting whether any of its
+// leaves are secrets.
+func newConfigObjectValue(obj interface{}) (config.Value, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return config.Value{}, err
+	}
+	var v config.Value
+	if err = json.Unmarshal(b, &v); err != nil {
+		return config.Value{}, err
+	}
+	return v, nil
+}
+
 var (
 	// keyPattern is the regular expression a configuration key must match before we check (and error) if we think
 	// it is a password
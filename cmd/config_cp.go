@@ -0,0 +1,134 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func newConfigCpCmd(stack *string) *cobra.Command {
+	var dest string
+
+	cmd := &cobra.Command{
+		Use:   "cp [key]",
+		Args:  cmdutil.MaximumNArgs(1),
+		Short: "Copy configuration values to another stack",
+		Long: "Copy configuration values to another stack.\n" +
+			"\n" +
+			"If a key is given, only that value is copied; otherwise, all of the stack's configuration is\n" +
+			"copied. Values in the destination stack with the same keys are replaced. Secrets are decrypted\n" +
+			"and re-encrypted with the destination stack's secrets provider unless both stacks encrypt\n" +
+			"secrets in the same way.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			if dest == "" {
+				return errors.New("missing required flag --dest")
+			}
+
+			opts := backend.DisplayOptions{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			src, err := requireStack(*stack, true, opts, false /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			dst, err := requireStack(dest, false, opts, false /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			if src.Name().String() == dst.Name().String() {
+				return errors.New("the source and destination stacks must be different")
+			}
+
+			srcStack, err := workspace.DetectProjectStack(src.Name().StackName())
+			if err != nil {
+				return err
+			}
+
+			values := srcStack.Config
+			if len(args) == 1 {
+				key, err := parseConfigKey(args[0])
+				if err != nil {
+					return errors.Wrap(err, "invalid configuration key")
+				}
+				v, has := values[key]
+				if !has {
+					return errors.Errorf(
+						"configuration key '%s' not found for stack '%s'", prettyKey(key), src.Name())
+				}
+				values = config.Map{key: v}
+			}
+
+			// If both stacks share a secrets provider and its settings, their ciphertexts are interchangeable and can
+			// be copied as-is. Otherwise, secrets are decrypted and re-encrypted for the destination stack.
+			dstStack, err := workspace.DetectProjectStack(dst.Name().StackName())
+			if err != nil {
+				return err
+			}
+			decrypter, encrypter := config.NopDecrypter, config.NopEncrypter
+			if values.HasSecureValue() && !sameSecretsSettings(srcStack, dstStack) {
+				decrypter = stackStateCrypter(src)
+				if encrypter, err = backend.GetStackCrypter(dst); err != nil {
+					return err
+				}
+
+				// Fetching the destination's crypter may have saved new settings for it, so reload them.
+				if dstStack, err = workspace.DetectProjectStack(dst.Name().StackName()); err != nil {
+					return err
+				}
+			}
+
+			if err = copyConfig(values, dstStack, decrypter, encrypter); err != nil {
+				return err
+			}
+			return workspace.SaveProjectStack(dst.Name().StackName(), dstStack)
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&dest, "dest", "d", "", "The name of the stack to copy configuration to")
+
+	return cmd
+}
+
+// copyConfig copies the given values into the destination stack's configuration, decrypting their secrets with
+// decrypter and re-encrypting them with encrypter.
+func copyConfig(values config.Map, dst *workspace.ProjectStack, decrypter config.Decrypter,
+	encrypter config.Encrypter) error {
+
+	if dst.Config == nil {
+		dst.Config = make(config.Map)
+	}
+	for key, value := range values {
+		copied, err := value.Copy(decrypter, encrypter)
+		if err != nil {
+			return errors.Wrapf(err, "could not copy configuration value '%s'", key)
+		}
+		dst.Config[key] = copied
+	}
+	return nil
+}
+
+// sameSecretsSettings returns true if the given stacks encrypt secrets identically, e.g. because they use the same
+// passphrase-derived key.
+func sameSecretsSettings(a, b *workspace.ProjectStack) bool {
+	return a.SecretsProvider == b.SecretsProvider && a.EncryptionSalt != "" && a.EncryptionSalt == b.EncryptionSalt
+}
@@ -0,0 +1,178 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func newConfigExportCmd(stack *string) *cobra.Command {
+	var file string
+	var format string
+	var showSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Args:  cmdutil.NoArgs,
+		Short: "Export a stack's configuration to standard out",
+		Long: "Export a stack's configuration to standard out.\n" +
+			"\n" +
+			"The configuration may be written as JSON, YAML, or shell environment variable assignments. The\n" +
+			"JSON and YAML formats can be loaded into another stack using `pulumi config import`; secrets are\n" +
+			"written as `{\"secure\": \"<plaintext>\"}` so that they are re-encrypted when they are imported.\n" +
+			"Secrets are only decrypted if --show-secrets is passed, and exports containing blinded secrets\n" +
+			"cannot be imported.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			// Check the format before anything is written, so that a mistyped format does not truncate the file.
+			if err := validateExportFormat(format); err != nil {
+				return err
+			}
+
+			opts := backend.DisplayOptions{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(*stack, true, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+
+			ps, err := workspace.DetectProjectStack(s.Name().StackName())
+			if err != nil {
+				return err
+			}
+
+			decrypter := config.NewBlindingDecrypter()
+			if showSecrets {
+				decrypter = stackStateCrypter(s)
+			}
+
+			writer := os.Stdout
+			if file != "" {
+				// The configuration may contain decrypted secrets, so keep the file private to the current user.
+				writer, err = os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+				if err != nil {
+					return errors.Wrap(err, "could not open file")
+				}
+				defer contract.IgnoreClose(writer)
+			}
+
+			return exportConfig(writer, ps.Config, decrypter, format)
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&file, "file", "", "", "A filename to write the configuration to")
+	cmd.PersistentFlags().StringVarP(
+		&format, "format", "f", "json", "The format to write the configuration in: json, yaml, or env")
+	cmd.PersistentFlags().BoolVar(
+		&showSecrets, "show-secrets", false,
+		"Decrypt secret values instead of writing blinded values")
+
+	return cmd
+}
+
+// exportConfig writes the given configuration to w in the given format, decrypting secrets with decrypter.
+func exportConfig(w io.Writer, cfg config.Map, decrypter config.Decrypter, format string) error {
+	switch format {
+	case "json", "yaml":
+		// Store the decrypted secrets in place of their ciphertexts, so that they can be re-encrypted on import.
+		plaintexts := make(config.Map)
+		for key, value := range cfg {
+			plaintext, err := value.Copy(decrypter, config.NopEncrypter)
+			if err != nil {
+				return errors.Wrapf(err, "could not decrypt configuration value '%s'", key)
+			}
+			plaintexts[key] = plaintext
+		}
+
+		var b []byte
+		var err error
+		if format == "json" {
+			if b, err = json.MarshalIndent(plaintexts, "", "    "); err == nil {
+				b = append(b, '\n')
+			}
+		} else {
+			b, err = yaml.Marshal(plaintexts)
+		}
+		if err != nil {
+			return errors.Wrap(err, "could not export configuration")
+		}
+		_, err = w.Write(b)
+		return err
+	case "env":
+		var keys config.KeyArray
+		for key := range cfg {
+			keys = append(keys, key)
+		}
+		sort.Sort(keys)
+		for _, key := range keys {
+			value, err := cfg[key].Value(decrypter)
+			if err != nil {
+				return errors.Wrapf(err, "could not decrypt configuration value '%s'", key)
+			}
+			if _, err = fmt.Fprintf(w, "%s=%s\n", configEnvVarName(key), shellQuote(value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return errors.Errorf("unknown format '%s'; supported formats are: env, json, yaml", format)
+	}
+}
+
+// validateExportFormat returns an error if the given format is not one that configuration can be exported in.
+func validateExportFormat(format string) error {
+	switch format {
+	case "json", "yaml", "env":
+		return nil
+	default:
+		return errors.Errorf("unknown format '%s'; supported formats are: env, json, yaml", format)
+	}
+}
+
+// configEnvVarName returns the environment variable name for a configuration key, e.g. AWS_REGION for aws:region.
+func configEnvVarName(key config.Key) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key.Namespace()+"_"+key.Name())
+}
+
+// shellQuote quotes a string so that a POSIX shell reads it back verbatim.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,92 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func TestExportImportConfig(t *testing.T) {
+	srcCrypter := config.NewSymmetricCrypterFromPassphrase("src", []byte("salt"))
+	dstCrypter := config.NewSymmetricCrypterFromPassphrase("dst", []byte("salt"))
+
+	ciphertext, err := srcCrypter.EncryptValue("hunter2")
+	assert.NoError(t, err)
+
+	region, password := config.MustMakeKey("aws", "region"), config.MustMakeKey("test", "db-password")
+	cfg := config.Map{
+		region:   config.NewValue("us-west-2"),
+		password: config.NewSecureValue(ciphertext),
+	}
+
+	var env bytes.Buffer
+	assert.NoError(t, exportConfig(&env, cfg, srcCrypter, "env"))
+	assert.Equal(t, "AWS_REGION='us-west-2'\nTEST_DB_PASSWORD='hunter2'\n", env.String())
+
+	assert.Error(t, exportConfig(&env, cfg, srcCrypter, "xml"))
+
+	for _, format := range []string{"json", "yaml"} {
+		var exported bytes.Buffer
+		assert.NoError(t, exportConfig(&exported, cfg, srcCrypter, format))
+
+		var plaintexts config.Map
+		assert.NoError(t, yaml.Unmarshal(exported.Bytes(), &plaintexts))
+
+		imported := make(config.Map)
+		assert.NoError(t, importConfig(imported, plaintexts, dstCrypter))
+		assert.Equal(t, config.NewValue("us-west-2"), imported[region])
+		v, err := imported[password].Value(dstCrypter)
+		assert.NoError(t, err)
+		assert.Equal(t, "hunter2", v)
+
+		// Blinded secrets cannot be imported.
+		var blinded bytes.Buffer
+		assert.NoError(t, exportConfig(&blinded, cfg, config.NewBlindingDecrypter(), format))
+		assert.NoError(t, yaml.Unmarshal(blinded.Bytes(), &plaintexts))
+		assert.Error(t, importConfig(make(config.Map), plaintexts, dstCrypter))
+	}
+}
+
+func TestCopyConfig(t *testing.T) {
+	srcCrypter := config.NewSymmetricCrypterFromPassphrase("src", []byte("salt"))
+	dstCrypter := config.NewSymmetricCrypterFromPassphrase("dst", []byte("salt"))
+
+	ciphertext, err := srcCrypter.EncryptValue("hunter2")
+	assert.NoError(t, err)
+
+	key := config.MustMakeKey("test", "password")
+	values := config.Map{key: config.NewSecureValue(ciphertext)}
+
+	// Secrets are re-encrypted for the destination.
+	dst := &workspace.ProjectStack{}
+	assert.NoError(t, copyConfig(values, dst, srcCrypter, dstCrypter))
+	v, err := dst.Config[key].Value(dstCrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", v)
+
+	// Stacks that share their secrets settings can use each other's ciphertexts.
+	src := &workspace.ProjectStack{EncryptionSalt: "v1:salt"}
+	assert.True(t, sameSecretsSettings(src, &workspace.ProjectStack{EncryptionSalt: "v1:salt"}))
+	assert.False(t, sameSecretsSettings(src, &workspace.ProjectStack{EncryptionSalt: "v1:other"}))
+	assert.False(t, sameSecretsSettings(src, &workspace.ProjectStack{EncryptionSalt: "v1:salt", SecretsProvider: "x"}))
+	assert.False(t, sameSecretsSettings(&workspace.ProjectStack{}, &workspace.ProjectStack{}))
+}
@@ -0,0 +1,96 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// blindedSecret is the value that secrets are replaced with when configuration is exported without decrypting them.
+const blindedSecret = "[secret]"
+
+func newConfigImportCmd(stack *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Import configuration values from a file into a stack",
+		Long: "Import configuration values from a file into a stack.\n" +
+			"\n" +
+			"The file may be in the JSON or YAML format written by `pulumi config export`. Each value in\n" +
+			"the file is set in the stack's configuration, replacing any existing value with the same key.\n" +
+			"Secrets, written as `{\"secure\": \"<plaintext>\"}`, are encrypted with the stack's secrets provider.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := backend.DisplayOptions{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(*stack, true, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+
+			b, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return errors.Wrap(err, "could not read file")
+			}
+
+			// JSON is a subset of YAML, so a single decoder handles both formats.
+			var plaintexts config.Map
+			if err = yaml.Unmarshal(b, &plaintexts); err != nil {
+				return errors.Wrap(err, "could not parse configuration")
+			}
+
+			ps, err := workspace.DetectProjectStack(s.Name().StackName())
+			if err != nil {
+				return err
+			}
+			if err = importConfig(ps.Config, plaintexts, stackStateCrypter(s)); err != nil {
+				return err
+			}
+			return workspace.SaveProjectStack(s.Name().StackName(), ps)
+		}),
+	}
+
+	return cmd
+}
+
+// importConfig encrypts the secrets in each of the given plaintext values and stores the result in the given
+// configuration. Secrets that were blinded when they were exported are rejected.
+func importConfig(cfg config.Map, plaintexts config.Map, encrypter config.Encrypter) error {
+	for key, plaintext := range plaintexts {
+		secrets, err := plaintext.SecureValues(config.NopDecrypter)
+		if err != nil {
+			return errors.Wrapf(err, "invalid configuration value '%s'", key)
+		}
+		for _, secret := range secrets {
+			if secret == blindedSecret {
+				return errors.Errorf(
+					"configuration value '%s' contains a blinded secret; rerun `pulumi config export` "+
+						"with --show-secrets", key)
+			}
+		}
+	}
+
+	return encryptSecrets(cfg, plaintexts, encrypter)
+}
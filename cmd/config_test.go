@@ -19,6 +19,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/workspace"
@@ -41,3 +42,52 @@ func TestSecretDetection(t *testing.T) {
 	// The key name does not match the, so even though this "looks like" a secret, we say it is not.
 	assert.False(t, looksLikeSecret(config.MustMakeKey("test", "okay"), "1415fc1f4eaeb5e096ee58c1480016638fff29bf"))
 }
+
+func TestConfigPaths(t *testing.T) {
+	parsePath := func(path string) resource.PropertyPath {
+		p, err := resource.ParsePropertyPath(path)
+		assert.NoError(t, err)
+		return p
+	}
+
+	// Setting a path creates any missing objects and arrays along the way.
+	v, err := setConfigPath(config.Value{}, false, parsePath("subnets[0]"), config.NewValue("10.0.0.0/24"))
+	assert.NoError(t, err)
+	assert.Equal(t, config.NewObjectValue(`{"subnets":["10.0.0.0/24"]}`), v)
+
+	// Arrays may be extended by one element at a time.
+	v, err = setConfigPath(v, true, parsePath("subnets[1]"), config.NewValue("10.0.1.0/24"))
+	assert.NoError(t, err)
+	_, err = setConfigPath(v, true, parsePath("subnets[3]"), config.NewValue("10.0.3.0/24"))
+	assert.Error(t, err)
+
+	// Secrets are stored encrypted at the leaves.
+	v, err = setConfigPath(v, true, parsePath(`tags["db password"]`), config.NewSecureValue("ciphertext"))
+	assert.NoError(t, err)
+	assert.Equal(t, config.NewSecureObjectValue(
+		`{"subnets":["10.0.0.0/24","10.0.1.0/24"],"tags":{"db password":{"secure":"ciphertext"}}}`), v)
+
+	raw, err := v.Value(config.NewBlindingDecrypter())
+	assert.NoError(t, err)
+	s, err := getConfigPath(raw, parsePath("subnets[1]"))
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.1.0/24", s)
+	s, err = getConfigPath(raw, parsePath("tags"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"db password":"[secret]"}`, s)
+	_, err = getConfigPath(raw, parsePath("subnets[2]"))
+	assert.Error(t, err)
+
+	// Removing the only secret leaves a plain object; removing array elements splices them out.
+	v, err = removeConfigPath(v, parsePath(`tags["db password"]`))
+	assert.NoError(t, err)
+	v, err = removeConfigPath(v, parsePath("subnets[0]"))
+	assert.NoError(t, err)
+	assert.Equal(t, config.NewObjectValue(`{"subnets":["10.0.1.0/24"],"tags":{}}`), v)
+	_, err = removeConfigPath(v, parsePath("missing"))
+	assert.Error(t, err)
+
+	// Plain string values cannot be indexed into.
+	_, err = setConfigPath(config.NewValue("plain"), true, parsePath("x"), config.NewValue("y"))
+	assert.Error(t, err)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/backend"
 	"github.com/pulumi/pulumi/pkg/engine"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
 )
 
 func newDestroyCmd() *cobra.Command {
@@ -34,10 +35,13 @@ func newDestroyCmd() *cobra.Command {
 	// Flags for engine.UpdateOptions.
 	var analyzers []string
 	var diffDisplay bool
+	var eventLogPath string
 	var parallel int
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
+	var targets []string
+	var targetDependents bool
 	var nonInteractive bool
 	var skipPreview bool
 	var yes bool
@@ -76,6 +80,15 @@ func newDestroyCmd() *cobra.Command {
 				Debug:                debug,
 			}
 
+			if eventLogPath != "" {
+				eventLog, logErr := backend.NewEventLog(eventLogPath)
+				if logErr != nil {
+					return logErr
+				}
+				defer contract.IgnoreClose(eventLog)
+				opts.Display.EventLog = eventLog
+			}
+
 			s, err := requireStack(stack, false, opts.Display, true /*setCurrent*/)
 			if err != nil {
 				return err
@@ -91,9 +104,11 @@ func newDestroyCmd() *cobra.Command {
 			}
 
 			opts.Engine = engine.UpdateOptions{
-				Analyzers: analyzers,
-				Parallel:  parallel,
-				Debug:     debug,
+				Analyzers:        analyzers,
+				Parallel:         parallel,
+				Debug:            debug,
+				Targets:          targetsToFilters(targets),
+				TargetDependents: targetDependents,
 			}
 
 			_, err = s.Destroy(commandContext(), proj, root, m, opts, cancellationScopes)
@@ -121,6 +136,9 @@ func newDestroyCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&diffDisplay, "diff", false,
 		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().StringVar(
+		&eventLogPath, "event-log", "",
+		"Log every engine event during the destroy to the given file as newline-delimited JSON")
 	cmd.PersistentFlags().BoolVar(
 		&nonInteractive, "non-interactive", false, "Disable interactive mode")
 	cmd.PersistentFlags().IntVarP(
@@ -135,6 +153,13 @@ func newDestroyCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&showSames, "show-sames", false,
 		"Show resources that don't need to be updated because they haven't changed, alongside those that do")
+	cmd.PersistentFlags().StringArrayVarP(
+		&targets, "target", "t", []string{},
+		"Restrict the destroy to the resources matching the given URN, '<type>::<name>', or name. "+
+			"Multiple resources can be targeted by passing the flag multiple times")
+	cmd.PersistentFlags().BoolVar(
+		&targetDependents, "target-dependents", false,
+		"Also target any resources that depend on the targeted resources")
 	cmd.PersistentFlags().BoolVar(
 		&skipPreview, "skip-preview", false,
 		"Do not perform a preview before performing the destroy")
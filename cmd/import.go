@@ -0,0 +1,164 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+func newImportCmd() *cobra.Command {
+	var debug bool
+	var message string
+	var stack string
+
+	// Flags for engine.UpdateOptions.
+	var analyzers []string
+	var diffDisplay bool
+	var eventLogPath string
+	var nonInteractive bool
+	var parallel int
+	var showConfig bool
+	var skipPreview bool
+	var yes bool
+
+	var cmd = &cobra.Command{
+		Use:   "import <type> <name> <id>",
+		Short: "Import an existing resource into a stack",
+		Long: "Import an existing resource into a stack.\n" +
+			"\n" +
+			"This command brings an existing resource under the management of a stack. The resource is identified\n" +
+			"by the type and name that the current Pulumi program uses to register it, and by the ID that its\n" +
+			"provider uses to refer to it. The program is run as it would be for an update, but only the imported\n" +
+			"resource is modified: rather than being created, its current state is read from its provider.\n" +
+			"\n" +
+			"The properties that the program specifies for the resource must match the resource's current state.\n" +
+			"If they do not, the import fails; update the program to describe the resource as it exists and try\n" +
+			"again. Once imported, the resource is managed like any other: subsequent updates may change it, and\n" +
+			"`pulumi destroy` will delete it.",
+		Args: cmdutil.ExactArgs(3),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			typ, name, id := tokens.Type(args[0]), tokens.QName(args[1]), resource.ID(args[2])
+
+			interactive := isInteractive(nonInteractive)
+			if !interactive {
+				yes = true // auto-approve changes, since we cannot prompt.
+			}
+
+			opts, err := updateFlagsToOptions(interactive, skipPreview, yes)
+			if err != nil {
+				return err
+			}
+
+			opts.Display = backend.DisplayOptions{
+				Color:         cmdutil.GetGlobalColorization(),
+				ShowConfig:    showConfig,
+				IsInteractive: interactive,
+				DiffDisplay:   diffDisplay,
+				Debug:         debug,
+			}
+
+			if eventLogPath != "" {
+				eventLog, logErr := backend.NewEventLog(eventLogPath)
+				if logErr != nil {
+					return logErr
+				}
+				defer contract.IgnoreClose(eventLog)
+				opts.Display.EventLog = eventLog
+			}
+
+			s, err := requireStack(stack, true, opts.Display, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+
+			proj, root, err := readProject()
+			if err != nil {
+				return err
+			}
+
+			m, err := getUpdateMetadata(message, root)
+			if err != nil {
+				return errors.Wrap(err, "gathering environment metadata")
+			}
+
+			// Restrict the update to the imported resource so that any other pending changes are left alone.
+			opts.Engine = engine.UpdateOptions{
+				Analyzers: analyzers,
+				Parallel:  parallel,
+				Debug:     debug,
+				Targets:   targetsToFilters([]string{string(typ) + "::" + string(name)}),
+				Imports:   []deploy.Import{{Type: typ, Name: name, ID: id}},
+			}
+
+			_, err = s.Update(commandContext(), proj, root, m, opts, cancellationScopes)
+			switch {
+			case err == context.Canceled:
+				return errors.New("import cancelled")
+			case err != nil:
+				return PrintEngineError(err)
+			default:
+				return nil
+			}
+		}),
+	}
+
+	cmd.PersistentFlags().BoolVarP(
+		&debug, "debug", "d", false,
+		"Print detailed debugging output during resource operations")
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringVarP(
+		&message, "message", "m", "",
+		"Optional message to associate with the import operation")
+
+	// Flags for engine.UpdateOptions.
+	cmd.PersistentFlags().StringSliceVar(
+		&analyzers, "analyzer", []string{},
+		"Run one or more analyzers as part of this import")
+	cmd.PersistentFlags().BoolVar(
+		&diffDisplay, "diff", false,
+		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().StringVar(
+		&eventLogPath, "event-log", "",
+		"Log every engine event during the import to the given file as newline-delimited JSON")
+	cmd.PersistentFlags().BoolVar(
+		&nonInteractive, "non-interactive", false, "Disable interactive mode")
+	cmd.PersistentFlags().IntVarP(
+		&parallel, "parallel", "p", 10,
+		"Allow P resource operations to run in parallel at once (<=1 for no parallelism)")
+	cmd.PersistentFlags().BoolVar(
+		&showConfig, "show-config", false,
+		"Show configuration keys and variables")
+	cmd.PersistentFlags().BoolVar(
+		&skipPreview, "skip-preview", false,
+		"Do not perform a preview before performing the import")
+	cmd.PersistentFlags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Automatically approve and perform the import after previewing it")
+
+	return cmd
+}
@@ -31,8 +31,15 @@ func newLoginCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "login",
 		Short: "Log into the Pulumi Cloud",
-		Long:  "Log into the Pulumi Cloud.  You can script by using PULUMI_ACCESS_TOKEN environment variable.",
-		Args:  cmdutil.NoArgs,
+		Long: "Log into the Pulumi Cloud.  You can script by using PULUMI_ACCESS_TOKEN environment variable.\n" +
+			"\n" +
+			"To manage stacks without the Pulumi Cloud, pass a URL to --cloud-url that names where their\n" +
+			"state should be kept instead: local://<path> or file://<path> for a directory on this machine,\n" +
+			"or s3://<bucket>[/<prefix>] for a bucket in an S3-compatible object store. S3 URLs accept\n" +
+			"region=<region> and endpoint=<url> query parameters; the latter may refer to any S3-compatible\n" +
+			"service, such as a MinIO server. Credentials are read from the standard AWS_ACCESS_KEY_ID and\n" +
+			"AWS_SECRET_ACCESS_KEY environment variables.",
+		Args: cmdutil.NoArgs,
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			displayOptions := backend.DisplayOptions{
 				Color: cmdutil.GetGlobalColorization(),
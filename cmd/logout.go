@@ -18,6 +18,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/pulumi/pulumi/pkg/backend"
 	"github.com/pulumi/pulumi/pkg/backend/cloud"
 	"github.com/pulumi/pulumi/pkg/backend/local"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
@@ -41,11 +42,13 @@ func newLogoutCmd() *cobra.Command {
 				cloudURL = creds.Current
 			}
 
+			var b backend.Backend
+			var err error
 			if local.IsLocalBackendURL(cloudURL) {
-				return local.New(cmdutil.Diag(), cloudURL).Logout()
+				b, err = local.New(cmdutil.Diag(), cloudURL)
+			} else {
+				b, err = cloud.New(cmdutil.Diag(), cloudURL)
 			}
-
-			b, err := cloud.New(cmdutil.Diag(), cloudURL)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,37 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newPolicyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Check stacks against resource policies",
+		Long: "Check stacks against resource policies.\n" +
+			"\n" +
+			"Policies are enforced by analyzer plugins, which are normally run against each resource\n" +
+			"during a preview or update. Subcommands of this command run analyzers outside of an update.",
+		Args: cmdutil.NoArgs,
+	}
+
+	cmd.AddCommand(newPolicyCheckCmd())
+
+	return cmd
+}
@@ -0,0 +1,251 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func newPolicyCheckCmd() *cobra.Command {
+	var analyzerNames []string
+	var file string
+	var jsonOut bool
+	var stackName string
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Args:  cmdutil.NoArgs,
+		Short: "Check a stack's resources against resource policies",
+		Long: "Check a stack's resources against resource policies.\n" +
+			"\n" +
+			"This command runs analyzers against every resource in a stack's current state, and reports\n" +
+			"any policy violations that they find. No program is run and no resource providers are loaded,\n" +
+			"so no cloud credentials are required. The analyzers listed in Pulumi.yaml are run, along with\n" +
+			"any given by --analyzer, and the policy enforcement levels in Pulumi.yaml are applied.\n" +
+			"\n" +
+			"By default, the current stack's state is checked. Alternatively, a deployment written by\n" +
+			"`pulumi stack export` may be checked using --file; in that case a project is not required, and\n" +
+			"any secrets in the deployment are masked.\n" +
+			"\n" +
+			"The command fails if any mandatory policy violations are found.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := backend.DisplayOptions{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if file != "" && stackName != "" {
+				return errors.New("only one of --file and --stack may be specified")
+			}
+
+			// The project supplies analyzers and policy overrides. It is optional when checking a deployment file.
+			proj, root, err := readPolicyProject(file == "")
+			if err != nil {
+				return err
+			}
+
+			var analyzers []tokens.QName
+			if proj != nil && proj.Analyzers != nil {
+				analyzers = append(analyzers, *proj.Analyzers...)
+			}
+			for _, a := range analyzerNames {
+				analyzers = append(analyzers, tokens.QName(a))
+			}
+			if len(analyzers) == 0 {
+				return errors.New("no analyzers to run; list them in Pulumi.yaml or pass --analyzer")
+			}
+
+			// Load the state to check, either from the deployment file or from the stack.
+			var snap *deploy.Snapshot
+			if file != "" {
+				snap, err = readDeploymentFile(file)
+			} else {
+				var s backend.Stack
+				if s, err = requireStack(stackName, false, opts, false /*setCurrent*/); err != nil {
+					return err
+				}
+				snap, err = s.Snapshot(commandContext())
+			}
+			if err != nil {
+				return err
+			}
+
+			// Load the analyzers and run them over the state.
+			var policy *workspace.ProjectPolicy
+			if proj != nil {
+				policy = proj.Policy
+			}
+			violations, err := checkPolicies(root, snap, analyzers, policy)
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				err = printPolicyViolationsJSON(os.Stdout, violations)
+			} else {
+				printPolicyViolations(os.Stdout, violations, opts)
+			}
+			if err != nil {
+				return err
+			}
+
+			var mandatory int
+			for _, v := range violations {
+				if v.EnforcementLevel == workspace.Mandatory {
+					mandatory++
+				}
+			}
+			if mandatory > 0 {
+				return errors.Errorf("%d mandatory policy violation(s) found", mandatory)
+			}
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringSliceVar(
+		&analyzerNames, "analyzer", []string{}, "Run the given analyzer in addition to any listed in Pulumi.yaml")
+	cmd.PersistentFlags().StringVarP(
+		&file, "file", "", "", "Check the deployment in the given file, as written by `pulumi stack export`")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonOut, "json", "j", false, "Emit policy violations as JSON")
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+
+	return cmd
+}
+
+// readPolicyProject reads the project for the current workspace. If the project is not required and there is none,
+// it returns a nil project rooted at the current working directory.
+func readPolicyProject(required bool) (*workspace.Project, string, error) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return nil, "", err
+	}
+	if !required {
+		if path, err := workspace.DetectProjectPathFrom(pwd); err == nil && path == "" {
+			return nil, pwd, nil
+		}
+	}
+	return readProject()
+}
+
+// readDeploymentFile reads a deployment written by `pulumi stack export` from the given file. Secrets are masked.
+func readDeploymentFile(file string) (*deploy.Snapshot, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read deployment file")
+	}
+
+	var deployment apitype.UntypedDeployment
+	if err = json.Unmarshal(b, &deployment); err != nil {
+		return nil, errors.Wrap(err, "could not read deployment file")
+	}
+	snap, err := stack.DeserializeUntypedDeployment(&deployment, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not deserialize deployment file")
+	}
+	return snap, nil
+}
+
+// checkPolicies loads the named analyzers and runs them over the resources in the given snapshot, applying the given
+// policy's enforcement levels. Only the analyzer plugins themselves are launched.
+func checkPolicies(pwd string, snap *deploy.Snapshot, names []tokens.QName,
+	policy *workspace.ProjectPolicy) ([]deploy.PolicyViolation, error) {
+
+	plugctx, err := plugin.NewContext(cmdutil.Diag(), nil, nil, nil, pwd, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer contract.IgnoreClose(plugctx)
+
+	var analyzers []plugin.Analyzer
+	for _, name := range names {
+		a, err := plugctx.Host.Analyzer(name)
+		if err != nil {
+			return nil, err
+		} else if a == nil {
+			return nil, errors.Errorf("analyzer '%v' could not be loaded from your $PATH", name)
+		}
+		analyzers = append(analyzers, a)
+	}
+
+	return deploy.AnalyzeSnapshot(snap, analyzers, policy)
+}
+
+// printPolicyViolations prints the given policy violations in a human-readable form.
+func printPolicyViolations(w io.Writer, violations []deploy.PolicyViolation, opts backend.DisplayOptions) {
+	if len(violations) == 0 {
+		fmt.Fprintln(w, "No policy violations found.")
+		return
+	}
+
+	fmt.Fprintln(w, opts.Color.Colorize(
+		fmt.Sprintf("%v%d policy violation(s) found:%v", colors.SpecWarning, len(violations), colors.Reset)))
+	for _, v := range violations {
+		policy := v.PolicyName
+		if policy == "" {
+			policy = string(v.Analyzer)
+		}
+		if v.Severity != "" {
+			policy = fmt.Sprintf("%s (%s severity)", policy, v.Severity)
+		}
+		fmt.Fprintf(w, "    [%v] %v: %v\n", v.EnforcementLevel, policy, v.Reason)
+		if v.URN != "" {
+			if v.Property != "" {
+				fmt.Fprintf(w, "        %v (property %v)\n", v.URN, v.Property)
+			} else {
+				fmt.Fprintf(w, "        %v\n", v.URN)
+			}
+		}
+	}
+}
+
+// printPolicyViolationsJSON prints the given policy violations as a JSON array.
+func printPolicyViolationsJSON(w io.Writer, violations []deploy.PolicyViolation) error {
+	jsonViolations := []apitype.PolicyViolation{}
+	for _, v := range violations {
+		jsonViolations = append(jsonViolations, apitype.PolicyViolation{
+			Analyzer:         string(v.Analyzer),
+			PolicyName:       v.PolicyName,
+			Description:      v.Description,
+			Severity:         v.Severity,
+			EnforcementLevel: string(v.EnforcementLevel),
+			URN:              string(v.URN),
+			Property:         string(v.Property),
+			Reason:           v.Reason,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return errors.Wrap(enc.Encode(jsonViolations), "could not write policy violations")
+}
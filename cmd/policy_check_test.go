@@ -0,0 +1,120 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func TestReadDeploymentFile(t *testing.T) {
+	urn := resource.NewURN("dev", "proj", "", "pkgA:m:typA", "resA")
+	snap := deploy.NewSnapshot(deploy.Manifest{}, []*resource.State{
+		{Type: "pkgA:m:typA", URN: urn, Custom: true, ID: "a", Outputs: resource.PropertyMap{
+			"public": resource.NewBoolProperty(true),
+		}},
+	}, nil)
+	deployment, err := stack.SerializeDeployment(snap, nil)
+	assert.NoError(t, err)
+	raw, err := json.Marshal(deployment)
+	assert.NoError(t, err)
+	b, err := json.Marshal(apitype.UntypedDeployment{Version: apitype.DeploymentSchemaVersionCurrent, Deployment: raw})
+	assert.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "policy-check")
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, os.RemoveAll(dir)) }()
+	file := filepath.Join(dir, "deployment.json")
+	assert.NoError(t, ioutil.WriteFile(file, b, 0600))
+
+	read, err := readDeploymentFile(file)
+	assert.NoError(t, err)
+	if assert.Len(t, read.Resources, 1) {
+		assert.Equal(t, urn, read.Resources[0].URN)
+		assert.Equal(t, resource.NewBoolProperty(true), read.Resources[0].Outputs["public"])
+	}
+
+	_, err = readDeploymentFile(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestPrintPolicyViolations(t *testing.T) {
+	urn := resource.NewURN("dev", "proj", "", "pkgA:m:typA", "resA")
+	violations := []deploy.PolicyViolation{
+		{Analyzer: "policy", AnalyzeFailure: plugin.AnalyzeFailure{
+			URN:              urn,
+			Property:         "public",
+			Reason:           "resource is public",
+			PolicyName:       "no-public",
+			Severity:         "high",
+			EnforcementLevel: workspace.Mandatory,
+		}},
+		{Analyzer: "policy", AnalyzeFailure: plugin.AnalyzeFailure{
+			Reason:           "stack is too large",
+			EnforcementLevel: workspace.Advisory,
+		}},
+	}
+
+	var text bytes.Buffer
+	printPolicyViolations(&text, violations, backend.DisplayOptions{Color: colors.Never})
+	assert.Equal(t, "2 policy violation(s) found:\n"+
+		"    [mandatory] no-public (high severity): resource is public\n"+
+		"        "+string(urn)+" (property public)\n"+
+		"    [advisory] policy: stack is too large\n", text.String())
+
+	text.Reset()
+	printPolicyViolations(&text, nil, backend.DisplayOptions{Color: colors.Never})
+	assert.Equal(t, "No policy violations found.\n", text.String())
+
+	var out bytes.Buffer
+	assert.NoError(t, printPolicyViolationsJSON(&out, violations))
+	var decoded []apitype.PolicyViolation
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, []apitype.PolicyViolation{
+		{
+			Analyzer:         "policy",
+			PolicyName:       "no-public",
+			Severity:         "high",
+			EnforcementLevel: "mandatory",
+			URN:              string(urn),
+			Property:         "public",
+			Reason:           "resource is public",
+		},
+		{
+			Analyzer:         "policy",
+			EnforcementLevel: "advisory",
+			Reason:           "stack is too large",
+		},
+	}, decoded)
+
+	out.Reset()
+	assert.NoError(t, printPolicyViolationsJSON(&out, nil))
+	assert.Equal(t, "[]\n", out.String())
+}
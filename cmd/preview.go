@@ -21,6 +21,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/backend"
 	"github.com/pulumi/pulumi/pkg/engine"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
 )
 
 func newPreviewCmd() *cobra.Command {
@@ -32,11 +33,16 @@ func newPreviewCmd() *cobra.Command {
 	// Flags for engine.UpdateOptions.
 	var analyzers []string
 	var diffDisplay bool
+	var eventLogPath string
+	var jsonDisplay bool
 	var nonInteractive bool
 	var parallel int
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
+	var targets []string
+	var targetDependents bool
+	var replaces []string
 
 	var cmd = &cobra.Command{
 		Use:        "preview",
@@ -58,9 +64,12 @@ func newPreviewCmd() *cobra.Command {
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			opts := backend.UpdateOptions{
 				Engine: engine.UpdateOptions{
-					Analyzers: analyzers,
-					Parallel:  parallel,
-					Debug:     debug,
+					Analyzers:        analyzers,
+					Parallel:         parallel,
+					Debug:            debug,
+					Targets:          targetsToFilters(targets),
+					TargetDependents: targetDependents,
+					ReplaceTargets:   targetsToFilters(replaces),
 				},
 				Display: backend.DisplayOptions{
 					Color:                cmdutil.GetGlobalColorization(),
@@ -69,10 +78,20 @@ func newPreviewCmd() *cobra.Command {
 					ShowSameResources:    showSames,
 					IsInteractive:        isInteractive(nonInteractive),
 					DiffDisplay:          diffDisplay,
+					JSONDisplay:          jsonDisplay,
 					Debug:                debug,
 				},
 			}
 
+			if eventLogPath != "" {
+				eventLog, logErr := backend.NewEventLog(eventLogPath)
+				if logErr != nil {
+					return logErr
+				}
+				defer contract.IgnoreClose(eventLog)
+				opts.Display.EventLog = eventLog
+			}
+
 			s, err := requireStack(stack, true, opts.Display, true /*setCurrent*/)
 			if err != nil {
 				return err
@@ -121,6 +140,12 @@ func newPreviewCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&diffDisplay, "diff", false,
 		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().StringVar(
+		&eventLogPath, "event-log", "",
+		"Log every engine event during the preview to the given file as newline-delimited JSON")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonDisplay, "json", "j", false,
+		"Serialize the preview steps, diagnostics, outputs, and summary as a single JSON document")
 	cmd.PersistentFlags().BoolVar(
 		&nonInteractive, "non-interactive", false, "Disable interactive mode")
 	cmd.PersistentFlags().IntVarP(
@@ -135,6 +160,17 @@ func newPreviewCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&showSames, "show-sames", false,
 		"Show resources that needn't be updated because they haven't changed, alongside those that do")
+	cmd.PersistentFlags().StringArrayVarP(
+		&targets, "target", "t", []string{},
+		"Restrict the preview to the resources matching the given URN, '<type>::<name>', or name. "+
+			"Multiple resources can be targeted by passing the flag multiple times")
+	cmd.PersistentFlags().BoolVar(
+		&targetDependents, "target-dependents", false,
+		"Also target any resources that depend on the targeted resources")
+	cmd.PersistentFlags().StringArrayVar(
+		&replaces, "replace", []string{},
+		"Force the replacement of the resources matching the given URN, '<type>::<name>', or name during the preview. "+
+			"Multiple resources can be replaced by passing the flag multiple times")
 
 	return cmd
 }
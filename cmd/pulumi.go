@@ -121,14 +121,17 @@ func NewPulumiCmd() *cobra.Command {
 	cmd.AddCommand(newCancelCmd())
 	cmd.AddCommand(newConfigCmd())
 	cmd.AddCommand(newDestroyCmd())
+	cmd.AddCommand(newImportCmd())
 	cmd.AddCommand(newLoginCmd())
 	cmd.AddCommand(newLogoutCmd())
 	cmd.AddCommand(newLogsCmd())
 	cmd.AddCommand(newNewCmd())
 	cmd.AddCommand(newPluginCmd())
+	cmd.AddCommand(newPolicyCmd())
 	cmd.AddCommand(newPreviewCmd())
 	cmd.AddCommand(newRefreshCmd())
 	cmd.AddCommand(newStackCmd())
+	cmd.AddCommand(newStateCmd())
 	cmd.AddCommand(newUpCmd())
 	cmd.AddCommand(newVersionCmd())
 	cmd.AddCommand(newWhoAmICmd())
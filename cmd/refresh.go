@@ -16,13 +16,19 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"os"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/pulumi/pulumi/pkg/backend"
 	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
 )
 
 func newRefreshCmd() *cobra.Command {
@@ -33,7 +39,9 @@ func newRefreshCmd() *cobra.Command {
 	// Flags for engine.UpdateOptions.
 	var analyzers []string
 	var diffDisplay bool
+	var eventLogPath string
 	var parallel int
+	var previewOnly bool
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
@@ -51,6 +59,10 @@ func newRefreshCmd() *cobra.Command {
 			"the program text isn't updated accordingly, subsequent updates may still appear to be out of\n" +
 			"synch with respect to the cloud provider's source of truth.\n" +
 			"\n" +
+			"With `--preview-only`, the stack's state is left untouched. Instead, a report of every resource\n" +
+			"whose actual state has drifted from the stack's state is printed, and the command exits with a\n" +
+			"non-zero status if any drift was found. This makes it suitable for detecting drift on a schedule.\n" +
+			"\n" +
 			"The program to run is loaded from the project in the current directory. Use the `-C` or\n" +
 			"`--cwd` flag to use a different directory.",
 		Args: cmdutil.NoArgs,
@@ -60,10 +72,16 @@ func newRefreshCmd() *cobra.Command {
 				yes = true // auto-approve changes, since we cannot prompt.
 			}
 
-			opts, err := updateFlagsToOptions(interactive, skipPreview, yes)
+			if previewOnly && skipPreview {
+				return errors.New("--preview-only and --skip-preview cannot be used together")
+			}
+
+			// A preview-only refresh never changes anything, so there is nothing to approve.
+			opts, err := updateFlagsToOptions(interactive, skipPreview, yes || previewOnly)
 			if err != nil {
 				return err
 			}
+			opts.PreviewOnly = previewOnly
 
 			opts.Display = backend.DisplayOptions{
 				Color:                cmdutil.GetGlobalColorization(),
@@ -75,6 +93,21 @@ func newRefreshCmd() *cobra.Command {
 				Debug:                debug,
 			}
 
+			if eventLogPath != "" {
+				eventLog, logErr := backend.NewEventLog(eventLogPath)
+				if logErr != nil {
+					return logErr
+				}
+				defer contract.IgnoreClose(eventLog)
+				opts.Display.EventLog = eventLog
+			}
+
+			var drift *backend.DriftReport
+			if previewOnly {
+				drift = backend.NewDriftReport()
+				opts.Display.DriftReport = drift
+			}
+
 			s, err := requireStack(stack, true, opts.Display, true /*setCurrent*/)
 			if err != nil {
 				return err
@@ -99,8 +132,15 @@ func newRefreshCmd() *cobra.Command {
 			_, err = s.Refresh(commandContext(), proj, root, m, opts, cancellationScopes)
 			if err == context.Canceled {
 				return errors.New("refresh cancelled")
+			} else if err != nil || drift == nil {
+				return PrintEngineError(err)
 			}
-			return PrintEngineError(err)
+
+			printDriftReport(os.Stdout, drift)
+			if drift.HasDrift() {
+				return errors.Errorf("%d resource(s) have drifted from the stack's state", len(drift.Resources()))
+			}
+			return nil
 		}),
 	}
 
@@ -122,11 +162,17 @@ func newRefreshCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&diffDisplay, "diff", false,
 		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().StringVar(
+		&eventLogPath, "event-log", "",
+		"Log every engine event during the refresh to the given file as newline-delimited JSON")
 	cmd.PersistentFlags().BoolVar(
 		&nonInteractive, "non-interactive", false, "Disable interactive mode")
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", 10,
 		"Allow P resource operations to run in parallel at once (<=1 for no parallelism)")
+	cmd.PersistentFlags().BoolVar(
+		&previewOnly, "preview-only", false,
+		"Only preview the refresh and report any drift, without changing the stack's state")
 	cmd.PersistentFlags().BoolVar(
 		&showReplacementSteps, "show-replacement-steps", false,
 		"Show detailed resource replacement creates and deletes instead of a single step")
@@ -142,3 +188,43 @@ func newRefreshCmd() *cobra.Command {
 
 	return cmd
 }
+
+// printDriftReport prints a description of each resource recorded by the given drift report.
+func printDriftReport(w io.Writer, drift *backend.DriftReport) {
+	resources := drift.Resources()
+	if len(resources) == 0 {
+		fprintf(w, "No drift detected.\n")
+		return
+	}
+
+	fprintf(w, "Drift detected in %d resource(s):\n", len(resources))
+	for _, res := range resources {
+		fprintf(w, "    %v\n", res.URN)
+		if res.Deleted {
+			fprintf(w, "        resource no longer exists\n")
+			continue
+		}
+		for _, k := range res.Diff.Keys() {
+			switch {
+			case res.Diff.Added(k):
+				fprintf(w, "        + %v: %v\n", k, driftValueString(res.Diff.Adds[k]))
+			case res.Diff.Deleted(k):
+				fprintf(w, "        - %v: %v\n", k, driftValueString(res.Diff.Deletes[k]))
+			case res.Diff.Updated(k):
+				update := res.Diff.Updates[k]
+				fprintf(w, "        ~ %v: %v => %v\n", k, driftValueString(update.Old), driftValueString(update.New))
+			}
+		}
+	}
+}
+
+// driftValueString renders a property value in a drift report. Secret values are masked.
+func driftValueString(v resource.PropertyValue) string {
+	serialized, err := stack.SerializePropertyValue(v, nil)
+	contract.AssertNoError(err) // masking secrets cannot fail.
+	b, err := json.Marshal(serialized)
+	if err != nil {
+		return v.String()
+	}
+	return string(b)
+}
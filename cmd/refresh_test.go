@@ -0,0 +1,56 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+func TestPrintDriftReportMasksSecrets(t *testing.T) {
+	urn := resource.NewURN("test", "test", "", "pkgA:m:typA", "resA")
+	olds := resource.PropertyMap{
+		"password": resource.MakeSecret(resource.NewStringProperty("old-secret")),
+		"size":     resource.NewNumberProperty(1),
+	}
+	news := resource.PropertyMap{
+		"password": resource.MakeSecret(resource.NewStringProperty("new-secret")),
+		"size":     resource.NewNumberProperty(2),
+	}
+
+	drift := backend.NewDriftReport()
+	drift.Record(engine.Event{Type: engine.ResourcePreEvent, Payload: engine.ResourcePreEventPayload{
+		Metadata: engine.StepEventMetadata{
+			Op:  deploy.OpUpdate,
+			URN: urn,
+			Old: &engine.StepEventStateMetadata{URN: urn, Outputs: olds},
+			New: &engine.StepEventStateMetadata{URN: urn, Outputs: news},
+		},
+	}})
+
+	var buf bytes.Buffer
+	printDriftReport(&buf, drift)
+	assert.Contains(t, buf.String(), "~ size: 1 => 2")
+	assert.Contains(t, buf.String(), `~ password: "[secret]" => "[secret]"`)
+	assert.NotContains(t, buf.String(), "old-secret")
+	assert.NotContains(t, buf.String(), "new-secret")
+}
@@ -153,13 +153,19 @@ func newStackCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVarP(
 		&showURNs, "show-urns", "u", false, "Display each resource's Pulumi-assigned globally unique URN")
 
+	cmd.AddCommand(newStackChangeSecretsProviderCmd())
+	cmd.AddCommand(newStackDiffCmd())
 	cmd.AddCommand(newStackExportCmd())
 	cmd.AddCommand(newStackGraphCmd())
+	cmd.AddCommand(newStackHistoryCmd())
 	cmd.AddCommand(newStackImportCmd())
 	cmd.AddCommand(newStackInitCmd())
 	cmd.AddCommand(newStackLsCmd())
 	cmd.AddCommand(newStackOutputCmd())
+	cmd.AddCommand(newStackRenameCmd())
+	cmd.AddCommand(newStackRestoreCmd())
 	cmd.AddCommand(newStackRmCmd())
+	cmd.AddCommand(newStackRollbackCmd())
 	cmd.AddCommand(newStackSelectCmd())
 
 	return cmd
@@ -0,0 +1,212 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func newStackChangeSecretsProviderCmd() *cobra.Command {
+	var stackName string
+	var cmd = &cobra.Command{
+		Use:   "change-secrets-provider <provider>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Change the secrets provider for a stack",
+		Long: "Change the secrets provider for a stack.\n" +
+			"\n" +
+			"The secrets provider encrypts and decrypts the stack's secret configuration values and the\n" +
+			"secret values in its state. Every existing secret is decrypted with the current provider and\n" +
+			"re-encrypted with the new one.\n" +
+			"\n" +
+			"Valid providers are:\n" +
+			"\n" +
+			"  passphrase       encrypt with a passphrase (stacks managed by the local backend only)\n" +
+			"  service          encrypt using the Pulumi service (stacks managed by the service only)\n" +
+			"  exec:<command>   run <command> with an added `encrypt` or `decrypt` argument, passing the\n" +
+			"                   value to transform on stdin and reading the result from stdout\n" +
+			"\n" +
+			"Changing to the passphrase provider always asks for a new passphrase, so this command may\n" +
+			"also be used to change a stack's passphrase.\n" +
+			"\n" +
+			"Only the stack's configuration and its current state are re-encrypted. The earlier checkpoints\n" +
+			"kept in the stack's update history and backups are left as they are, so any secrets they contain\n" +
+			"can still only be decrypted with the old provider.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			provider := args[0]
+
+			opts := backend.DisplayOptions{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(stackName, false, opts, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			name := s.Name().StackName()
+
+			ps, err := workspace.DetectProjectStack(name)
+			if err != nil {
+				return err
+			}
+			original := *ps
+
+			// Decrypt the existing secrets in the stack's configuration and state with the current provider before
+			// switching away from it. The provider is only consulted if there are secrets to decrypt.
+			decrypter := stackStateCrypter(s)
+			plaintexts, err := decryptSecrets(ps.Config, decrypter)
+			if err != nil {
+				return err
+			}
+			deployment, err := s.ExportDeployment(commandContext())
+			if err != nil {
+				return err
+			}
+			snap, err := stack.DeserializeUntypedDeployment(deployment, decrypter)
+			if err != nil {
+				return errors.Wrap(err, "could not decrypt the stack's state")
+			}
+
+			// Fetch the new provider's crypter. Providers keep their own state in the stack's settings, so the new
+			// provider is recorded there while its crypter is obtained. The original settings are then put back until
+			// the stack's state has been re-encrypted, so that the settings never name a provider that cannot decrypt
+			// the stack's current state.
+			ps.SecretsProvider, ps.EncryptionSalt = provider, ""
+			if err = workspace.SaveProjectStack(name, ps); err != nil {
+				return err
+			}
+			encrypter, err := backend.GetStackCrypter(s)
+			if err == nil {
+				// Reload the settings, as the new provider may have stored state of its own.
+				ps, err = workspace.DetectProjectStack(name)
+			}
+			if restoreErr := workspace.SaveProjectStack(name, &original); restoreErr != nil {
+				if err != nil {
+					return errors.Wrapf(err, "restoring the stack's original settings also failed: %v", restoreErr)
+				}
+				return errors.Wrap(restoreErr, "restoring the stack's original settings")
+			}
+			if err != nil {
+				return err
+			}
+
+			// Re-encrypt the stack's secrets and save its state, and only then its settings.
+			if err = encryptSecrets(ps.Config, plaintexts, encrypter); err != nil {
+				return err
+			}
+			hasSecrets := snapshotHasSecrets(snap)
+			if hasSecrets {
+				encrypted, encryptErr := encryptDeployment(snap, encrypter)
+				if encryptErr != nil {
+					return encryptErr
+				}
+				if err = s.ImportDeployment(commandContext(), encrypted); err != nil {
+					return errors.Wrap(err, "could not save the re-encrypted state")
+				}
+			}
+			if err = workspace.SaveProjectStack(name, ps); err != nil {
+				if hasSecrets {
+					// Put back the state as it was, protected by the original provider.
+					if _, rollbackErr := backend.GetStackCrypter(s); rollbackErr != nil {
+						return errors.Wrapf(err, "restoring the stack's original state also failed: %v", rollbackErr)
+					}
+					if rollbackErr := s.ImportDeployment(commandContext(), deployment); rollbackErr != nil {
+						return errors.Wrapf(err, "restoring the stack's original state also failed: %v", rollbackErr)
+					}
+				}
+				return err
+			}
+
+			fmt.Printf("Changed the secrets provider for stack '%s' to '%s'\n", s.Name(), provider)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+
+	return cmd
+}
+
+// decryptSecrets returns a copy of each secure value in the given configuration in which its secrets are stored in
+// plaintext.
+func decryptSecrets(cfg config.Map, decrypter config.Decrypter) (config.Map, error) {
+	plaintexts := make(config.Map)
+	for key, value := range cfg {
+		if !value.Secure() {
+			continue
+		}
+		plaintext, err := value.Copy(decrypter, config.NopEncrypter)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decrypt configuration value '%s'", key)
+		}
+		plaintexts[key] = plaintext
+	}
+	return plaintexts, nil
+}
+
+// encryptSecrets encrypts the secrets in each of the given plaintext values and stores the result in the given
+// configuration.
+func encryptSecrets(cfg config.Map, plaintexts config.Map, encrypter config.Encrypter) error {
+	for key, plaintext := range plaintexts {
+		ciphertext, err := plaintext.Copy(config.NopDecrypter, encrypter)
+		if err != nil {
+			return errors.Wrapf(err, "could not encrypt configuration value '%s'", key)
+		}
+		cfg[key] = ciphertext
+	}
+	return nil
+}
+
+// encryptDeployment serializes the given snapshot as a deployment whose secrets are encrypted with the given encrypter.
+func encryptDeployment(snap *deploy.Snapshot, encrypter config.Encrypter) (*apitype.UntypedDeployment, error) {
+	serialized, err := stack.SerializeDeployment(snap, encrypter)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encrypt the stack's state")
+	}
+	bytes, err := json.Marshal(serialized)
+	if err != nil {
+		return nil, err
+	}
+	return &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: bytes,
+	}, nil
+}
+
+// snapshotHasSecrets returns true if any resource in the given snapshot has a secret input or output.
+func snapshotHasSecrets(snap *deploy.Snapshot) bool {
+	if snap == nil {
+		return false
+	}
+	for _, res := range snap.Resources {
+		if res.Inputs.ContainsSecrets() || res.Outputs.ContainsSecrets() {
+			return true
+		}
+	}
+	return false
+}
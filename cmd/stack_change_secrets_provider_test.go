@@ -0,0 +1,102 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+)
+
+func TestReencryptSecrets(t *testing.T) {
+	oldCrypter := config.NewSymmetricCrypterFromPassphrase("old", []byte("salt"))
+	newCrypter := config.NewSymmetricCrypterFromPassphrase("new", []byte("salt"))
+
+	ciphertext, err := oldCrypter.EncryptValue("hunter2")
+	assert.NoError(t, err)
+
+	plain, secret := config.MustMakeKey("test", "plain"), config.MustMakeKey("test", "secret")
+	cfg := config.Map{
+		plain:  config.NewValue("value"),
+		secret: config.NewSecureValue(ciphertext),
+	}
+
+	plaintexts, err := decryptSecrets(cfg, oldCrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, config.Map{secret: config.NewSecureValue("hunter2")}, plaintexts)
+
+	assert.NoError(t, encryptSecrets(cfg, plaintexts, newCrypter))
+	assert.Equal(t, config.NewValue("value"), cfg[plain])
+	assert.True(t, cfg[secret].Secure())
+
+	_, err = cfg[secret].Value(oldCrypter)
+	assert.Error(t, err)
+	v, err := cfg[secret].Value(newCrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", v)
+
+	// Values that cannot be decrypted with the current provider are reported.
+	_, err = decryptSecrets(cfg, oldCrypter)
+	assert.Error(t, err)
+}
+
+func TestReencryptDeployment(t *testing.T) {
+	oldCrypter := config.NewSymmetricCrypterFromPassphrase("old", []byte("salt"))
+	newCrypter := config.NewSymmetricCrypterFromPassphrase("new", []byte("salt"))
+
+	secret := resource.MakeSecret(resource.NewStringProperty("hunter2"))
+	urn := resource.NewURN("test", "test", "", "pkgA:m:typA", "resA")
+	snap := deploy.NewSnapshot(deploy.Manifest{}, []*resource.State{{
+		Type:    urn.Type(),
+		URN:     urn,
+		Custom:  true,
+		ID:      "id",
+		Inputs:  resource.PropertyMap{"password": secret},
+		Outputs: resource.PropertyMap{"password": secret, "plain": resource.NewStringProperty("value")},
+	}}, nil)
+
+	// Export the stack's state as it was encrypted by the old provider, and decrypt it again.
+	exported, err := encryptDeployment(snap, oldCrypter)
+	assert.NoError(t, err)
+	decrypted, err := stack.DeserializeUntypedDeployment(exported, oldCrypter)
+	assert.NoError(t, err)
+	assert.True(t, snapshotHasSecrets(decrypted))
+
+	// Once re-encrypted, the state can only be read with the new provider.
+	reencrypted, err := encryptDeployment(decrypted, newCrypter)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(reencrypted.Deployment), "hunter2")
+
+	_, err = stack.DeserializeUntypedDeployment(reencrypted, oldCrypter)
+	assert.Error(t, err)
+	imported, err := stack.DeserializeUntypedDeployment(reencrypted, newCrypter)
+	assert.NoError(t, err)
+	if assert.Len(t, imported.Resources, 1) {
+		assert.Equal(t, secret, imported.Resources[0].Inputs["password"])
+		assert.Equal(t, secret, imported.Resources[0].Outputs["password"])
+		assert.Equal(t, resource.NewStringProperty("value"), imported.Resources[0].Outputs["plain"])
+	}
+
+	// Stacks without secrets in their state need not be re-encrypted.
+	assert.False(t, snapshotHasSecrets(nil))
+	assert.False(t, snapshotHasSecrets(deploy.NewSnapshot(deploy.Manifest{}, []*resource.State{{
+		Type: urn.Type(), URN: urn, Outputs: resource.PropertyMap{"plain": resource.NewStringProperty("value")},
+	}}, nil)))
+}
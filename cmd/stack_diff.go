@@ -0,0 +1,184 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStackDiffCmd() *cobra.Command {
+	var stackName string
+	cmd := &cobra.Command{
+		Use:   "diff <version-a> <version-b>",
+		Args:  cmdutil.ExactArgs(2),
+		Short: "Compare the state of a stack at two versions",
+		Long: "Compare the state of a stack at two versions.\n" +
+			"\n" +
+			"This command loads the stack's checkpoint as of each of the given versions, as listed by\n" +
+			"`pulumi stack history`, and shows the resources that were added, removed, or changed\n" +
+			"between them. For each changed resource, the input and output properties that were\n" +
+			"added, removed, or updated are shown.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := backend.DisplayOptions{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			var versions [2]int
+			for i, arg := range args {
+				v, err := strconv.Atoi(arg)
+				if err != nil || v < 1 {
+					return errors.Errorf("invalid version '%s'; versions are positive integers", arg)
+				}
+				versions[i] = v
+			}
+
+			s, err := requireStack(stackName, false, opts, false /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+
+			var snaps [2]*deploy.Snapshot
+			for i, version := range versions {
+				deployment, err := s.Backend().ExportDeploymentVersion(commandContext(), s.Name(), version)
+				if err != nil {
+					return errors.Wrapf(err, "loading version %d", version)
+				}
+				if snaps[i], err = stack.DeserializeUntypedDeployment(deployment, nil /*mask secrets*/); err != nil {
+					return errors.Wrapf(err, "loading version %d", version)
+				}
+			}
+
+			diffs := diffSnapshots(snaps[0], snaps[1])
+			if len(diffs) == 0 {
+				fmt.Printf("No resources changed between versions %d and %d\n", versions[0], versions[1])
+				return nil
+			}
+			for _, diff := range diffs {
+				printResourceDiff(diff, opts)
+			}
+			return nil
+		}),
+	}
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+	return cmd
+}
+
+// resourceDiff describes how a single resource differs between two snapshots.
+type resourceDiff struct {
+	URN     resource.URN
+	Op      deploy.StepOp        // OpCreate if the resource was added, OpDelete if removed, or OpUpdate if changed
+	Inputs  *resource.ObjectDiff // for changed resources, the difference in inputs, or nil if they are the same
+	Outputs *resource.ObjectDiff // for changed resources, the difference in outputs, or nil if they are the same
+}
+
+// diffSnapshots compares the resources in two snapshots by URN. Resources in the new snapshot are reported in order,
+// followed by those that only exist in the old one. Resources whose properties did not change are omitted.
+func diffSnapshots(olds, news *deploy.Snapshot) []resourceDiff {
+	oldResources, oldURNs := snapshotResources(olds)
+	newResources, newURNs := snapshotResources(news)
+
+	var diffs []resourceDiff
+	for _, urn := range newURNs {
+		newRes := newResources[urn]
+		oldRes, has := oldResources[urn]
+		if !has {
+			diffs = append(diffs, resourceDiff{URN: urn, Op: deploy.OpCreate})
+			continue
+		}
+
+		inputs, outputs := oldRes.Inputs.Diff(newRes.Inputs), oldRes.Outputs.Diff(newRes.Outputs)
+		if inputs != nil || outputs != nil {
+			diffs = append(diffs, resourceDiff{URN: urn, Op: deploy.OpUpdate, Inputs: inputs, Outputs: outputs})
+		}
+	}
+	for _, urn := range oldURNs {
+		if _, has := newResources[urn]; !has {
+			diffs = append(diffs, resourceDiff{URN: urn, Op: deploy.OpDelete})
+		}
+	}
+	return diffs
+}
+
+// snapshotResources indexes the resources in a snapshot by URN, also returning the URNs in order. If a resource
+// appears more than once, e.g. because a replaced copy is pending deletion, the live copy is preferred.
+func snapshotResources(snap *deploy.Snapshot) (map[resource.URN]*resource.State, []resource.URN) {
+	resources := make(map[resource.URN]*resource.State)
+	var urns []resource.URN
+	if snap == nil {
+		return resources, urns
+	}
+	for _, res := range snap.Resources {
+		existing, has := resources[res.URN]
+		if !has {
+			urns = append(urns, res.URN)
+		}
+		if !has || existing.Delete {
+			resources[res.URN] = res
+		}
+	}
+	return resources, urns
+}
+
+// printResourceDiff prints a resource that differs between two snapshots, along with its changed properties.
+func printResourceDiff(diff resourceDiff, opts backend.DisplayOptions) {
+	fmt.Print(opts.Color.Colorize(fmt.Sprintf("%s%s%s\n", diff.Op.Prefix(), diff.URN, colors.Reset)))
+	printPropertyDiff("inputs", diff.Inputs, opts)
+	printPropertyDiff("outputs", diff.Outputs, opts)
+}
+
+// printPropertyDiff prints the top-level properties that were added, removed, or updated in a property map.
+func printPropertyDiff(title string, diff *resource.ObjectDiff, opts backend.DisplayOptions) {
+	if diff == nil {
+		return
+	}
+
+	fmt.Printf("    %s:\n", title)
+	for _, k := range diff.Keys() {
+		var line string
+		if add, isadd := diff.Adds[k]; isadd {
+			line = fmt.Sprintf("%s%s: %s", deploy.OpCreate.Prefix(), k, stringifyPropertyValue(add))
+		} else if del, isdel := diff.Deletes[k]; isdel {
+			line = fmt.Sprintf("%s%s: %s", deploy.OpDelete.Prefix(), k, stringifyPropertyValue(del))
+		} else if update, isupdate := diff.Updates[k]; isupdate {
+			line = fmt.Sprintf("%s%s: %s => %s", deploy.OpUpdate.Prefix(), k,
+				stringifyPropertyValue(update.Old), stringifyPropertyValue(update.New))
+		} else {
+			continue
+		}
+		fmt.Print(opts.Color.Colorize(fmt.Sprintf("        %s%s\n", line, colors.Reset)))
+	}
+}
+
+// stringifyPropertyValue renders a property value for display, masking any secrets it contains.
+func stringifyPropertyValue(v resource.PropertyValue) string {
+	return stringifyOutput(v.MapRepl(nil, func(v resource.PropertyValue) (interface{}, bool) {
+		if v.IsSecret() {
+			return "[secret]", true
+		}
+		return nil, false
+	}))
+}
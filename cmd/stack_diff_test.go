@@ -0,0 +1,66 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	newState := func(urn string, delete bool, inputs, outputs resource.PropertyMap) *resource.State {
+		return &resource.State{Type: "test", URN: resource.URN(urn), Delete: delete, Inputs: inputs, Outputs: outputs}
+	}
+	props := func(v string) resource.PropertyMap {
+		return resource.PropertyMap{"key": resource.NewStringProperty(v)}
+	}
+
+	olds := deploy.NewSnapshot(deploy.Manifest{}, []*resource.State{
+		newState("same", false, props("a"), props("a")),
+		newState("changed", false, props("a"), props("a")),
+		newState("removed", false, props("a"), props("a")),
+		newState("replaced", false, props("a"), props("a")),
+	}, nil)
+	news := deploy.NewSnapshot(deploy.Manifest{}, []*resource.State{
+		newState("added", false, props("a"), props("a")),
+		newState("same", false, props("a"), props("a")),
+		newState("changed", false, props("a"), props("b")),
+		newState("replaced", false, props("a"), props("a")),
+		newState("replaced", true, props("x"), props("x")),
+	}, nil)
+
+	diffs := diffSnapshots(olds, news)
+	if !assert.Len(t, diffs, 3) {
+		t.FailNow()
+	}
+	assert.Equal(t, resourceDiff{URN: "added", Op: deploy.OpCreate}, diffs[0])
+
+	assert.Equal(t, resource.URN("changed"), diffs[1].URN)
+	assert.Equal(t, deploy.OpUpdate, diffs[1].Op)
+	assert.Nil(t, diffs[1].Inputs)
+	if assert.NotNil(t, diffs[1].Outputs) {
+		assert.True(t, diffs[1].Outputs.Updated("key"))
+	}
+
+	assert.Equal(t, resourceDiff{URN: "removed", Op: deploy.OpDelete}, diffs[2])
+
+	// Diffing a snapshot against nothing reports all of its resources as added or removed.
+	assert.Len(t, diffSnapshots(nil, news), 4)
+	assert.Len(t, diffSnapshots(olds, nil), 4)
+}
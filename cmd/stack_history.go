@@ -0,0 +1,103 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStackHistoryCmd() *cobra.Command {
+	var stack string
+	cmd := &cobra.Command{
+		Use:   "history",
+		Args:  cmdutil.NoArgs,
+		Short: "Show the update history of a stack",
+		Long: "Show the update history of a stack.\n" +
+			"\n" +
+			"This command lists the updates that have been made to a stack, most recent first. Each\n" +
+			"update is labeled with the version of the stack it produced; two versions may be compared\n" +
+			"using `pulumi stack diff`.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := backend.DisplayOptions{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(stack, false, opts, false /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			updates, err := s.Backend().GetHistory(commandContext(), s.Name())
+			if err != nil {
+				return err
+			}
+			if len(updates) == 0 {
+				fmt.Printf("Stack %s has no updates\n", s.Name())
+				return nil
+			}
+
+			for i, update := range updates {
+				if i > 0 {
+					fmt.Println()
+				}
+				printUpdateInfo(update)
+			}
+			return nil
+		}),
+	}
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+	return cmd
+}
+
+// printUpdateInfo prints a summary of a single update from a stack's history.
+func printUpdateInfo(update backend.UpdateInfo) {
+	start := time.Unix(update.StartTime, 0)
+	fmt.Printf("Version %d: %s %s\n", update.Version, update.Kind, update.Result)
+	fmt.Printf("    Started:  %s (%s)\n", humanize.Time(start), start.Format(time.RFC1123))
+	if update.EndTime != 0 {
+		fmt.Printf("    Duration: %s\n", time.Duration(update.EndTime-update.StartTime)*time.Second)
+	}
+	if update.Message != "" {
+		fmt.Printf("    Message:  %s\n", update.Message)
+	}
+	fmt.Printf("    Changes:  %s\n", formatResourceChanges(update.ResourceChanges))
+}
+
+// formatResourceChanges summarizes the number of resources affected by each kind of step in an update.
+func formatResourceChanges(changes engine.ResourceChanges) string {
+	var parts []string
+	for _, op := range deploy.StepOps {
+		if c := changes[op]; c > 0 && op != deploy.OpSame {
+			parts = append(parts, fmt.Sprintf("%d %s", c, op.PastTense()))
+		}
+	}
+	if c := changes[deploy.OpSame]; c > 0 {
+		parts = append(parts, fmt.Sprintf("%d unchanged", c))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -74,7 +74,8 @@ func newStackImportCmd() *cobra.Command {
 			// We do, however, now want to unmarshal the json.RawMessage into a real, typed deployment.  We do this so
 			// we can check that the deployment doesn't contain resources from a stack other than the selected one. This
 			// catches errors wherein someone imports the wrong stack's deployment (which can seriously hork things).
-			snapshot, err := stack.DeserializeUntypedDeployment(&deployment)
+			crypter := stackStateCrypter(s)
+			snapshot, err := stack.DeserializeUntypedDeployment(&deployment, crypter)
 			if err != nil {
 				switch err {
 				case stack.ErrDeploymentSchemaVersionTooOld:
@@ -120,7 +121,11 @@ func newStackImportCmd() *cobra.Command {
 
 				snapshot.PendingOperations = nil
 			}
-			bytes, err := json.Marshal(stack.SerializeDeployment(snapshot))
+			serialized, err := stack.SerializeDeployment(snapshot, crypter)
+			if err != nil {
+				return err
+			}
+			bytes, err := json.Marshal(serialized)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,152 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/state"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func newStackRenameCmd() *cobra.Command {
+	var stack string
+	var cmd = &cobra.Command{
+		Use:   "rename <new-stack-name>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Rename an existing stack",
+		Long: "Rename an existing stack.\n" +
+			"\n" +
+			"Because the URN of each resource in a stack includes the stack's name, renaming a stack\n" +
+			"rewrites the URN of every resource in its checkpoint. The stack's update history and its\n" +
+			"configuration file are moved along with it.\n" +
+			"\n" +
+			"Note that resource names that were derived from the stack's name by the stack's program\n" +
+			"are not changed; the next update may replace such resources.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			newName := tokens.QName(args[0])
+
+			opts := backend.DisplayOptions{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(stack, false, opts, false /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			oldName := s.Name().StackName()
+			if oldName == newName {
+				return errors.Errorf("stack '%s' is already named '%s'", s.Name(), newName)
+			}
+
+			oldPath, err := workspace.DetectProjectStackPath(oldName)
+			if err != nil {
+				return err
+			}
+			newPath, err := workspace.DetectProjectStackPath(newName)
+			if err != nil {
+				return err
+			}
+
+			// Copy the stack's settings, if it has any, before renaming the stack itself: the backend may need them in
+			// order to protect the renamed stack's secrets. The copy is removed again if the rename fails, and the
+			// original is only removed once the rename has succeeded.
+			copied, err := copyStackSettings(oldPath, newPath)
+			if err != nil {
+				return err
+			}
+			if err = s.Rename(commandContext(), newName); err != nil {
+				if copied {
+					if removeErr := os.Remove(newPath); removeErr != nil {
+						return errors.Wrapf(err, "renaming stack (removing '%s' also failed: %v)", newPath, removeErr)
+					}
+				}
+				return err
+			}
+			if copied {
+				if err = os.Remove(oldPath); err != nil {
+					return errors.Wrapf(err, "removing the stack's old settings file '%s'", oldPath)
+				}
+			}
+
+			oldRef := s.Name().String()
+			newRef := strings.TrimSuffix(oldRef, string(oldName)) + string(newName)
+			fmt.Printf("Renamed stack '%s' to '%s'.\n", oldRef, newRef)
+
+			// If the renamed stack was the current stack, select it under its new name.
+			w, err := workspace.New()
+			if err != nil {
+				return err
+			}
+			if current := w.Settings().Stack; current != "" {
+				currentRef, parseErr := s.Backend().ParseStackReference(current)
+				if parseErr != nil || currentRef.String() != oldRef {
+					return parseErr
+				}
+				return state.SetCurrentStack(newRef)
+			}
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+
+	return cmd
+}
+
+// copyStackSettings copies the stack settings file at `oldPath` to `newPath`, returning false if there is no such file.
+// It refuses to replace an existing file.
+func copyStackSettings(oldPath, newPath string) (bool, error) {
+	b, err := ioutil.ReadFile(oldPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Wrapf(err, "reading stack settings from '%s'", oldPath)
+	}
+
+	info, err := os.Stat(oldPath)
+	if err != nil {
+		return false, err
+	}
+	f, err := os.OpenFile(newPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode())
+	if err != nil {
+		if os.IsExist(err) {
+			return false, errors.Errorf("stack settings file '%s' already exists", newPath)
+		}
+		return false, err
+	}
+	if _, err = f.Write(b); err != nil {
+		contract.IgnoreClose(f)
+		contract.IgnoreError(os.Remove(newPath))
+		return false, errors.Wrapf(err, "writing stack settings to '%s'", newPath)
+	}
+	if err = f.Close(); err != nil {
+		contract.IgnoreError(os.Remove(newPath))
+		return false, errors.Wrapf(err, "writing stack settings to '%s'", newPath)
+	}
+	return true, nil
+}
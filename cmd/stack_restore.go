@@ -0,0 +1,121 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/local"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStackRestoreCmd() *cobra.Command {
+	var fromBackup bool
+	var stack string
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "restore --from-backup [<backup>]",
+		Args:  cmdutil.MaximumNArgs(1),
+		Short: "Restore a stack's checkpoint from a backup",
+		Long: "Restore a stack's checkpoint from a backup.\n" +
+			"\n" +
+			"Before each update, the local backend saves a backup of the stack's checkpoint. When run\n" +
+			"without arguments, this command lists the available backups, most recent first. When given\n" +
+			"a backup, either by its number in that list or by its name, the stack's checkpoint is\n" +
+			"replaced with it. The current checkpoint is kept as a numbered .bak file next to it.\n" +
+			"\n" +
+			"This command is only supported for stacks managed by the local backend.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			if !fromBackup {
+				return errors.New("--from-backup must be passed; backups are currently the only restore source")
+			}
+
+			opts := backend.DisplayOptions{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(stack, false, opts, false /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			b, ok := s.Backend().(local.Backend)
+			if !ok {
+				return errors.Errorf("stack '%s' does not support restoring from backups; "+
+					"only stacks managed by the local backend do", s.Name())
+			}
+
+			backups, err := b.ListBackups(commandContext(), s.Name())
+			if err != nil {
+				return errors.Wrap(err, "listing backups")
+			}
+
+			// With no backup given, just print the backups that are available.
+			if len(args) == 0 {
+				if len(backups) == 0 {
+					fmt.Printf("Stack '%s' has no backups.\n", s.Name())
+					return nil
+				}
+				fmt.Printf("%-4s %-40s %s\n", "#", "NAME", "TAKEN")
+				for i, bck := range backups {
+					fmt.Printf("%-4d %-40s %s\n", i+1, bck.Name, humanize.Time(bck.Time))
+				}
+				return nil
+			}
+
+			// Otherwise, the backup may be given either by its position in the list or by its name.
+			name := args[0]
+			if n, parseErr := strconv.Atoi(name); parseErr == nil {
+				if n < 1 || n > len(backups) {
+					return errors.Errorf("backup number %d is out of range; stack '%s' has %d backup(s)",
+						n, s.Name(), len(backups))
+				}
+				name = backups[n-1].Name
+			}
+
+			if !yes {
+				prompt := fmt.Sprintf("This will replace the checkpoint of stack '%s' with the backup '%s'.",
+					s.Name(), name)
+				if !confirmPrompt(prompt, s.Name().String(), opts) {
+					return errors.New("confirmation declined")
+				}
+			}
+
+			if err = b.RestoreBackup(commandContext(), s.Name(), name); err != nil {
+				return err
+			}
+
+			fmt.Printf("Restored stack '%s' from backup '%s'.\n", s.Name(), name)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().BoolVar(
+		&fromBackup, "from-backup", false,
+		"Restore the stack's checkpoint from one of the backups taken before each update")
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Skip confirmation prompts, and proceed with the restore anyway")
+
+	return cmd
+}
@@ -0,0 +1,196 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/local"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+func newStackRollbackCmd() *cobra.Command {
+	var debug bool
+	var stack string
+	var message string
+
+	// Flags for engine.UpdateOptions.
+	var diffDisplay bool
+	var eventLogPath string
+	var parallel int
+	var previewOnly bool
+	var showReplacementSteps bool
+	var showSames bool
+	var nonInteractive bool
+	var skipPreview bool
+	var yes bool
+
+	var cmd = &cobra.Command{
+		Use:   "rollback <version>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Return a stack's resources to a previous version",
+		Long: "Return a stack's resources to a previous version.\n" +
+			"\n" +
+			"This command loads the stack's checkpoint as of the given version, as listed by\n" +
+			"`pulumi stack history`, and updates the stack so that its resources match it again.\n" +
+			"Instead of running the stack's program, every resource in that checkpoint is registered\n" +
+			"with the inputs, provider, and options it had at the time; resources that have been added\n" +
+			"since are deleted. The rollback is previewed first, and must be confirmed unless `--yes`\n" +
+			"is passed.\n" +
+			"\n" +
+			"This command is only supported for stacks managed by the local backend.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			version, err := strconv.Atoi(args[0])
+			if err != nil || version < 1 {
+				return errors.Errorf("invalid version '%s'; versions are positive integers", args[0])
+			}
+
+			interactive := isInteractive(nonInteractive)
+			if !interactive {
+				yes = true // auto-approve changes, since we cannot prompt.
+			}
+			if previewOnly && skipPreview {
+				return errors.New("--preview-only and --skip-preview cannot be used together")
+			}
+
+			opts, err := updateFlagsToOptions(interactive, skipPreview, yes || previewOnly)
+			if err != nil {
+				return err
+			}
+
+			opts.Display = backend.DisplayOptions{
+				Color:                cmdutil.GetGlobalColorization(),
+				ShowReplacementSteps: showReplacementSteps,
+				ShowSameResources:    showSames,
+				IsInteractive:        interactive,
+				DiffDisplay:          diffDisplay,
+				Debug:                debug,
+			}
+
+			if eventLogPath != "" {
+				eventLog, logErr := backend.NewEventLog(eventLogPath)
+				if logErr != nil {
+					return logErr
+				}
+				defer contract.IgnoreClose(eventLog)
+				opts.Display.EventLog = eventLog
+			}
+
+			s, err := requireStack(stack, false, opts.Display, true /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+			b, ok := s.Backend().(local.Backend)
+			if !ok {
+				return errors.Errorf("stack '%s' does not support rollback; "+
+					"only stacks managed by the local backend do", s.Name())
+			}
+
+			proj, root, err := readProject()
+			if err != nil {
+				return err
+			}
+
+			m, err := getUpdateMetadata(message, root)
+			if err != nil {
+				return errors.Wrap(err, "gathering environment metadata")
+			}
+			if m.Message == "" {
+				m.Message = fmt.Sprintf("Roll back to version %d", version)
+			}
+
+			opts.Engine = engine.UpdateOptions{
+				Parallel: parallel,
+				Debug:    debug,
+			}
+
+			rollback := func(opts backend.UpdateOptions) error {
+				_, rollbackErr := b.Rollback(commandContext(), s.Name(), proj, root, version, m, opts,
+					cancellationScopes)
+				if rollbackErr == context.Canceled {
+					return errors.New("rollback cancelled")
+				}
+				return PrintEngineError(rollbackErr)
+			}
+
+			// Preview the rollback first, so that its effects can be reviewed before they are made.
+			if !skipPreview {
+				previewOpts := opts
+				previewOpts.PreviewOnly = true
+				if err = rollback(previewOpts); err != nil || previewOnly {
+					return err
+				}
+
+				if !yes {
+					prompt := fmt.Sprintf("This will return the resources of stack '%s' to version %d.",
+						s.Name(), version)
+					if !confirmPrompt(prompt, s.Name().String(), opts.Display) {
+						return errors.New("confirmation declined")
+					}
+				}
+			}
+
+			return rollback(opts)
+		}),
+	}
+
+	cmd.PersistentFlags().BoolVarP(
+		&debug, "debug", "d", false,
+		"Print detailed debugging output during resource operations")
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringVarP(
+		&message, "message", "m", "",
+		"Optional message to associate with the rollback operation")
+
+	// Flags for engine.UpdateOptions.
+	cmd.PersistentFlags().BoolVar(
+		&diffDisplay, "diff", false,
+		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().StringVar(
+		&eventLogPath, "event-log", "",
+		"Log every engine event during the rollback to the given file as newline-delimited JSON")
+	cmd.PersistentFlags().BoolVar(
+		&nonInteractive, "non-interactive", false, "Disable interactive mode")
+	cmd.PersistentFlags().IntVarP(
+		&parallel, "parallel", "p", 10,
+		"Allow P resource operations to run in parallel at once (<=1 for no parallelism)")
+	cmd.PersistentFlags().BoolVar(
+		&previewOnly, "preview-only", false,
+		"Only preview the rollback, without changing the stack's resources")
+	cmd.PersistentFlags().BoolVar(
+		&showReplacementSteps, "show-replacement-steps", false,
+		"Show detailed resource replacement creates and deletes instead of a single step")
+	cmd.PersistentFlags().BoolVar(
+		&showSames, "show-sames", false,
+		"Show resources that don't need to be updated because they haven't changed, alongside those that do")
+	cmd.PersistentFlags().BoolVar(
+		&skipPreview, "skip-preview", false,
+		"Do not perform a preview before performing the rollback")
+	cmd.PersistentFlags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Automatically approve and perform the rollback after previewing it")
+
+	return cmd
+}
@@ -0,0 +1,105 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/secrets"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Edit the current stack's state",
+		Long: "Edit the current stack's state.\n" +
+			"\n" +
+			"Subcommands of this command can be used to surgically edit the resources recorded in a\n" +
+			"stack's state, for example to remove a resource that was deleted outside of Pulumi. They\n" +
+			"change only the stack's state, never the resources themselves. Every edit is checked for\n" +
+			"the same integrity constraints that the engine enforces before it is saved.",
+		Args: cmdutil.NoArgs,
+	}
+
+	cmd.AddCommand(newStateClearPendingCmd())
+	cmd.AddCommand(newStateDeleteCmd())
+	cmd.AddCommand(newStateUnprotectCmd())
+
+	return cmd
+}
+
+// stackStateCrypter returns a crypter for the secrets in the given stack's state. The stack's crypter is only obtained,
+// possibly prompting for a passphrase, if the state contains secrets, and secrets that are decrypted are re-encrypted
+// to the same ciphertext.
+func stackStateCrypter(s backend.Stack) config.Crypter {
+	return secrets.NewCachingCrypter(func() (config.Crypter, error) { return backend.GetStackCrypter(s) })
+}
+
+// editStackState applies the given edit to the deployment of the named stack (or the current stack, if none is
+// named). The edited snapshot must pass the engine's integrity checks before it is written back to the stack.
+func editStackState(stackName string, opts backend.DisplayOptions,
+	edit func(snap *deploy.Snapshot) error) error {
+
+	s, err := requireStack(stackName, false, opts, true /*setCurrent*/)
+	if err != nil {
+		return err
+	}
+
+	deployment, err := s.ExportDeployment(commandContext())
+	if err != nil {
+		return err
+	}
+	crypter := stackStateCrypter(s)
+	snap, err := stack.DeserializeUntypedDeployment(deployment, crypter)
+	if err != nil {
+		return err
+	}
+	if snap == nil {
+		return errors.Errorf("stack '%s' has no resources", s.Name())
+	}
+
+	if err = edit(snap); err != nil {
+		return err
+	}
+	if err = snap.VerifyIntegrity(); err != nil {
+		return errors.Wrap(err, "the edited state failed integrity checks; refusing to save it")
+	}
+
+	serialized, err := stack.SerializeDeployment(snap, crypter)
+	if err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(serialized)
+	if err != nil {
+		return err
+	}
+	dep := apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: bytes,
+	}
+	if err = s.ImportDeployment(commandContext(), &dep); err != nil {
+		return errors.Wrap(err, "could not save the edited state")
+	}
+	return nil
+}
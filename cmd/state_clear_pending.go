@@ -0,0 +1,66 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/edit"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStateClearPendingCmd() *cobra.Command {
+	var stack string
+	cmd := &cobra.Command{
+		Use:   "clear-pending",
+		Short: "Clear the pending operations recorded in a stack's state",
+		Long: "Clear the pending operations recorded in a stack's state.\n" +
+			"\n" +
+			"If an update is interrupted, the operations that were in flight are recorded in the\n" +
+			"stack's state, and subsequent updates refuse to proceed until they are resolved. This\n" +
+			"command discards them. Any resource that was being created by an interrupted operation\n" +
+			"may have to be imported or deleted by hand; running 'pulumi refresh' afterwards will\n" +
+			"reconcile resources that were being updated or deleted.",
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := backend.DisplayOptions{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			var cleared int
+			err := editStackState(stack, opts, func(snap *deploy.Snapshot) error {
+				cleared = len(snap.PendingOperations)
+				edit.ClearPendingOperations(snap)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Cleared %d pending operation(s).\n", cleared)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+
+	return cmd
+}
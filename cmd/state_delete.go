@@ -0,0 +1,88 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/edit"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStateDeleteCmd() *cobra.Command {
+	var force bool
+	var stack string
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "delete <resource URN>",
+		Short: "Delete a resource from a stack's state",
+		Long: "Delete a resource from a stack's state.\n" +
+			"\n" +
+			"This command removes the resource with the given URN from the stack's state without\n" +
+			"deleting the resource itself. It is most useful when a resource has been deleted outside\n" +
+			"of Pulumi and should no longer be managed by the stack.\n" +
+			"\n" +
+			"Protected resources and resources upon which other resources depend are not deleted unless\n" +
+			"--force is passed, in which case any dependents are rewritten to no longer refer to it.",
+		Args: cmdutil.ExactArgs(1),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			urn := resource.URN(args[0])
+
+			opts := backend.DisplayOptions{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if !yes {
+				prompt := fmt.Sprintf("This will remove '%s' from the stack's state; the resource itself "+
+					"will not be deleted.", urn)
+				if !confirmPrompt(prompt, urn.Name().String(), opts) {
+					return errors.New("confirmation declined")
+				}
+			}
+
+			err := editStackState(stack, opts, func(snap *deploy.Snapshot) error {
+				return edit.DeleteResource(snap, urn, force)
+			})
+			if err != nil {
+				switch err.(type) {
+				case edit.ResourceHasDependenciesError, edit.ResourceProtectedError:
+					return errors.Errorf("%v; pass --force to delete it anyway", err)
+				}
+				return err
+			}
+
+			fmt.Printf("Resource '%s' was deleted from the stack's state.\n", urn)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().BoolVarP(
+		&force, "force", "f", false,
+		"Delete the resource even if it is protected or other resources depend on it")
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Skip confirmation prompts, and proceed with the deletion anyway")
+
+	return cmd
+}
@@ -0,0 +1,64 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/edit"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+)
+
+func newStateUnprotectCmd() *cobra.Command {
+	var stack string
+	cmd := &cobra.Command{
+		Use:   "unprotect <resource URN>",
+		Short: "Unprotect a resource in a stack's state",
+		Long: "Unprotect a resource in a stack's state.\n" +
+			"\n" +
+			"This command clears the 'protect' bit on the resource with the given URN, so that it may\n" +
+			"be deleted or replaced by subsequent updates. Note that if the stack's program still\n" +
+			"marks the resource as protected, the next update will protect it again.",
+		Args: cmdutil.ExactArgs(1),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			urn := resource.URN(args[0])
+
+			opts := backend.DisplayOptions{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			err := editStackState(stack, opts, func(snap *deploy.Snapshot) error {
+				return edit.UnprotectResource(snap, urn)
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Resource '%s' was unprotected.\n", urn)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+
+	return cmd
+}
@@ -44,11 +44,16 @@ func newUpCmd() *cobra.Command {
 	// Flags for engine.UpdateOptions.
 	var analyzers []string
 	var diffDisplay bool
+	var eventLogPath string
+	var jsonDisplay bool
 	var nonInteractive bool
 	var parallel int
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
+	var targets []string
+	var targetDependents bool
+	var replaces []string
 	var skipPreview bool
 	var yes bool
 
@@ -70,9 +75,12 @@ func newUpCmd() *cobra.Command {
 		}
 
 		opts.Engine = engine.UpdateOptions{
-			Analyzers: analyzers,
-			Parallel:  parallel,
-			Debug:     debug,
+			Analyzers:        analyzers,
+			Parallel:         parallel,
+			Debug:            debug,
+			Targets:          targetsToFilters(targets),
+			TargetDependents: targetDependents,
+			ReplaceTargets:   targetsToFilters(replaces),
 		}
 
 		changes, err := s.Update(commandContext(), proj, root, m, opts, cancellationScopes)
@@ -209,9 +217,12 @@ func newUpCmd() *cobra.Command {
 		}
 
 		opts.Engine = engine.UpdateOptions{
-			Analyzers: analyzers,
-			Parallel:  parallel,
-			Debug:     debug,
+			Analyzers:        analyzers,
+			Parallel:         parallel,
+			Debug:            debug,
+			Targets:          targetsToFilters(targets),
+			TargetDependents: targetDependents,
+			ReplaceTargets:   targetsToFilters(replaces),
 		}
 
 		// TODO for the URL case:
@@ -251,6 +262,14 @@ func newUpCmd() *cobra.Command {
 		Args: cmdutil.MaximumNArgs(1),
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			interactive := isInteractive(nonInteractive)
+			if jsonDisplay {
+				// A JSON document can't be interleaved with a confirmation prompt, so require that the update be
+				// approved up front. The preview is skipped so that only a single document is written.
+				if interactive && !yes {
+					return errors.New("--yes must be passed in along with --json")
+				}
+				interactive, skipPreview = false, true
+			}
 			if !interactive {
 				yes = true // auto-approve changes, since we cannot prompt.
 			}
@@ -267,9 +286,19 @@ func newUpCmd() *cobra.Command {
 				ShowSameResources:    showSames,
 				IsInteractive:        interactive,
 				DiffDisplay:          diffDisplay,
+				JSONDisplay:          jsonDisplay,
 				Debug:                debug,
 			}
 
+			if eventLogPath != "" {
+				eventLog, logErr := backend.NewEventLog(eventLogPath)
+				if logErr != nil {
+					return logErr
+				}
+				defer contract.IgnoreClose(eventLog)
+				opts.Display.EventLog = eventLog
+			}
+
 			if len(args) > 0 {
 				return upURL(args[0], opts)
 			}
@@ -302,6 +331,13 @@ func newUpCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&diffDisplay, "diff", false,
 		"Display operation as a rich diff showing the overall change")
+	cmd.PersistentFlags().StringVar(
+		&eventLogPath, "event-log", "",
+		"Log every engine event during the update to the given file as newline-delimited JSON")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonDisplay, "json", "j", false,
+		"Serialize the update steps, diagnostics, outputs, and summary as a single JSON document. "+
+			"Implies --skip-preview; --yes must also be passed when running interactively")
 	cmd.PersistentFlags().BoolVar(
 		&nonInteractive, "non-interactive", false, "Disable interactive mode")
 	cmd.PersistentFlags().IntVarP(
@@ -316,6 +352,17 @@ func newUpCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&showSames, "show-sames", false,
 		"Show resources that don't need be updated because they haven't changed, alongside those that do")
+	cmd.PersistentFlags().StringArrayVarP(
+		&targets, "target", "t", []string{},
+		"Restrict the update to the resources matching the given URN, '<type>::<name>', or name. "+
+			"Multiple resources can be targeted by passing the flag multiple times")
+	cmd.PersistentFlags().BoolVar(
+		&targetDependents, "target-dependents", false,
+		"Also target any resources that depend on the targeted resources")
+	cmd.PersistentFlags().StringArrayVar(
+		&replaces, "replace", []string{},
+		"Force the replacement of the resources matching the given URN, '<type>::<name>', or name during the update. "+
+			"Multiple resources can be replaced by passing the flag multiple times")
 	cmd.PersistentFlags().BoolVar(
 		&skipPreview, "skip-preview", false,
 		"Do not perform a preview before performing the update")
@@ -39,6 +39,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/backend/state"
 	"github.com/pulumi/pulumi/pkg/diag/colors"
 	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/operations"
 	"github.com/pulumi/pulumi/pkg/util/cancel"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 	"github.com/pulumi/pulumi/pkg/util/contract"
@@ -57,7 +58,7 @@ func currentBackend(opts backend.DisplayOptions) (backend.Backend, error) {
 		return nil, err
 	}
 	if local.IsLocalBackendURL(creds.Current) {
-		return local.New(cmdutil.Diag(), creds.Current), nil
+		return local.New(cmdutil.Diag(), creds.Current)
 	}
 	return cloud.Login(commandContext(), cmdutil.Diag(), creds.Current, opts)
 }
@@ -536,3 +537,15 @@ func updateFlagsToOptions(interactive, skipPreview, yes bool) (backend.UpdateOpt
 		SkipPreview: skipPreview,
 	}, nil
 }
+
+// targetsToFilters converts the values of a `--target` flag into the resource filters used to restrict an update.
+func targetsToFilters(targets []string) []operations.ResourceFilter {
+	if len(targets) == 0 {
+		return nil
+	}
+	filters := make([]operations.ResourceFilter, len(targets))
+	for i, target := range targets {
+		filters[i] = operations.ResourceFilter(target)
+	}
+	return filters
+}
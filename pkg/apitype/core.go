@@ -68,6 +68,8 @@ type CheckpointV2 struct {
 	Config config.Map `json:"config,omitempty" yaml:"config,omitempty"`
 	// Latest is the latest/current deployment (if an update has occurred).
 	Latest *DeploymentV2 `json:"latest,omitempty" yaml:"latest,omitempty"`
+	// Journal contains changes made to the latest deployment since it was written, in the order they were made.
+	Journal []JournalEntryV1 `json:"journal,omitempty" yaml:"journal,omitempty"`
 }
 
 // DeploymentV1 represents a deployment that has actually occurred. It is similar to the engine's snapshot structure,
@@ -103,6 +105,8 @@ const (
 	OperationTypeDeleting OperationType = "deleting"
 	// OperationTypeReading is the state of resources that are being read.
 	OperationTypeReading OperationType = "reading"
+	// OperationTypeImporting is the state of resources that are being imported.
+	OperationTypeImporting OperationType = "importing"
 )
 
 // OperationV1 represents an operation that the engine is performing. It consists of a Resource, which is the state
@@ -115,6 +119,46 @@ type OperationV1 struct {
 	Type OperationType `json:"type" yaml:"type"`
 }
 
+// JournalEntryKind is the kind of change recorded by a journal entry.
+type JournalEntryKind string
+
+const (
+	// JournalEntryBeginOperation records that the engine began an operation on a resource.
+	JournalEntryBeginOperation JournalEntryKind = "begin"
+	// JournalEntryCompleteOperation records that an operation begun earlier is no longer pending.
+	JournalEntryCompleteOperation JournalEntryKind = "complete"
+	// JournalEntryAddResource records that a resource was added to the deployment.
+	JournalEntryAddResource JournalEntryKind = "add"
+	// JournalEntryRemoveResource records that a resource was removed from the deployment.
+	JournalEntryRemoveResource JournalEntryKind = "remove"
+	// JournalEntryUpdateResource records that the state of a resource in the deployment changed in place.
+	JournalEntryUpdateResource JournalEntryKind = "update"
+	// JournalEntryAddPlugin records that a plugin was loaded during the deployment.
+	JournalEntryAddPlugin JournalEntryKind = "plugin"
+)
+
+// JournalEntryV1 records a single change to a deployment. Rather than rewriting the entire deployment after every
+// change, a journal of these entries may be appended to the last deployment written, and replayed on top of it to
+// reconstruct the current deployment.
+//
+// Resources are identified by number. The resources in the deployment the journal applies to are numbered from zero
+// in the order they appear in it, and each resource added by the journal takes the next number in sequence.
+// Operations are numbered in the same way, starting with the deployment's pending operations.
+type JournalEntryV1 struct {
+	// Kind is the kind of change this entry records.
+	Kind JournalEntryKind `json:"kind" yaml:"kind"`
+	// ID is the number of the resource (or, for begin and complete entries, the operation) that was changed.
+	ID int `json:"id" yaml:"id"`
+	// Index is the position at which an added resource was inserted into the deployment's list of resources.
+	Index int `json:"index,omitempty" yaml:"index,omitempty"`
+	// Resource is the new state of an added or updated resource, or the resource state an operation began with.
+	Resource *ResourceV2 `json:"resource,omitempty" yaml:"resource,omitempty"`
+	// OperationType is the type of operation that began.
+	OperationType OperationType `json:"operationType,omitempty" yaml:"operationType,omitempty"`
+	// Plugin is the plugin that was loaded.
+	Plugin *PluginInfoV1 `json:"plugin,omitempty" yaml:"plugin,omitempty"`
+}
+
 // UntypedDeployment contains an inner, untyped deployment structure.
 type UntypedDeployment struct {
 	// Version indicates the schema of the encoded deployment.
@@ -196,6 +240,8 @@ type ResourceV2 struct {
 	InitErrors []string `json:"initErrors" yaml:"initErrors,omitempty"`
 	// Provider is a reference to the provider that is associated with this resource.
 	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+	// IgnoreChanges is a list of property paths whose changes are ignored when this resource is updated.
+	IgnoreChanges []string `json:"ignoreChanges,omitempty" yaml:"ignoreChanges,omitempty"`
 }
 
 // ManifestV1 captures meta-information about this checkpoint file, such as versions of binaries, etc.
@@ -220,10 +266,14 @@ type PluginInfoV1 struct {
 
 // ConfigValue describes a single (possibly secret) configuration value.
 type ConfigValue struct {
-	// String is either the plaintext value (for non-secrets) or the base64-encoded ciphertext (for secrets).
+	// String is either the plaintext value (for non-secrets) or the base64-encoded ciphertext (for secrets).  For
+	// structured values, it is the JSON representation of the object, with any secrets in the form
+	// {"secure": "<ciphertext>"}.
 	String string `json:"string"`
-	// Secret is true if this value is a secret and false otherwise.
+	// Secret is true if this value is a secret (or, for structured values, contains a secret) and false otherwise.
 	Secret bool `json:"secret"`
+	// Object is true if this value is a structured object or array and false otherwise.
+	Object bool `json:"object,omitempty"`
 }
 
 // StackTagName is the key for the tags bag in stack. This is just a string, but we use a type alias to provide a richer
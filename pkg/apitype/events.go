@@ -0,0 +1,159 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apitype
+
+// This file contains the wire format for the events emitted by the engine during an update. These types should
+// generally mirror the event payloads in the engine package, but we clone them here so that the serialized form stays
+// stable even if the engine's types change.
+
+// CancelEvent is emitted when the engine has finished emitting events for an operation.
+type CancelEvent struct{}
+
+// StdoutEngineEvent is emitted whenever a generic message is written, for example warnings from the pulumi CLI
+// itself. Less common than DiagnosticEvent.
+type StdoutEngineEvent struct {
+	Message string `json:"message"`
+	Color   string `json:"color"`
+}
+
+// DiagnosticEvent is emitted whenever a diagnostic message is provided, for example errors from a cloud resource
+// provider while trying to create or update a resource.
+type DiagnosticEvent struct {
+	URN      string `json:"urn,omitempty"`
+	Prefix   string `json:"prefix,omitempty"`
+	Message  string `json:"message"`
+	Color    string `json:"color"`
+	Severity string `json:"severity"` // one of "debug", "info", "info#err", "warning", or "error".
+	StreamID int    `json:"streamID,omitempty"`
+}
+
+// PreludeEvent is emitted at the start of an update.
+type PreludeEvent struct {
+	IsPreview bool `json:"isPreview"`
+	// Config contains the keys and values for the update. Encrypted configuration values may be blinded.
+	Config map[string]string `json:"config"`
+}
+
+// SummaryEvent is emitted at the end of an update, with a summary of the changes made.
+type SummaryEvent struct {
+	IsPreview bool `json:"isPreview"`
+	// MaybeCorrupt is set if one or more of the resources is in an invalid state.
+	MaybeCorrupt bool `json:"maybeCorrupt"`
+	// DurationSeconds is the number of seconds the update was executing.
+	DurationSeconds int `json:"durationSeconds"`
+	// ResourceChanges contains the count for resource change by type. The keys are step operations, e.g. "create".
+	ResourceChanges map[string]int `json:"resourceChanges"`
+	// PolicyViolations contains any policy violations reported by analyzers during the update.
+	PolicyViolations []PolicyViolation `json:"policyViolations,omitempty"`
+}
+
+// PolicyViolation describes a violation of a policy that was reported by an analyzer.
+type PolicyViolation struct {
+	Analyzer         string `json:"analyzer"`
+	PolicyName       string `json:"policyName,omitempty"`
+	Description      string `json:"description,omitempty"`
+	Severity         string `json:"severity,omitempty"`
+	EnforcementLevel string `json:"enforcementLevel"` // one of "mandatory" or "advisory".
+	URN              string `json:"urn,omitempty"`
+	Property         string `json:"property,omitempty"`
+	Reason           string `json:"reason"`
+}
+
+// StepEventMetadata describes a "step" within the Pulumi engine, which is any concrete action to migrate a set of
+// cloud resources from one state to another.
+type StepEventMetadata struct {
+	// Op is the operation being performed, e.g. "same", "create", "update", "delete", or "replace".
+	Op   string `json:"op"`
+	URN  string `json:"urn"`
+	Type string `json:"type"`
+
+	// Old is the state of the resource before performing the step.
+	Old *StepEventStateMetadata `json:"old,omitempty"`
+	// New is the state of the resource after performing the step.
+	New *StepEventStateMetadata `json:"new,omitempty"`
+
+	// Keys causing a replacement (only applicable for "create" and "replace" Ops).
+	Keys []string `json:"keys,omitempty"`
+	// Forced is set if the user forced the replacement (only applicable for "create" and "replace" Ops).
+	Forced bool `json:"forced,omitempty"`
+	// Logical is set if the step is a logical operation in the program.
+	Logical bool `json:"logical,omitempty"`
+	// Provider actually performing the step.
+	Provider string `json:"provider,omitempty"`
+}
+
+// StepEventStateMetadata is the more detailed state information for a resource as it relates to a step(s) being
+// performed.
+type StepEventStateMetadata struct {
+	Type string `json:"type"`
+	URN  string `json:"urn"`
+
+	// Custom indicates if the resource is managed by a plugin.
+	Custom bool `json:"custom,omitempty"`
+	// Delete is true when the resource is pending deletion due to a replacement.
+	Delete bool `json:"delete,omitempty"`
+	// ID is the resource's unique ID, assigned by the resource provider (or blank if none/uncreated).
+	ID string `json:"id,omitempty"`
+	// Parent is an optional parent URN that this resource belongs to.
+	Parent string `json:"parent,omitempty"`
+	// Protect is true to "protect" this resource (protected resources cannot be deleted).
+	Protect bool `json:"protect,omitempty"`
+	// Inputs contains the resource's input properties (as specified by the program).
+	Inputs map[string]interface{} `json:"inputs,omitempty"`
+	// Outputs contains the resource's complete output state (as returned by the resource provider).
+	Outputs map[string]interface{} `json:"outputs,omitempty"`
+	// Provider is the resource's provider reference.
+	Provider string `json:"provider,omitempty"`
+}
+
+// ResourcePreEvent is emitted before a resource is modified.
+type ResourcePreEvent struct {
+	Metadata StepEventMetadata `json:"metadata"`
+	Planning bool              `json:"planning,omitempty"`
+}
+
+// ResOutputsEvent is emitted when a resource is finished being provisioned.
+type ResOutputsEvent struct {
+	Metadata StepEventMetadata `json:"metadata"`
+	Planning bool              `json:"planning,omitempty"`
+}
+
+// ResOpFailedEvent is emitted when a resource operation fails. Typically a DiagnosticEvent is emitted before this
+// event, indicating the root cause of the error.
+type ResOpFailedEvent struct {
+	Metadata StepEventMetadata `json:"metadata"`
+	Status   int               `json:"status"`
+	Steps    int               `json:"steps"`
+}
+
+// EngineEvent describes a Pulumi engine event, such as a change to a resource or diagnostic message. EngineEvent is
+// a discriminated union of all possible event types, and exactly one field will be non-nil.
+type EngineEvent struct {
+	// Sequence is a unique, monotonically increasing number for each engine event in a log. Events from several
+	// operations (e.g. a preview followed by an update) may share a log, so the sequence number ensures that events
+	// can be placed within the proper context.
+	Sequence int `json:"sequence"`
+	// Timestamp is a Unix timestamp (seconds) of when the event was emitted.
+	Timestamp int `json:"timestamp"`
+
+	CancelEvent      *CancelEvent       `json:"cancelEvent,omitempty"`
+	StdoutEvent      *StdoutEngineEvent `json:"stdoutEvent,omitempty"`
+	DiagnosticEvent  *DiagnosticEvent   `json:"diagnosticEvent,omitempty"`
+	PreludeEvent     *PreludeEvent      `json:"preludeEvent,omitempty"`
+	SummaryEvent     *SummaryEvent      `json:"summaryEvent,omitempty"`
+	ResourcePreEvent *ResourcePreEvent  `json:"resourcePreEvent,omitempty"`
+	ResOutputsEvent  *ResOutputsEvent   `json:"resOutputsEvent,omitempty"`
+	ResOpFailedEvent *ResOpFailedEvent  `json:"resOpFailedEvent,omitempty"`
+}
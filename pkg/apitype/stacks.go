@@ -110,6 +110,12 @@ type DecryptValueResponse struct {
 	Plaintext []byte `json:"plaintext"`
 }
 
+// RenameStackRequest defines the request body for renaming a Stack.
+type RenameStackRequest struct {
+	// The new name of the stack.
+	NewName string `json:"newName"`
+}
+
 // ExportStackResponse defines the response body for exporting a Stack.
 type ExportStackResponse UntypedDeployment
 
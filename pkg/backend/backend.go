@@ -74,6 +74,9 @@ type Backend interface {
 	// still contains resources.  Otherwise, if the stack contains resources, a non-nil error is returned, and the
 	// first boolean return value will be set to true.
 	RemoveStack(ctx context.Context, stackRef StackReference, force bool) (bool, error)
+	// RenameStack renames the given stack. Because resource URNs embed the name of their stack, every URN in the
+	// stack's checkpoint is rewritten to refer to the new name.
+	RenameStack(ctx context.Context, stackRef StackReference, newName tokens.QName) error
 	// ListStacks returns a list of stack summaries for all known stacks in the target backend.
 	ListStacks(ctx context.Context, projectFilter *tokens.PackageName) ([]Stack, error)
 
@@ -92,6 +95,9 @@ type Backend interface {
 	// Destroy destroys all of this stack's resources.
 	Destroy(ctx context.Context, stackRef StackReference, proj *workspace.Project, root string,
 		m UpdateMetadata, opts UpdateOptions, scopes CancellationScopeSource) (engine.ResourceChanges, error)
+	// CancelCurrentUpdate cancels the update currently being applied to the given stack, if any, so that the stack
+	// is ready for further updates.
+	CancelCurrentUpdate(ctx context.Context, stackRef StackReference) error
 
 	// GetHistory returns all updates for the stack. The returned UpdateInfo slice will be in
 	// descending order (newest first).
@@ -103,6 +109,10 @@ type Backend interface {
 
 	// ExportDeployment exports the deployment for the given stack as an opaque JSON message.
 	ExportDeployment(ctx context.Context, stackRef StackReference) (*apitype.UntypedDeployment, error)
+	// ExportDeploymentVersion exports the deployment produced by the given version of the stack, as numbered by the
+	// updates returned from GetHistory, as an opaque JSON message.
+	ExportDeploymentVersion(ctx context.Context, stackRef StackReference,
+		version int) (*apitype.UntypedDeployment, error)
 	// ImportDeployment imports the given deployment into the indicated stack.
 	ImportDeployment(ctx context.Context, stackRef StackReference, deployment *apitype.UntypedDeployment) error
 	// Logout logs you out of the backend and removes any stored credentials.
@@ -122,6 +132,8 @@ type UpdateOptions struct {
 	AutoApprove bool
 	// SkipPreview, when true, causes the preview step to be skipped.
 	SkipPreview bool
+	// PreviewOnly, when true, causes the operation to stop after its preview; nothing is performed or persisted.
+	PreviewOnly bool
 }
 
 // CancellationScope provides a scoped source of cancellation and termination requests.
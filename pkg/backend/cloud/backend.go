@@ -20,6 +20,7 @@ import (
 	cryptorand "crypto/rand"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -31,6 +32,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cheggaaa/pb"
@@ -52,6 +54,8 @@ import (
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/secrets"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/archive"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
@@ -139,7 +143,6 @@ type Backend interface {
 		ctx context.Context, info workspace.PluginInfo,
 		progress bool, opts backend.DisplayOptions) (io.ReadCloser, error)
 
-	CancelCurrentUpdate(ctx context.Context, stackRef backend.StackReference) error
 	StackConsoleURL(stackRef backend.StackReference) (string, error)
 }
 
@@ -147,6 +150,9 @@ type cloudBackend struct {
 	d      diag.Sink
 	url    string
 	client *client.Client
+
+	crypters     map[string]config.Crypter // the crypters protecting each stack's secret state.
+	cryptersLock sync.Mutex
 }
 
 // New creates a new Pulumi backend for the given cloud API URL and token.
@@ -158,9 +164,10 @@ func New(d diag.Sink, cloudURL string) (Backend, error) {
 	}
 
 	return &cloudBackend{
-		d:      d,
-		url:    cloudURL,
-		client: client.NewClient(cloudURL, apiToken),
+		d:        d,
+		url:      cloudURL,
+		client:   client.NewClient(cloudURL, apiToken),
+		crypters: make(map[string]config.Crypter),
 	}, nil
 }
 
@@ -524,6 +531,61 @@ func (b *cloudBackend) RemoveStack(ctx context.Context, stackRef backend.StackRe
 	return b.client.DeleteStack(ctx, stack, force)
 }
 
+func (b *cloudBackend) RenameStack(ctx context.Context, stackRef backend.StackReference,
+	newName tokens.QName) error {
+
+	stackID, err := b.getCloudStackIdentifier(stackRef)
+	if err != nil {
+		return err
+	}
+	if err = backend.ValidateStackProperties(string(newName), nil); err != nil {
+		return errors.Wrap(err, "validating stack properties")
+	}
+
+	// Fetch the stack's current deployment and rewrite its URNs before renaming the stack itself, so that we do not
+	// leave a renamed stack with an unusable deployment behind if the deployment cannot be rewritten.
+	deployment, err := b.client.ExportStackDeployment(ctx, stackID)
+	if err != nil {
+		return err
+	}
+	snap, err := stack.DeserializeUntypedDeployment(&deployment, b.stateCrypter(stackRef))
+	if err != nil {
+		return err
+	}
+	if err = snap.RenameStack(newName); err != nil {
+		return err
+	}
+	dep, err := stack.SerializeDeployment(snap, b.stateCrypter(stackRef))
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(dep)
+	if err != nil {
+		return err
+	}
+
+	if err = b.client.RenameStack(ctx, stackID, string(newName)); err != nil {
+		return err
+	}
+
+	// Finally, import the rewritten deployment into the renamed stack. If that fails, rename the stack back so that
+	// its name continues to match the URNs in its deployment.
+	newRef := cloudBackendReference{name: newName, owner: stackID.Owner, b: b}
+	err = b.ImportDeployment(ctx, newRef, &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: raw,
+	})
+	if err != nil {
+		newStackID := client.StackIdentifier{Owner: stackID.Owner, Stack: string(newName)}
+		if rollbackErr := b.client.RenameStack(ctx, newStackID, stackID.Stack); rollbackErr != nil {
+			return errors.Wrapf(err, "importing the renamed deployment (renaming the stack back to '%s' also failed: %v)",
+				stackID.Stack, rollbackErr)
+		}
+		return errors.Wrap(err, "importing the renamed deployment")
+	}
+	return nil
+}
+
 // cloudCrypter is an encrypter/decrypter that uses the Pulumi cloud to encrypt/decrypt a stack's secrets.
 type cloudCrypter struct {
 	backend *cloudBackend
@@ -556,7 +618,33 @@ func (b *cloudBackend) GetStackCrypter(stackRef backend.StackReference) (config.
 		return nil, err
 	}
 
-	return &cloudCrypter{backend: b, stack: stack}, nil
+	// Stacks default to the service's own encryption, unless their settings name another provider. Commands may run
+	// outside of a project, in which case there are no settings to consult.
+	var provider string
+	if info, err := workspace.DetectProjectStack(stackRef.StackName()); err == nil {
+		provider = info.SecretsProvider
+	}
+
+	service := func() (config.Crypter, error) { return &cloudCrypter{backend: b, stack: stack}, nil }
+	return secrets.NewCrypter(provider, map[string]secrets.Factory{
+		"":                      service,
+		secrets.ServiceProvider: service,
+	})
+}
+
+// stateCrypter returns the value encrypter/decrypter that protects the stack's secret state. The underlying crypter is
+// only obtained when a secret is first encountered, and is shared by all uses of the stack within this process, so
+// that secrets keep the same ciphertext each time the stack's state is saved.
+func (b *cloudBackend) stateCrypter(stackRef backend.StackReference) config.Crypter {
+	b.cryptersLock.Lock()
+	defer b.cryptersLock.Unlock()
+
+	crypter, has := b.crypters[stackRef.String()]
+	if !has {
+		crypter = secrets.NewCachingCrypter(func() (config.Crypter, error) { return b.GetStackCrypter(stackRef) })
+		b.crypters[stackRef.String()] = crypter
+	}
+	return crypter
 }
 
 var (
@@ -662,7 +750,7 @@ func (b *cloudBackend) PreviewThenPrompt(
 	}
 
 	// If there are no changes, or we're auto-approving or just previewing, we can skip the confirmation prompt.
-	if opts.AutoApprove || updateKind == client.UpdateKindPreview {
+	if opts.AutoApprove || opts.PreviewOnly || updateKind == client.UpdateKindPreview {
 		return changes, nil
 	}
 
@@ -732,7 +820,7 @@ func (b *cloudBackend) PreviewThenPromptThenExecute(
 
 	// Preview the operation to the user and ask them if they want to proceed.
 	changes, err := b.PreviewThenPrompt(ctx, updateKind, stack, pkg, root, m, opts, scopes)
-	if err != nil || updateKind == client.UpdateKindPreview {
+	if err != nil || opts.PreviewOnly || updateKind == client.UpdateKindPreview {
 		return changes, err
 	}
 
@@ -835,11 +923,14 @@ func (b *cloudBackend) updateStack(
 	callerEventsOpt chan<- engine.Event, dryRun bool, persist bool,
 	scopes backend.CancellationScopeSource) (engine.ResourceChanges, error) {
 
-	// Print a banner so it's clear this is going to the cloud.
-	actionLabel := getActionLabel(string(action), dryRun)
-	fmt.Printf(
-		opts.Display.Color.Colorize(colors.BrightMagenta+"%s stack '%s'"+colors.Reset+"\n"),
-		actionLabel, stack.Name())
+	// Print a banner so it's clear this is going to the cloud, unless stdout is reserved for the update's JSON
+	// serialization.
+	if !opts.Display.JSONDisplay {
+		actionLabel := getActionLabel(string(action), dryRun)
+		fmt.Printf(
+			opts.Display.Color.Colorize(colors.BrightMagenta+"%s stack '%s'"+colors.Reset+"\n"),
+			actionLabel, stack.Name())
+	}
 
 	// Create an update object if we will persist the results, e.g. when not doing a local preview.
 	var update client.UpdateIdentifier
@@ -862,7 +953,7 @@ func (b *cloudBackend) updateStack(
 		} else {
 			link = b.CloudConsoleURL(base, "previews", update.UpdateID)
 		}
-		if link != "" {
+		if link != "" && !opts.Display.JSONDisplay {
 			defer func() {
 				fmt.Printf(
 					opts.Display.Color.Colorize(
@@ -915,7 +1006,7 @@ func (b *cloudBackend) runEngineAction(
 		return nil, err
 	}
 
-	persister := b.newSnapshotPersister(ctx, u.update, u.tokenSource)
+	persister := b.newSnapshotPersister(ctx, u.update, u.tokenSource, b.stateCrypter(stackRef))
 	manager := backend.NewSnapshotManager(persister, u.GetTarget().Snapshot)
 	displayEvents := make(chan engine.Event)
 	displayDone := make(chan bool)
@@ -963,11 +1054,14 @@ func (b *cloudBackend) runEngineAction(
 	}
 
 	// Wait for the display to finish showing all the events.
-	<-displayDone
+	displayOK := <-displayDone
 	close(engineEvents)
 	close(displayEvents)
 	close(displayDone)
 	contract.IgnoreClose(manager)
+	if !displayOK && err == nil {
+		err = errors.New("failed to write the update's output")
+	}
 
 	// Make sure that the goroutine writing to displayEvents and callerEventsOpt
 	// has exited before proceeding
@@ -1037,6 +1131,7 @@ func (b *cloudBackend) GetHistory(ctx context.Context, stackRef backend.StackRef
 			StartTime:       update.StartTime,
 			EndTime:         update.EndTime,
 			ResourceChanges: convertResourceChanges(update.ResourceChanges),
+			Version:         update.Version,
 		})
 	}
 
@@ -1071,9 +1166,14 @@ func convertConfig(apiConfig map[string]apitype.ConfigValue) (config.Map, error)
 		if err != nil {
 			return nil, err
 		}
-		if rawV.Secret {
+		switch {
+		case rawV.Object && rawV.Secret:
+			c[k] = config.NewSecureObjectValue(rawV.String)
+		case rawV.Object:
+			c[k] = config.NewObjectValue(rawV.String)
+		case rawV.Secret:
 			c[k] = config.NewSecureValue(rawV.String)
-		} else {
+		default:
 			c[k] = config.NewValue(rawV.String)
 		}
 	}
@@ -1114,6 +1214,22 @@ func (b *cloudBackend) ExportDeployment(ctx context.Context,
 	return &deployment, nil
 }
 
+func (b *cloudBackend) ExportDeploymentVersion(ctx context.Context, stackRef backend.StackReference,
+	version int) (*apitype.UntypedDeployment, error) {
+
+	stack, err := b.getCloudStackIdentifier(stackRef)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment, err := b.client.ExportStackDeploymentVersion(ctx, stack, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deployment, nil
+}
+
 func (b *cloudBackend) ImportDeployment(ctx context.Context, stackRef backend.StackReference,
 	deployment *apitype.UntypedDeployment) error {
 
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -166,9 +167,14 @@ func (pc *Client) GetLatestConfiguration(ctx context.Context, stackID StackIdent
 		if err != nil {
 			return nil, err
 		}
-		if v.Secret {
+		switch {
+		case v.Object && v.Secret:
+			cfg[newKey] = config.NewSecureObjectValue(v.String)
+		case v.Object:
+			cfg[newKey] = config.NewObjectValue(v.String)
+		case v.Secret:
 			cfg[newKey] = config.NewSecureValue(v.String)
-		} else {
+		default:
 			cfg[newKey] = config.NewValue(v.String)
 		}
 	}
@@ -228,6 +234,12 @@ func (pc *Client) DeleteStack(ctx context.Context, stack StackIdentifier, force
 	return false, pc.restCall(ctx, "DELETE", path, nil, nil, nil)
 }
 
+// RenameStack renames the indicated stack.
+func (pc *Client) RenameStack(ctx context.Context, stack StackIdentifier, newName string) error {
+	req := apitype.RenameStackRequest{NewName: newName}
+	return pc.restCall(ctx, "POST", getStackPath(stack, "rename"), nil, &req, nil)
+}
+
 // EncryptValue encrypts a plaintext value in the context of the indicated stack.
 func (pc *Client) EncryptValue(ctx context.Context, stack StackIdentifier, plaintext []byte) ([]byte, error) {
 	req := apitype.EncryptValueRequest{Plaintext: plaintext}
@@ -287,6 +299,20 @@ func (pc *Client) ExportStackDeployment(ctx context.Context,
 	return apitype.UntypedDeployment(resp), nil
 }
 
+// ExportStackDeploymentVersion exports the deployment produced by the given version of the indicated stack as a raw
+// JSON message.
+func (pc *Client) ExportStackDeploymentVersion(ctx context.Context, stack StackIdentifier,
+	version int) (apitype.UntypedDeployment, error) {
+
+	var resp apitype.ExportStackResponse
+	exportPath := getStackPath(stack, "export", strconv.Itoa(version))
+	if err := pc.restCall(ctx, "GET", exportPath, nil, nil, &resp); err != nil {
+		return apitype.UntypedDeployment{}, err
+	}
+
+	return apitype.UntypedDeployment(resp), nil
+}
+
 // ImportStackDeployment imports a new deployment into the indicated stack.
 func (pc *Client) ImportStackDeployment(ctx context.Context, stack StackIdentifier,
 	deployment *apitype.UntypedDeployment) (UpdateIdentifier, error) {
@@ -320,6 +346,7 @@ func (pc *Client) CreateUpdate(
 		wireConfig[k.Namespace()+":config:"+k.Name()] = apitype.ConfigValue{
 			String: v,
 			Secret: cv.Secure(),
+			Object: cv.Object(),
 		}
 	}
 
@@ -25,5 +25,8 @@ type DisplayOptions struct {
 	SummaryDiff          bool                // If the diff display should be summarized
 	IsInteractive        bool                // If we should display things interactively
 	DiffDisplay          bool                // true if we should display things as a rich diff
+	JSONDisplay          bool                // true if we should emit the entire operation as a single JSON document
+	EventLog             *EventLog           // an optional log to which every engine event is written.
+	DriftReport          *DriftReport        // an optional report in which any drift observed by a refresh is recorded.
 	Debug                bool
 }
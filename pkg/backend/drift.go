@@ -0,0 +1,93 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"sync"
+
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+// ResourceDrift describes how the live state of a single resource differs from the state recorded in its stack's
+// checkpoint.
+type ResourceDrift struct {
+	URN     resource.URN         // the resource's URN.
+	Type    tokens.Type          // the resource's type.
+	Deleted bool                 // true if the resource no longer exists.
+	Diff    *resource.ObjectDiff // the difference between the checkpoint's outputs and the live outputs, if any.
+}
+
+// DriftReport collects the resources whose live state has drifted from their stack's checkpoint, as observed by the
+// engine events of a refresh.
+type DriftReport struct {
+	m         sync.Mutex
+	resources []ResourceDrift
+}
+
+// NewDriftReport creates a new, empty drift report.
+func NewDriftReport() *DriftReport {
+	return &DriftReport{}
+}
+
+// Record inspects the given engine event and records any drift that it describes.
+func (r *DriftReport) Record(e engine.Event) {
+	if e.Type != engine.ResourcePreEvent {
+		return
+	}
+	p, ok := e.Payload.(engine.ResourcePreEventPayload)
+	if !ok {
+		return
+	}
+
+	// A refresh deletes resources that no longer exist, and updates the outputs of those whose live state differs
+	// from the checkpoint. Any other steps leave the checkpoint as it was.
+	md := p.Metadata
+	var drift ResourceDrift
+	switch {
+	case md.Old == nil:
+		return
+	case md.Op == deploy.OpDelete:
+		drift = ResourceDrift{URN: md.URN, Type: md.Type, Deleted: true}
+	case md.Op != deploy.OpSame && md.New != nil:
+		diff := md.Old.Outputs.Diff(md.New.Outputs)
+		if diff == nil {
+			return
+		}
+		drift = ResourceDrift{URN: md.URN, Type: md.Type, Diff: diff}
+	default:
+		return
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.resources = append(r.resources, drift)
+}
+
+// Resources returns the drifted resources recorded by this report, in the order in which they were observed.
+func (r *DriftReport) Resources() []ResourceDrift {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return append([]ResourceDrift(nil), r.resources...)
+}
+
+// HasDrift returns true if any resource has drifted from the checkpoint.
+func (r *DriftReport) HasDrift() bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return len(r.resources) > 0
+}
@@ -0,0 +1,82 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+func newRefreshEvent(op deploy.StepOp, urn resource.URN, olds, news map[string]interface{}) engine.Event {
+	md := engine.StepEventMetadata{Op: op, URN: urn, Type: urn.Type()}
+	if olds != nil {
+		md.Old = &engine.StepEventStateMetadata{URN: urn, Type: urn.Type(),
+			Outputs: resource.NewPropertyMapFromMap(olds)}
+	}
+	if news != nil {
+		md.New = &engine.StepEventStateMetadata{URN: urn, Type: urn.Type(),
+			Outputs: resource.NewPropertyMapFromMap(news)}
+	}
+	return engine.Event{Type: engine.ResourcePreEvent, Payload: engine.ResourcePreEventPayload{
+		Metadata: md,
+		Planning: true,
+	}}
+}
+
+func TestDriftReport(t *testing.T) {
+	resA := resource.URN("urn:pulumi:test::test::pkgA:m:typA::resA")
+	resB := resource.URN("urn:pulumi:test::test::pkgA:m:typA::resB")
+	resC := resource.URN("urn:pulumi:test::test::pkgA:m:typA::resC")
+
+	report := NewDriftReport()
+	assert.False(t, report.HasDrift())
+
+	events := []engine.Event{
+		{Type: engine.PreludeEvent, Payload: engine.PreludeEventPayload{IsPreview: true}},
+		newRefreshEvent(deploy.OpSame, resA, map[string]interface{}{"foo": "bar"},
+			map[string]interface{}{"foo": "bar"}),
+		newRefreshEvent(deploy.OpUpdate, resB, map[string]interface{}{"foo": "bar", "baz": 1},
+			map[string]interface{}{"foo": "qux", "zed": true}),
+		newRefreshEvent(deploy.OpDelete, resC, map[string]interface{}{"foo": "bar"}, nil),
+		{Type: engine.SummaryEvent, Payload: engine.SummaryEventPayload{IsPreview: true}},
+	}
+	for _, e := range events {
+		report.Record(e)
+	}
+
+	// Only the updated and deleted resources have drifted.
+	assert.True(t, report.HasDrift())
+	resources := report.Resources()
+	if !assert.Len(t, resources, 2) {
+		return
+	}
+
+	assert.Equal(t, resB, resources[0].URN)
+	assert.False(t, resources[0].Deleted)
+	diff := resources[0].Diff
+	assert.True(t, diff.Updated("foo"))
+	assert.Equal(t, "qux", diff.Updates["foo"].New.StringValue())
+	assert.True(t, diff.Deleted("baz"))
+	assert.True(t, diff.Added("zed"))
+
+	assert.Equal(t, resC, resources[1].URN)
+	assert.True(t, resources[1].Deleted)
+	assert.Nil(t, resources[1].Diff)
+}
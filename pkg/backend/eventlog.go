@@ -0,0 +1,272 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// EventLog records engine events as newline-delimited JSON, one apitype.EngineEvent per line. A single log may be
+// shared by several operations (e.g. a preview followed by an update); sequence numbers increase across all of them.
+// The log's file is opened on the first write after the log is created or closed, so each operation may close the log
+// once it is finished to ensure that all of its events have been flushed.
+type EventLog struct {
+	m        sync.Mutex
+	open     func() (io.WriteCloser, error)
+	w        io.WriteCloser
+	encoder  *json.Encoder
+	sequence int
+}
+
+// NewEventLog creates a new event log that writes to the file at the given path, truncating any existing contents.
+func NewEventLog(path string) (*EventLog, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating event log '%v'", path)
+	}
+	if err = f.Close(); err != nil {
+		return nil, errors.Wrapf(err, "creating event log '%v'", path)
+	}
+	return newEventLog(func() (io.WriteCloser, error) {
+		return os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	}), nil
+}
+
+func newEventLog(open func() (io.WriteCloser, error)) *EventLog {
+	return &EventLog{open: open}
+}
+
+// Write records the given engine event in the log.
+func (l *EventLog) Write(event engine.Event) error {
+	apiEvent, err := ConvertEngineEvent(event)
+	if err != nil {
+		return err
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if l.w == nil {
+		w, err := l.open()
+		if err != nil {
+			return errors.Wrap(err, "opening event log")
+		}
+		l.w, l.encoder = w, json.NewEncoder(w)
+	}
+
+	apiEvent.Sequence, apiEvent.Timestamp = l.sequence, int(time.Now().Unix())
+	l.sequence++
+	return l.encoder.Encode(apiEvent)
+}
+
+// Close flushes and closes the log's underlying file. The log may continue to be written to afterwards, in which case
+// the file is reopened and new events are appended to it.
+func (l *EventLog) Close() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if l.w == nil {
+		return nil
+	}
+	w := l.w
+	l.w, l.encoder = nil, nil
+	return w.Close()
+}
+
+// ConvertEngineEvent converts an engine event into its wire format. The sequence number and timestamp of the
+// resulting event are left unset.
+func ConvertEngineEvent(e engine.Event) (apitype.EngineEvent, error) {
+	var apiEvent apitype.EngineEvent
+
+	// Error to return if the payload doesn't match the expected type.
+	handleInvalidPayload := func() (apitype.EngineEvent, error) {
+		return apiEvent, errors.Errorf("unexpected payload of type %T for event type %v", e.Payload, e.Type)
+	}
+
+	switch e.Type {
+	case engine.CancelEvent:
+		apiEvent.CancelEvent = &apitype.CancelEvent{}
+
+	case engine.StdoutColorEvent:
+		p, ok := e.Payload.(engine.StdoutEventPayload)
+		if !ok {
+			return handleInvalidPayload()
+		}
+		apiEvent.StdoutEvent = &apitype.StdoutEngineEvent{
+			Message: p.Message,
+			Color:   string(p.Color),
+		}
+
+	case engine.DiagEvent:
+		p, ok := e.Payload.(engine.DiagEventPayload)
+		if !ok {
+			return handleInvalidPayload()
+		}
+		apiEvent.DiagnosticEvent = &apitype.DiagnosticEvent{
+			URN:      string(p.URN),
+			Prefix:   p.Prefix,
+			Message:  p.Message,
+			Color:    string(p.Color),
+			Severity: string(p.Severity),
+			StreamID: int(p.StreamID),
+		}
+
+	case engine.PreludeEvent:
+		p, ok := e.Payload.(engine.PreludeEventPayload)
+		if !ok {
+			return handleInvalidPayload()
+		}
+		// Convert the config bag.
+		cfg := make(map[string]string)
+		for k, v := range p.Config {
+			cfg[k] = v
+		}
+		apiEvent.PreludeEvent = &apitype.PreludeEvent{
+			IsPreview: p.IsPreview,
+			Config:    cfg,
+		}
+
+	case engine.SummaryEvent:
+		p, ok := e.Payload.(engine.SummaryEventPayload)
+		if !ok {
+			return handleInvalidPayload()
+		}
+		// Convert the resource changes.
+		changes := make(map[string]int)
+		for op, count := range p.ResourceChanges {
+			changes[string(op)] = count
+		}
+		// Convert the policy violations.
+		var violations []apitype.PolicyViolation
+		for _, v := range p.PolicyViolations {
+			violations = append(violations, apitype.PolicyViolation{
+				Analyzer:         string(v.Analyzer),
+				PolicyName:       v.PolicyName,
+				Description:      v.Description,
+				Severity:         v.Severity,
+				EnforcementLevel: string(v.EnforcementLevel),
+				URN:              string(v.URN),
+				Property:         string(v.Property),
+				Reason:           v.Reason,
+			})
+		}
+		apiEvent.SummaryEvent = &apitype.SummaryEvent{
+			IsPreview:        p.IsPreview,
+			MaybeCorrupt:     p.MaybeCorrupt,
+			DurationSeconds:  int(p.Duration.Seconds()),
+			ResourceChanges:  changes,
+			PolicyViolations: violations,
+		}
+
+	case engine.ResourcePreEvent:
+		p, ok := e.Payload.(engine.ResourcePreEventPayload)
+		if !ok {
+			return handleInvalidPayload()
+		}
+		apiEvent.ResourcePreEvent = &apitype.ResourcePreEvent{
+			Metadata: convertStepEventMetadata(p.Metadata),
+			Planning: p.Planning,
+		}
+
+	case engine.ResourceOutputsEvent:
+		p, ok := e.Payload.(engine.ResourceOutputsEventPayload)
+		if !ok {
+			return handleInvalidPayload()
+		}
+		apiEvent.ResOutputsEvent = &apitype.ResOutputsEvent{
+			Metadata: convertStepEventMetadata(p.Metadata),
+			Planning: p.Planning,
+		}
+
+	case engine.ResourceOperationFailed:
+		p, ok := e.Payload.(engine.ResourceOperationFailedPayload)
+		if !ok {
+			return handleInvalidPayload()
+		}
+		apiEvent.ResOpFailedEvent = &apitype.ResOpFailedEvent{
+			Metadata: convertStepEventMetadata(p.Metadata),
+			Status:   int(p.Status),
+			Steps:    p.Steps,
+		}
+
+	default:
+		return apiEvent, errors.Errorf("unknown event type %q", e.Type)
+	}
+
+	return apiEvent, nil
+}
+
+func convertStepEventMetadata(md engine.StepEventMetadata) apitype.StepEventMetadata {
+	keys := make([]string, len(md.Keys))
+	for i, k := range md.Keys {
+		keys[i] = string(k)
+	}
+
+	return apitype.StepEventMetadata{
+		Op:   string(md.Op),
+		URN:  string(md.URN),
+		Type: string(md.Type),
+
+		Old: convertStepEventStateMetadata(md.Old),
+		New: convertStepEventStateMetadata(md.New),
+
+		Keys:     keys,
+		Forced:   md.Forced,
+		Logical:  md.Logical,
+		Provider: md.Provider,
+	}
+}
+
+func convertStepEventStateMetadata(md *engine.StepEventStateMetadata) *apitype.StepEventStateMetadata {
+	if md == nil {
+		return nil
+	}
+
+	return &apitype.StepEventStateMetadata{
+		Type: string(md.Type),
+		URN:  string(md.URN),
+
+		Custom:   md.Custom,
+		Delete:   md.Delete,
+		ID:       string(md.ID),
+		Parent:   string(md.Parent),
+		Protect:  md.Protect,
+		Inputs:   serializeProperties(md.Inputs),
+		Outputs:  serializeProperties(md.Outputs),
+		Provider: md.Provider,
+	}
+}
+
+func serializeProperties(props resource.PropertyMap) map[string]interface{} {
+	if props == nil {
+		return nil
+	}
+	// Event logs are not protected, so secrets are masked rather than encrypted.
+	serialized, err := stack.SerializeProperties(props, nil)
+	contract.AssertNoError(err)
+	return serialized
+}
@@ -0,0 +1,115 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+type nopCloser struct {
+	*bytes.Buffer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func TestEventLog(t *testing.T) {
+	urn := resource.URN("urn:pulumi:test::test::pkgA:m:typA::resA")
+
+	buf := &bytes.Buffer{}
+	log := newEventLog(func() (io.WriteCloser, error) { return nopCloser{buf}, nil })
+
+	events := []engine.Event{
+		{Type: engine.PreludeEvent, Payload: engine.PreludeEventPayload{
+			IsPreview: true,
+			Config:    map[string]string{"test:foo": "bar"},
+		}},
+		{Type: engine.ResourcePreEvent, Payload: engine.ResourcePreEventPayload{
+			Metadata: engine.StepEventMetadata{
+				Op:   deploy.OpCreate,
+				URN:  urn,
+				Type: urn.Type(),
+				New: &engine.StepEventStateMetadata{
+					Type:   urn.Type(),
+					URN:    urn,
+					Custom: true,
+					Inputs: resource.NewPropertyMapFromMap(map[string]interface{}{"foo": "bar"}),
+				},
+				Logical: true,
+			},
+			Planning: true,
+		}},
+		{Type: engine.DiagEvent, Payload: engine.DiagEventPayload{
+			URN:      urn,
+			Message:  "hello",
+			Severity: diag.Warning,
+		}},
+		{Type: engine.SummaryEvent, Payload: engine.SummaryEventPayload{
+			IsPreview:       true,
+			ResourceChanges: engine.ResourceChanges{deploy.OpCreate: 1},
+		}},
+		{Type: engine.CancelEvent},
+	}
+	for _, e := range events {
+		assert.NoError(t, log.Write(e))
+	}
+	assert.NoError(t, log.Close())
+
+	// Each event should have been written on its own line with an increasing sequence number.
+	var apiEvents []apitype.EngineEvent
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var e apitype.EngineEvent
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		apiEvents = append(apiEvents, e)
+	}
+	assert.Len(t, apiEvents, len(events))
+	for i, e := range apiEvents {
+		assert.Equal(t, i, e.Sequence)
+	}
+
+	assert.NotNil(t, apiEvents[0].PreludeEvent)
+	assert.Equal(t, map[string]string{"test:foo": "bar"}, apiEvents[0].PreludeEvent.Config)
+
+	assert.NotNil(t, apiEvents[1].ResourcePreEvent)
+	assert.Equal(t, "create", apiEvents[1].ResourcePreEvent.Metadata.Op)
+	assert.Equal(t, string(urn), apiEvents[1].ResourcePreEvent.Metadata.URN)
+	assert.Nil(t, apiEvents[1].ResourcePreEvent.Metadata.Old)
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, apiEvents[1].ResourcePreEvent.Metadata.New.Inputs)
+
+	assert.NotNil(t, apiEvents[2].DiagnosticEvent)
+	assert.Equal(t, "warning", apiEvents[2].DiagnosticEvent.Severity)
+
+	assert.NotNil(t, apiEvents[3].SummaryEvent)
+	assert.Equal(t, map[string]int{"create": 1}, apiEvents[3].SummaryEvent.ResourceChanges)
+
+	assert.NotNil(t, apiEvents[4].CancelEvent)
+}
+
+func TestConvertEngineEventInvalidPayload(t *testing.T) {
+	_, err := ConvertEngineEvent(engine.Event{Type: engine.DiagEvent, Payload: "oops"})
+	assert.Error(t, err)
+}
@@ -18,17 +18,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"os/user"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/local/blob"
 	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/encoding"
 	"github.com/pulumi/pulumi/pkg/engine"
@@ -36,6 +38,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/secrets"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/logging"
@@ -48,13 +51,26 @@ const localBackendURLPrefix = "local://"
 // Backend extends the base backend interface with specific information about local backends.
 type Backend interface {
 	backend.Backend
-	local() // at the moment, no local specific info, so just use a marker function.
+	local() // a marker function, to distinguish local backends from other backends.
+
+	// ListBackups returns the checkpoint backups available for the given stack, most recent first.
+	ListBackups(ctx context.Context, stackRef backend.StackReference) ([]CheckpointBackup, error)
+	// RestoreBackup replaces the given stack's checkpoint with the named backup.
+	RestoreBackup(ctx context.Context, stackRef backend.StackReference, name string) error
+	// Rollback returns the given stack's resources to the state recorded by the given version of the stack, as
+	// numbered by GetHistory, by creating, updating, and deleting resources as necessary.
+	Rollback(ctx context.Context, stackRef backend.StackReference, proj *workspace.Project, root string,
+		version int, m backend.UpdateMetadata, opts backend.UpdateOptions,
+		scopes backend.CancellationScopeSource) (engine.ResourceChanges, error)
 }
 
 type localBackend struct {
-	d         diag.Sink
-	url       string
-	stateRoot string
+	d      diag.Sink
+	url    string
+	bucket blob.Bucket // the bucket in which all state is stored.
+
+	crypters     map[tokens.QName]config.Crypter // the crypters protecting each stack's secret state.
+	cryptersLock sync.Mutex
 }
 
 type localBackendReference struct {
@@ -79,19 +95,49 @@ func stateRootFromLocalURL(localURL string) string {
 	return localURL[len(localBackendURLPrefix):]
 }
 
+// IsLocalBackendURL returns true if the given URL refers to a local backend: either a directory on this machine
+// (local://<path> or file://<path>) or a bucket in an S3-compatible object store (s3://<bucket>[/<prefix>]).
 func IsLocalBackendURL(url string) bool {
-	return strings.HasPrefix(url, localBackendURLPrefix)
+	return strings.HasPrefix(url, localBackendURLPrefix) || blob.IsBucketURL(url)
 }
 
-func New(d diag.Sink, localURL string) Backend {
-	return &localBackend{d: d, url: localURL, stateRoot: stateRootFromLocalURL(localURL)}
+func New(d diag.Sink, localURL string) (Backend, error) {
+	var bucket blob.Bucket
+	if strings.HasPrefix(localURL, localBackendURLPrefix) {
+		bucket = blob.NewFileBucket(stateRootFromLocalURL(localURL))
+	} else {
+		var err error
+		if bucket, err = blob.OpenBucket(localURL); err != nil {
+			return nil, err
+		}
+	}
+	return &localBackend{
+		d:        d,
+		url:      localURL,
+		bucket:   bucket,
+		crypters: make(map[tokens.QName]config.Crypter),
+	}, nil
 }
 
 func Login(d diag.Sink, localURL string) (Backend, error) {
-	return New(d, localURL), workspace.StoreAccessToken(localURL, "", true)
+	b, err := New(d, localURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure that the bucket is accessible before remembering it.
+	if _, err = b.(*localBackend).bucket.List(workspace.StackDir); err != nil {
+		return nil, errors.Wrapf(err, "could not access '%s'", localURL)
+	}
+	return b, workspace.StoreAccessToken(localURL, "", true)
 }
 
 func (b *localBackend) Name() string {
+	// Stacks stored on this machine are named after it; shared buckets are named after themselves.
+	if !strings.HasPrefix(b.url, localBackendURLPrefix) && !strings.HasPrefix(b.url, blob.FileURLPrefix) {
+		return b.bucket.String()
+	}
+
 	name, err := os.Hostname()
 	contract.IgnoreError(err)
 	if name == "" {
@@ -143,7 +189,7 @@ func (b *localBackend) GetStack(ctx context.Context, stackRef backend.StackRefer
 	stackName := stackRef.StackName()
 	config, snapshot, path, err := b.getStack(stackName)
 	switch {
-	case os.IsNotExist(errors.Cause(err)):
+	case blob.IsNotExist(err):
 		return nil, nil
 	case err != nil:
 		return nil, err
@@ -172,6 +218,12 @@ func (b *localBackend) ListStacks(ctx context.Context, projectFilter *tokens.Pac
 
 func (b *localBackend) RemoveStack(ctx context.Context, stackRef backend.StackReference, force bool) (bool, error) {
 	stackName := stackRef.StackName()
+	unlock, err := b.lockStack(stackName, "removing")
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
 	_, snapshot, _, err := b.getStack(stackName)
 	if err != nil {
 		return false, err
@@ -185,8 +237,79 @@ func (b *localBackend) RemoveStack(ctx context.Context, stackRef backend.StackRe
 	return false, b.removeStack(stackName)
 }
 
+func (b *localBackend) RenameStack(ctx context.Context, stackRef backend.StackReference,
+	newName tokens.QName) error {
+
+	stackName := stackRef.StackName()
+	unlock, err := b.lockStack(stackName, "renaming")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	config, snap, _, err := b.getStack(stackName)
+	if err != nil {
+		return err
+	}
+
+	if err = backend.ValidateStackProperties(string(newName), nil); err != nil {
+		return errors.Wrap(err, "validating stack properties")
+	}
+	switch _, err = b.bucket.Get(b.stackKey(newName)); {
+	case err == nil:
+		return &backend.StackAlreadyExistsError{StackName: string(newName)}
+	case !blob.IsNotExist(err):
+		return errors.Wrapf(err, "checking for an existing stack named '%v'", newName)
+	}
+
+	// The renamed stack's secrets remain encrypted just as they were, so make sure that they are protected by the same
+	// secrets provider under the new name.
+	if err = b.carryOverSecrets(stackName, newName); err != nil {
+		return err
+	}
+
+	// Rewrite the checkpoint's URNs and save it under the new name.
+	if err = snap.RenameStack(newName); err != nil {
+		return err
+	}
+	if _, err = b.saveStack(newName, config, snap); err != nil {
+		return err
+	}
+
+	// Move the stack's history and backups along with it.
+	for _, dirs := range [][2]string{
+		{b.historyDirectory(stackName), b.historyDirectory(newName)},
+		{b.backupDirectory(stackName), b.backupDirectory(newName)},
+	} {
+		files, listErr := b.bucket.List(dirs[0])
+		if listErr != nil {
+			return listErr
+		}
+		for _, file := range files {
+			dest := path.Join(dirs[1], path.Base(file.Key))
+			if err = b.bucket.Rename(file.Key, dest); err != nil {
+				return errors.Wrapf(err, "moving '%v' to '%v'", file.Key, dest)
+			}
+		}
+	}
+
+	// Finally, retire the old checkpoint. As with removal, we leave a backup of it behind.
+	b.backupTarget(b.stackKey(stackName), false /*keepOriginal*/)
+	return b.removeJournal(stackName)
+}
+
 func (b *localBackend) GetStackCrypter(stackRef backend.StackReference) (config.Crypter, error) {
-	return symmetricCrypter(stackRef.StackName())
+	crypter, err := stackCrypter(stackRef.StackName())
+	if err != nil {
+		return nil, err
+	}
+
+	// The stack's secrets provider may have changed since its state was last read, so replace the crypter that
+	// protects its state with the one that was just obtained.
+	b.cryptersLock.Lock()
+	defer b.cryptersLock.Unlock()
+	b.crypters[stackRef.StackName()] = secrets.NewCachingCrypter(func() (config.Crypter, error) { return crypter, nil })
+	return crypter, nil
 }
 
 func (b *localBackend) GetLatestConfiguration(ctx context.Context,
@@ -232,7 +355,11 @@ func (b *localBackend) Update(
 func (b *localBackend) Refresh(
 	_ context.Context, stackRef backend.StackReference, proj *workspace.Project, root string, m backend.UpdateMetadata,
 	opts backend.UpdateOptions, scopes backend.CancellationScopeSource) (engine.ResourceChanges, error) {
-	return b.performEngineOp("refreshing", backend.RefreshUpdate,
+	op := "refreshing"
+	if opts.PreviewOnly {
+		op = "previewing"
+	}
+	return b.performEngineOp(op, backend.RefreshUpdate,
 		stackRef.StackName(), proj, root, m, opts, scopes, engine.Refresh)
 }
 
@@ -243,19 +370,66 @@ func (b *localBackend) Destroy(
 		stackRef.StackName(), proj, root, m, opts, scopes, engine.Destroy)
 }
 
+func (b *localBackend) Rollback(
+	_ context.Context, stackRef backend.StackReference, proj *workspace.Project, root string, version int,
+	m backend.UpdateMetadata, opts backend.UpdateOptions,
+	scopes backend.CancellationScopeSource) (engine.ResourceChanges, error) {
+
+	stackName := stackRef.StackName()
+	chk, err := b.getHistoricalCheckpoint(stackName, version)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := stack.DeserializeCheckpoint(chk, b.stateCrypter(stackName))
+	if err != nil {
+		return nil, err
+	}
+
+	// If the stack has been renamed since, its URNs must be brought up to date before they can be compared.
+	if snap != nil && chk.Stack != stackName {
+		if err = snap.RenameStack(stackName); err != nil {
+			return nil, err
+		}
+	}
+	if snap != nil && !DisableIntegrityChecking {
+		if err = snap.VerifyIntegrity(); err != nil {
+			return nil, errors.Wrapf(err, "version %d: snapshot integrity failure; refusing to roll back to it",
+				version)
+		}
+	}
+
+	op := "rolling back"
+	if opts.PreviewOnly {
+		op = "previewing"
+	}
+	return b.performEngineOp(op, backend.RollbackUpdate, stackName, proj, root, m, opts, scopes,
+		func(u engine.UpdateInfo, ctx *engine.Context, opts engine.UpdateOptions,
+			dryRun bool) (engine.ResourceChanges, error) {
+			return engine.Rollback(u, ctx, opts, snap, dryRun)
+		})
+}
+
 type engineOpFunc func(engine.UpdateInfo, *engine.Context, engine.UpdateOptions, bool) (engine.ResourceChanges, error)
 
 func (b *localBackend) performEngineOp(op string, kind backend.UpdateKind,
 	stackName tokens.QName, proj *workspace.Project, root string, m backend.UpdateMetadata, opts backend.UpdateOptions,
 	scopes backend.CancellationScopeSource, performEngineOp engineOpFunc) (engine.ResourceChanges, error) {
 
+	// Lock the stack for the duration of the operation so that concurrent operations do not overwrite each other's
+	// checkpoints. The stack must be locked before its checkpoint is read.
+	unlock, err := b.lockStack(stackName, op)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	update, err := b.newUpdate(stackName, proj, root)
 	if err != nil {
 		return nil, err
 	}
 
 	events := make(chan engine.Event)
-	dryRun := (kind == backend.PreviewUpdate)
+	dryRun := (kind == backend.PreviewUpdate || opts.PreviewOnly)
 
 	cancelScope := scopes.NewScope(events, dryRun)
 	defer cancelScope.Close()
@@ -273,10 +447,13 @@ func (b *localBackend) performEngineOp(op string, kind backend.UpdateKind,
 	changes, updateErr := performEngineOp(update, engineCtx, opts.Engine, dryRun)
 	end := time.Now().Unix()
 
-	<-done
+	displayOK := <-done
 	close(events)
 	close(done)
 	contract.IgnoreClose(manager)
+	if !displayOK && updateErr == nil {
+		updateErr = errors.New("failed to write the update's output")
+	}
 
 	// Save update results.
 	result := backend.SucceededResult
@@ -367,7 +544,42 @@ func (b *localBackend) ExportDeployment(ctx context.Context,
 		snap = deploy.NewSnapshot(deploy.Manifest{}, nil, nil)
 	}
 
-	data, err := json.Marshal(stack.SerializeDeployment(snap))
+	dep, err := stack.SerializeDeployment(snap, b.stateCrypter(stackRef.StackName()))
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(dep)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apitype.UntypedDeployment{
+		Version:    2,
+		Deployment: json.RawMessage(data),
+	}, nil
+}
+
+func (b *localBackend) ExportDeploymentVersion(ctx context.Context, stackRef backend.StackReference,
+	version int) (*apitype.UntypedDeployment, error) {
+
+	chk, err := b.getHistoricalCheckpoint(stackRef.StackName(), version)
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := stack.DeserializeCheckpoint(chk, b.stateCrypter(stackRef.StackName()))
+	if err != nil {
+		return nil, err
+	}
+	if snap == nil {
+		snap = deploy.NewSnapshot(deploy.Manifest{}, nil, nil)
+	}
+
+	dep, err := stack.SerializeDeployment(snap, b.stateCrypter(stackRef.StackName()))
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(dep)
 	if err != nil {
 		return nil, err
 	}
@@ -382,12 +594,21 @@ func (b *localBackend) ImportDeployment(ctx context.Context, stackRef backend.St
 	deployment *apitype.UntypedDeployment) error {
 
 	stackName := stackRef.StackName()
-	config, _, _, err := b.getStack(stackName)
+	unlock, err := b.lockStack(stackName, "importing")
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	snap, err := stack.DeserializeUntypedDeployment(deployment)
+	// Only the existing checkpoint's configuration is kept, so there is no need to decrypt its state, which may be
+	// protected by a secrets provider that the stack no longer uses.
+	chk, err := b.getCheckpoint(stackName)
+	if err != nil {
+		return errors.Wrap(err, "failed to load checkpoint")
+	}
+	config := chk.Config
+
+	snap, err := stack.DeserializeUntypedDeployment(deployment, b.stateCrypter(stackName))
 	if err != nil {
 		return err
 	}
@@ -408,22 +629,15 @@ func (b *localBackend) getLocalStacks() ([]tokens.QName, error) {
 	var stacks []tokens.QName
 
 	// Read the stack directory.
-	path := b.stackPath("")
-
-	files, err := ioutil.ReadDir(path)
-	if err != nil && !os.IsNotExist(err) {
+	files, err := b.bucket.List(workspace.StackDir)
+	if err != nil {
 		return nil, errors.Errorf("could not read stacks: %v", err)
 	}
 
 	for _, file := range files {
-		// Ignore directories.
-		if file.IsDir() {
-			continue
-		}
-
 		// Skip files without valid extensions (e.g., *.bak files).
-		stackfn := file.Name()
-		ext := filepath.Ext(stackfn)
+		stackfn := path.Base(file.Key)
+		ext := path.Ext(stackfn)
 		if _, has := encoding.Marshalers[ext]; !has {
 			continue
 		}
@@ -0,0 +1,110 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/local/blob"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+)
+
+// CheckpointBackup describes a backup of a stack's checkpoint, taken before an update began.
+type CheckpointBackup struct {
+	Name string    // the name of the backup file, which can be passed to RestoreBackup.
+	Time time.Time // the time at which the backup was taken.
+}
+
+// ListBackups returns the checkpoint backups available for the given stack, most recent first.
+func (b *localBackend) ListBackups(ctx context.Context, stackRef backend.StackReference) ([]CheckpointBackup, error) {
+	stackName := stackRef.StackName()
+	files, err := b.bucket.List(b.backupDirectory(stackName))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []CheckpointBackup
+	for _, file := range files {
+		// Backups are named <stack>.<unix-nanoseconds><ext>; fall back to the modification time for anything else.
+		name := path.Base(file.Key)
+		t := file.ModTime
+		base := strings.TrimSuffix(name, path.Ext(name))
+		if i := strings.LastIndex(base, "."); i != -1 {
+			if nanos, parseErr := strconv.ParseInt(base[i+1:], 10, 64); parseErr == nil {
+				t = time.Unix(0, nanos)
+			}
+		}
+		backups = append(backups, CheckpointBackup{Name: name, Time: t})
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// RestoreBackup replaces the given stack's checkpoint with the named backup, as returned by ListBackups. The current
+// checkpoint is itself retained as a numbered backup alongside the stack's checkpoint.
+func (b *localBackend) RestoreBackup(ctx context.Context, stackRef backend.StackReference, name string) error {
+	stackName := stackRef.StackName()
+	if name == "" || path.Base(name) != name {
+		return errors.Errorf("invalid backup name '%s'", name)
+	}
+
+	byts, err := b.bucket.Get(path.Join(b.backupDirectory(stackName), name))
+	if err != nil {
+		if blob.IsNotExist(err) {
+			return errors.Errorf("no backup named '%s' found for stack '%s'", name, stackName)
+		}
+		return err
+	}
+	chk, err := stack.UnmarshalVersionedCheckpointToLatestCheckpoint(byts)
+	if err != nil {
+		return errors.Wrapf(err, "reading backup '%s'", name)
+	}
+	snap, err := stack.DeserializeCheckpoint(chk, b.stateCrypter(stackName))
+	if err != nil {
+		return errors.Wrapf(err, "reading backup '%s'", name)
+	}
+
+	// Backups are moved along with a stack when it is renamed, so the backup may refer to the stack's old name.
+	if snap != nil && chk.Stack != stackName {
+		if err = snap.RenameStack(stackName); err != nil {
+			return err
+		}
+	}
+	if snap != nil && !DisableIntegrityChecking {
+		if err = snap.VerifyIntegrity(); err != nil {
+			return errors.Wrapf(err, "backup '%s' failed integrity checks; refusing to restore it", name)
+		}
+	}
+
+	unlock, err := b.lockStack(stackName, "restoring")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	_, err = b.saveStack(stackName, chk.Config, snap)
+	return err
+}
@@ -0,0 +1,118 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/backend/local/blob"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+func testConfig(value string) config.Map {
+	return config.Map{config.MustMakeKey("test", "value"): config.NewValue(value)}
+}
+
+func TestCheckpointBackupRotation(t *testing.T) {
+	b, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	stack := tokens.QName("dev")
+	key := b.stackKey(stack)
+	for i := 0; i < checkpointBackupCount+3; i++ {
+		_, err := b.saveStack(stack, testConfig(string('a'+rune(i))), nil)
+		assert.NoError(t, err)
+	}
+
+	// The checkpoint itself reflects the latest save.
+	cfg, _, _, err := b.getStack(stack)
+	assert.NoError(t, err)
+	assert.Equal(t, testConfig(string('a'+rune(checkpointBackupCount+2))), cfg)
+
+	// Only a bounded number of backups are kept.
+	for i := 1; i <= checkpointBackupCount; i++ {
+		_, err = b.bucket.Get(backupTargetKey(key, i))
+		assert.NoError(t, err)
+	}
+	_, err = b.bucket.Get(backupTargetKey(key, checkpointBackupCount+1))
+	assert.True(t, blob.IsNotExist(err))
+
+	// Removing the stack retires its checkpoint to the most recent backup.
+	assert.NoError(t, b.removeStack(stack))
+	_, err = b.bucket.Get(key)
+	assert.True(t, blob.IsNotExist(err))
+	_, err = b.bucket.Get(backupTargetKey(key, 1))
+	assert.NoError(t, err)
+}
+
+func TestCheckpointBackupOncePerUpdate(t *testing.T) {
+	b, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	stack := tokens.QName("dev")
+	key := b.stackKey(stack)
+	_, err := b.saveStack(stack, testConfig("before"), nil)
+	assert.NoError(t, err)
+
+	// However many checkpoints an update saves, only the checkpoint that preceded it is backed up.
+	snap := deploy.NewSnapshot(deploy.Manifest{Time: time.Now()}, nil, nil)
+	persister := b.newSnapshotPersister(stack)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, persister.Save(snap))
+	}
+	_, err = b.bucket.Get(backupTargetKey(key, 1))
+	assert.NoError(t, err)
+	_, err = b.bucket.Get(backupTargetKey(key, 2))
+	assert.True(t, blob.IsNotExist(err))
+
+	// The next update backs up the checkpoint again.
+	assert.NoError(t, b.newSnapshotPersister(stack).Save(snap))
+	_, err = b.bucket.Get(backupTargetKey(key, 2))
+	assert.NoError(t, err)
+}
+
+func TestRestoreBackup(t *testing.T) {
+	b, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	stack := tokens.QName("dev")
+	ref := localBackendReference{name: stack}
+	_, err := b.saveStack(stack, testConfig("before"), nil)
+	assert.NoError(t, err)
+	assert.NoError(t, b.backupStack(stack))
+	_, err = b.saveStack(stack, testConfig("after"), nil)
+	assert.NoError(t, err)
+
+	backups, err := b.ListBackups(context.Background(), ref)
+	assert.NoError(t, err)
+	if !assert.Len(t, backups, 1) {
+		return
+	}
+
+	assert.NoError(t, b.RestoreBackup(context.Background(), ref, backups[0].Name))
+	cfg, _, _, err := b.getStack(stack)
+	assert.NoError(t, err)
+	assert.Equal(t, testConfig("before"), cfg)
+
+	// Backups must be named by their file name alone.
+	assert.Error(t, b.RestoreBackup(context.Background(), ref, "../"+backups[0].Name))
+	assert.Error(t, b.RestoreBackup(context.Background(), ref, "missing.json"))
+}
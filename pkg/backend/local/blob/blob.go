@@ -0,0 +1,111 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package blob defines a small interface to a store of blobs addressed by slash-separated keys, along with
+// implementations backed by the filesystem and by S3-compatible object stores. The local backend persists all of its
+// state through this interface, so that teams without access to the Pulumi Service can still share stacks.
+package blob
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotExist is returned when a blob does not exist.
+	ErrNotExist = errors.New("blob does not exist")
+	// ErrExists is returned by a conditional write when the blob it would have created already exists.
+	ErrExists = errors.New("blob already exists")
+)
+
+// IsNotExist returns true if the error, or the error it wraps, indicates that a blob does not exist.
+func IsNotExist(err error) bool {
+	return errors.Cause(err) == ErrNotExist
+}
+
+// IsExists returns true if the error, or the error it wraps, indicates that a conditional write failed because the
+// blob already exists.
+func IsExists(err error) bool {
+	return errors.Cause(err) == ErrExists
+}
+
+// Attributes describes a single blob in a bucket.
+type Attributes struct {
+	Key     string    // the blob's key.
+	Size    int64     // the blob's size, in bytes.
+	ModTime time.Time // the time at which the blob was last written.
+}
+
+// PutOptions controls the behavior of Bucket.Put.
+type PutOptions struct {
+	// IfNotExists causes the write to fail with ErrExists if the blob already exists. The check and the write are a
+	// single atomic operation, which makes such writes suitable for implementing locks.
+	IfNotExists bool
+}
+
+// Bucket is a store of blobs. Keys are slash-separated paths relative to the root of the bucket, e.g. "a/b/c.json".
+// Writes replace a blob in its entirety: readers observe either the old or the new contents of a blob, never a mix.
+type Bucket interface {
+	// Get returns the contents of the blob with the given key, or ErrNotExist if there is no such blob.
+	Get(key string) ([]byte, error)
+	// Put creates or replaces the blob with the given key.
+	Put(key string, data []byte, opts PutOptions) error
+	// List returns the blobs that lie directly within the given directory--that is, whose keys begin with dir + "/"
+	// and contain no further slashes--sorted by key. Listing a directory that does not exist returns no blobs.
+	List(dir string) ([]Attributes, error)
+	// Delete removes the blob with the given key. Deleting a blob that does not exist is not an error.
+	Delete(key string) error
+	// Rename moves the blob with the given key to a new key, replacing any blob already there. If there is no blob
+	// with the source key, ErrNotExist is returned. Renames are only atomic in buckets that support them natively;
+	// in S3, a rename is a copy followed by a delete.
+	Rename(from, to string) error
+
+	// String returns a description of the bucket, suitable for display.
+	String() string
+}
+
+// IsBucketURL returns true if the given URL refers to a bucket that OpenBucket knows how to open.
+func IsBucketURL(bucketURL string) bool {
+	return strings.HasPrefix(bucketURL, FileURLPrefix) || strings.HasPrefix(bucketURL, S3URLPrefix)
+}
+
+// OpenBucket opens the bucket identified by the given URL. Two kinds of URLs are supported:
+//
+//	file://<path>                   a directory on the local filesystem.
+//	s3://<bucket>[/<prefix>]        a bucket in an S3-compatible object store; see NewS3Bucket for details.
+func OpenBucket(bucketURL string) (Bucket, error) {
+	switch {
+	case strings.HasPrefix(bucketURL, FileURLPrefix):
+		return NewFileBucket(bucketURL[len(FileURLPrefix):]), nil
+	case strings.HasPrefix(bucketURL, S3URLPrefix):
+		u, err := url.Parse(bucketURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing bucket URL '%s'", bucketURL)
+		}
+		return NewS3Bucket(u)
+	default:
+		return nil, errors.Errorf("unsupported bucket URL '%s'", bucketURL)
+	}
+}
+
+// join joins the given directory and name into a key.
+func join(dir, name string) string {
+	if dir == "" {
+		return name
+	}
+	return strings.TrimSuffix(dir, "/") + "/" + name
+}
@@ -0,0 +1,117 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// testBucket exercises the behavior that every Bucket implementation must provide.
+func testBucket(t *testing.T, b Bucket) {
+	// Blobs that have not been written do not exist.
+	_, err := b.Get("stacks/dev.json")
+	assert.True(t, IsNotExist(err))
+	blobs, err := b.List("stacks")
+	assert.NoError(t, err)
+	assert.Empty(t, blobs)
+
+	// Blobs can be written, read, and replaced.
+	assert.NoError(t, b.Put("stacks/dev.json", []byte("v1"), PutOptions{}))
+	assert.NoError(t, b.Put("stacks/dev.json", []byte("v2"), PutOptions{}))
+	byts, err := b.Get("stacks/dev.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", string(byts))
+
+	// Conditional writes only succeed if the blob does not already exist.
+	assert.True(t, IsExists(b.Put("stacks/dev.json", []byte("v3"), PutOptions{IfNotExists: true})))
+	assert.NoError(t, b.Put("locks/dev.json", []byte("lock"), PutOptions{IfNotExists: true}))
+	assert.True(t, IsExists(b.Put("locks/dev.json", []byte("lock"), PutOptions{IfNotExists: true})))
+
+	// Listing a directory returns only the blobs directly within it, sorted by key.
+	assert.NoError(t, b.Put("stacks/prod.json", []byte("prod"), PutOptions{}))
+	assert.NoError(t, b.Put("stacks/a b+c.json", []byte("odd"), PutOptions{}))
+	assert.NoError(t, b.Put("stacks/nested/test.json", []byte("test"), PutOptions{}))
+	blobs, err = b.List("stacks")
+	assert.NoError(t, err)
+	var keys []string
+	for _, blob := range blobs {
+		keys = append(keys, blob.Key)
+	}
+	assert.Equal(t, []string{"stacks/a b+c.json", "stacks/dev.json", "stacks/prod.json"}, keys)
+	assert.Equal(t, int64(2), blobs[1].Size)
+	byts, err = b.Get("stacks/a b+c.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "odd", string(byts))
+
+	// Blobs can be renamed, replacing any blob at the destination.
+	assert.NoError(t, b.Rename("stacks/prod.json", "stacks/dev.json"))
+	byts, err = b.Get("stacks/dev.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", string(byts))
+	_, err = b.Get("stacks/prod.json")
+	assert.True(t, IsNotExist(err))
+	assert.True(t, IsNotExist(b.Rename("stacks/prod.json", "stacks/other.json")))
+
+	// And deleted, even if they don't exist.
+	assert.NoError(t, b.Delete("stacks/dev.json"))
+	assert.NoError(t, b.Delete("stacks/dev.json"))
+	_, err = b.Get("stacks/dev.json")
+	assert.True(t, IsNotExist(err))
+	assert.NoError(t, b.Delete("locks/dev.json"))
+	assert.NoError(t, b.Put("locks/dev.json", []byte("lock"), PutOptions{IfNotExists: true}))
+}
+
+func TestFileBucket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulumi-blob-test")
+	assert.NoError(t, err)
+	defer contract.IgnoreError(os.RemoveAll(dir))
+
+	testBucket(t, NewFileBucket(dir))
+}
+
+func TestOpenBucket(t *testing.T) {
+	b, err := OpenBucket("file:///tmp/state")
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/state", b.String())
+
+	b, err = OpenBucket("s3://my-bucket/team/infra?region=us-west-2")
+	assert.NoError(t, err)
+	s3b := b.(*s3Bucket)
+	assert.Equal(t, "my-bucket", s3b.bucket)
+	assert.Equal(t, "team/infra/", s3b.prefix)
+	assert.Equal(t, "us-west-2", s3b.region)
+	assert.Equal(t, "s3://my-bucket/team/infra", s3b.String())
+	assert.Equal(t, "https://my-bucket.s3.us-west-2.amazonaws.com/team/infra/x.json",
+		s3b.objectURL(s3b.prefix+"x.json").String())
+
+	b, err = OpenBucket("s3://my-bucket?endpoint=http://localhost:9000")
+	assert.NoError(t, err)
+	s3b = b.(*s3Bucket)
+	assert.Equal(t, "", s3b.prefix)
+	assert.Equal(t, "http://localhost:9000/my-bucket/x.json", s3b.objectURL("x.json").String())
+
+	_, err = OpenBucket("s3:///no-bucket")
+	assert.Error(t, err)
+	_, err = OpenBucket("s3://my-bucket?endpoint=localhost:9000")
+	assert.Error(t, err)
+	_, err = OpenBucket("gs://my-bucket")
+	assert.Error(t, err)
+}
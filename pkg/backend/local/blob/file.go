@@ -0,0 +1,124 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/fsutil"
+)
+
+// FileURLPrefix is the URL scheme for buckets that are directories on the local filesystem.
+const FileURLPrefix = "file://"
+
+// fileBucket is a Bucket that stores each blob as a file beneath a root directory.
+type fileBucket struct {
+	root string
+}
+
+// NewFileBucket returns a bucket that stores its blobs as files beneath the given directory, which is created as
+// needed.
+func NewFileBucket(root string) Bucket {
+	return &fileBucket{root: root}
+}
+
+func (b *fileBucket) path(key string) string {
+	return filepath.Join(b.root, filepath.FromSlash(key))
+}
+
+func (b *fileBucket) Get(key string) ([]byte, error) {
+	byts, err := ioutil.ReadFile(b.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotExist
+	}
+	return byts, err
+}
+
+func (b *fileBucket) Put(key string, data []byte, opts PutOptions) error {
+	path := b.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if !opts.IfNotExists {
+		return fsutil.WriteFileAtomic(path, data, 0600)
+	}
+
+	// Creating the file exclusively ensures that at most one writer succeeds.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return ErrExists
+		}
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		contract.IgnoreError(os.Remove(path))
+	}
+	return err
+}
+
+func (b *fileBucket) List(dir string) ([]Attributes, error) {
+	files, err := ioutil.ReadDir(b.path(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var blobs []Attributes
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		blobs = append(blobs, Attributes{Key: join(dir, file.Name()), Size: file.Size(), ModTime: file.ModTime()})
+	}
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].Key < blobs[j].Key })
+	return blobs, nil
+}
+
+func (b *fileBucket) Delete(key string) error {
+	if err := os.Remove(b.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (b *fileBucket) Rename(from, to string) error {
+	dest := b.path(to)
+	if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+		return err
+	}
+	err := os.Rename(b.path(from), dest)
+	if os.IsNotExist(err) {
+		return ErrNotExist
+	}
+	return err
+}
+
+func (b *fileBucket) String() string {
+	return b.root
+}
@@ -0,0 +1,430 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// S3URLPrefix is the URL scheme for buckets in S3-compatible object stores.
+const S3URLPrefix = "s3://"
+
+// s3Bucket is a Bucket backed by a bucket in an S3-compatible object store, accessed via its REST API.
+type s3Bucket struct {
+	client   *http.Client
+	endpoint *url.URL // the endpoint to send requests to; if nil, requests go to AWS.
+	bucket   string   // the name of the bucket.
+	prefix   string   // a prefix prepended to every key, e.g. "teams/infra/".
+	region   string   // the region the bucket lives in.
+	creds    s3Credentials
+}
+
+// s3Credentials are the credentials used to sign requests. If AccessKeyID is empty, requests are sent unsigned.
+type s3Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// NewS3Bucket returns a bucket backed by an S3-compatible object store. The URL takes the form
+//
+//	s3://<bucket>[/<prefix>][?region=<region>][&endpoint=<endpoint-url>]
+//
+// All blobs are stored beneath the given prefix, if any. The region defaults to the value of AWS_REGION or
+// AWS_DEFAULT_REGION, or to us-east-1 if neither is set. By default requests are sent to AWS; the endpoint parameter
+// directs them to another S3-compatible service instead (e.g. "http://localhost:9000" for a local MinIO server), in
+// which case path-style addressing is used. Credentials are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and
+// AWS_SESSION_TOKEN environment variables; if none are set, requests are sent anonymously.
+func NewS3Bucket(u *url.URL) (Bucket, error) {
+	contract.Require(u != nil, "u")
+	if u.Scheme+"://" != S3URLPrefix {
+		return nil, errors.Errorf("'%s' is not an S3 bucket URL", u)
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("S3 bucket URL '%s' does not name a bucket", u)
+	}
+
+	b := &s3Bucket{
+		client: &http.Client{Timeout: 5 * time.Minute},
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+		creds: s3Credentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		},
+	}
+	if b.prefix != "" {
+		b.prefix += "/"
+	}
+
+	query := u.Query()
+	for _, region := range []string{query.Get("region"), os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")} {
+		if region != "" {
+			b.region = region
+			break
+		}
+	}
+	if b.region == "" {
+		b.region = "us-east-1"
+	}
+	if endpoint := query.Get("endpoint"); endpoint != "" {
+		eu, err := url.Parse(endpoint)
+		if err != nil || (eu.Scheme != "http" && eu.Scheme != "https") || eu.Host == "" {
+			return nil, errors.Errorf("invalid S3 endpoint '%s'; expected an http:// or https:// URL", endpoint)
+		}
+		b.endpoint = eu
+	}
+
+	return b, nil
+}
+
+// objectURL returns the URL of the given object, or of the bucket itself if key is empty. The path is encoded exactly
+// as it is in the request's signature.
+func (b *s3Bucket) objectURL(key string) *url.URL {
+	if b.endpoint != nil {
+		// Custom endpoints use path-style addressing: <endpoint>/<bucket>/<key>.
+		u := *b.endpoint
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + b.bucket
+		if key != "" {
+			u.Path += "/" + key
+		}
+		u.RawPath = s3EscapePath(u.Path)
+		return &u
+	}
+	return &url.URL{
+		Scheme:  "https",
+		Host:    fmt.Sprintf("%s.s3.%s.amazonaws.com", b.bucket, b.region),
+		Path:    "/" + key,
+		RawPath: s3EscapePath("/" + key),
+	}
+}
+
+// do sends a signed request for the given object and returns the response. Responses with a status outside of the
+// 2xx range are returned as errors, except for those whose status is in the okStatus list.
+func (b *s3Bucket) do(method, key string, query url.Values, header http.Header, body []byte,
+	okStatus ...int) (*http.Response, error) {
+
+	u := b.objectURL(key)
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	req.ContentLength = int64(len(body))
+	signS3Request(req, body, b.creds, b.region, time.Now())
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	for _, status := range okStatus {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+
+	// Turn the response into an error, using the details in its body if there are any.
+	defer contract.IgnoreClose(resp.Body)
+	var s3Err struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if byts, readErr := ioutil.ReadAll(resp.Body); readErr == nil && xml.Unmarshal(byts, &s3Err) == nil &&
+		s3Err.Code != "" {
+		return nil, errors.Errorf("%s %s: %s (%s)", method, u.Path, s3Err.Message, s3Err.Code)
+	}
+	return nil, errors.Errorf("%s %s: %s", method, u.Path, resp.Status)
+}
+
+func (b *s3Bucket) Get(key string) ([]byte, error) {
+	resp, err := b.do("GET", b.prefix+key, nil, nil, nil, http.StatusNotFound)
+	if err != nil {
+		return nil, err
+	}
+	defer contract.IgnoreClose(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotExist
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (b *s3Bucket) Put(key string, data []byte, opts PutOptions) error {
+	header := http.Header{}
+	if opts.IfNotExists {
+		header.Set("If-None-Match", "*")
+	}
+	// A failed precondition is reported as 412; a conflicting concurrent conditional write may be reported as 409.
+	resp, err := b.do("PUT", b.prefix+key, nil, header, data, http.StatusPreconditionFailed, http.StatusConflict)
+	if err != nil {
+		return err
+	}
+	contract.IgnoreClose(resp.Body)
+	switch resp.StatusCode {
+	case http.StatusPreconditionFailed:
+		return ErrExists
+	case http.StatusConflict:
+		return errors.Errorf("PUT %s: conflicting concurrent write", key)
+	}
+	return nil
+}
+
+// s3ListResult is the subset of a ListObjectsV2 response that we use.
+type s3ListResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (b *s3Bucket) List(dir string) ([]Attributes, error) {
+	prefix := b.prefix + strings.TrimSuffix(dir, "/") + "/"
+	if dir == "" {
+		prefix = b.prefix
+	}
+
+	var blobs []Attributes
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}, "delimiter": {"/"}}
+	for {
+		resp, err := b.do("GET", "", query, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		contract.IgnoreClose(resp.Body)
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing %s", prefix)
+		}
+
+		for _, obj := range result.Contents {
+			blobs = append(blobs, Attributes{
+				Key:     strings.TrimPrefix(obj.Key, b.prefix),
+				Size:    obj.Size,
+				ModTime: obj.LastModified,
+			})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].Key < blobs[j].Key })
+	return blobs, nil
+}
+
+func (b *s3Bucket) Delete(key string) error {
+	resp, err := b.do("DELETE", b.prefix+key, nil, nil, nil, http.StatusNotFound)
+	if err != nil {
+		return err
+	}
+	contract.IgnoreClose(resp.Body)
+	return nil
+}
+
+// Rename copies the blob server-side and then deletes the original, as S3 has no rename operation. Unlike a rename in a
+// file bucket, this is not atomic: while the rename is in progress both keys exist, and a concurrent writer may change
+// either of them. The copy is checked against the original before the original is deleted, and the copy is removed
+// again if the rename fails after it was made.
+func (b *s3Bucket) Rename(from, to string) error {
+	if err := b.copy(from, to); err != nil {
+		return err
+	}
+
+	// Make sure that the copy holds what the original does before deleting the original.
+	src, err := b.Get(from)
+	if err != nil {
+		return b.removeCopy(from, to, err)
+	}
+	dst, err := b.Get(to)
+	if err != nil {
+		return b.removeCopy(from, to, err)
+	}
+	if !bytes.Equal(src, dst) {
+		return b.removeCopy(from, to, errors.New("the copy does not match the original"))
+	}
+
+	if err = b.Delete(from); err != nil {
+		return b.removeCopy(from, to, err)
+	}
+	return nil
+}
+
+// removeCopy deletes the copy made by a failed rename, and returns the error that caused the rename to fail.
+func (b *s3Bucket) removeCopy(from, to string, err error) error {
+	if deleteErr := b.Delete(to); deleteErr != nil {
+		return errors.Wrapf(err, "renaming %s to %s (removing the copy also failed: %v)", from, to, deleteErr)
+	}
+	return errors.Wrapf(err, "renaming %s to %s", from, to)
+}
+
+// copy copies the blob with the key `from` to the key `to` server-side, replacing any blob already there.
+func (b *s3Bucket) copy(from, to string) error {
+	header := http.Header{}
+	header.Set("X-Amz-Copy-Source", s3EscapePath("/"+b.bucket+"/"+b.prefix+from))
+	resp, err := b.do("PUT", b.prefix+to, nil, header, nil, http.StatusNotFound)
+	if err != nil {
+		return err
+	}
+	defer contract.IgnoreClose(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotExist
+	}
+
+	// A copy can fail after the server has sent a 200 status, in which case the body contains an error.
+	byts, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var result struct {
+		XMLName xml.Name
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(byts, &result) == nil && result.XMLName.Local == "Error" {
+		return errors.Errorf("copying %s to %s: %s (%s)", from, to, result.Message, result.Code)
+	}
+	return nil
+}
+
+func (b *s3Bucket) String() string {
+	return strings.TrimSuffix(S3URLPrefix+b.bucket+"/"+b.prefix, "/")
+}
+
+// signS3Request signs the given request using AWS Signature Version 4. Every header present on the request, along with
+// the Host header, is included in the signature. If no credentials are available, the request is left unsigned.
+func signS3Request(req *http.Request, body []byte, creds s3Credentials, region string, now time.Time) {
+	payloadHash := sha256.Sum256(body)
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	if creds.AccessKeyID == "" {
+		return
+	}
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	// Build the canonical request.
+	headers := map[string]string{"host": req.URL.Host}
+	for k, vs := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(vs, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3EscapePath(req.URL.Path),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+
+	// Then the string to sign, and finally the signature itself.
+	date := amzDate[:8]
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, err := io.WriteString(h, data)
+	contract.AssertNoError(err)
+	return h.Sum(nil)
+}
+
+// s3Escape percent-encodes every byte of s other than the unreserved characters of RFC 3986, as SigV4 requires.
+func s3Escape(s string, keepSlash bool) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			buf.WriteByte(c)
+		case c == '/' && keepSlash:
+			buf.WriteByte(c)
+		default:
+			fmt.Fprintf(&buf, "%%%02X", c)
+		}
+	}
+	return buf.String()
+}
+
+// s3EscapePath percent-encodes a URL path for use in a canonical request.
+func s3EscapePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	return s3Escape(p, true)
+}
+
+// s3CanonicalQuery returns the canonical form of a query string: its parameters, encoded and sorted by name.
+func s3CanonicalQuery(query url.Values) string {
+	var params []string
+	for k, vs := range query {
+		for _, v := range vs {
+			params = append(params, s3Escape(k, false)+"="+s3Escape(v, false))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/secrets"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 	"github.com/pulumi/pulumi/pkg/util/contract"
@@ -37,15 +38,70 @@ func readPassphrase(prompt string) (string, error) {
 	return cmdutil.ReadConsoleNoEcho(prompt)
 }
 
-// defaultCrypter gets the right value encrypter/decrypter given the project configuration.
-func defaultCrypter(stackName tokens.QName, cfg config.Map) (config.Crypter, error) {
-	// If there is no config, we can use a standard panic crypter.
-	if !cfg.HasSecureValue() {
-		return config.NewPanicCrypter(), nil
+// stateCrypter returns the value encrypter/decrypter that protects the stack's secret configuration and state. The
+// underlying crypter is only obtained, possibly prompting for a passphrase, when a secret is first encountered, and is
+// shared by all uses of the stack within this process.
+func (b *localBackend) stateCrypter(stackName tokens.QName) config.Crypter {
+	b.cryptersLock.Lock()
+	defer b.cryptersLock.Unlock()
+
+	crypter, has := b.crypters[stackName]
+	if !has {
+		crypter = secrets.NewCachingCrypter(func() (config.Crypter, error) { return stackCrypter(stackName) })
+		b.crypters[stackName] = crypter
+	}
+	return crypter
+}
+
+// carryOverSecrets arranges for the stack named `newName` to protect its secrets just as the stack named `oldName` does:
+// the new stack's settings are given the old stack's secrets provider and salt (unless they already name a provider of
+// their own), and the new stack shares the old stack's crypter, so that the user is not asked for a new passphrase.
+func (b *localBackend) carryOverSecrets(oldName, newName tokens.QName) error {
+	// Settings are only available within a project.
+	projPath, err := workspace.DetectProjectPath()
+	if err != nil {
+		return err
+	}
+	if projPath != "" {
+		oldInfo, err := workspace.DetectProjectStack(oldName)
+		if err != nil {
+			return err
+		}
+		newInfo, err := workspace.DetectProjectStack(newName)
+		if err != nil {
+			return err
+		}
+		if newInfo.SecretsProvider == "" && newInfo.EncryptionSalt == "" &&
+			(oldInfo.SecretsProvider != "" || oldInfo.EncryptionSalt != "") {
+
+			newInfo.SecretsProvider, newInfo.EncryptionSalt = oldInfo.SecretsProvider, oldInfo.EncryptionSalt
+			if err = workspace.SaveProjectStack(newName, newInfo); err != nil {
+				return errors.Wrapf(err, "saving settings for stack '%v'", newName)
+			}
+		}
+	}
+
+	crypter := b.stateCrypter(oldName)
+	b.cryptersLock.Lock()
+	defer b.cryptersLock.Unlock()
+	b.crypters[newName] = crypter
+	return nil
+}
+
+// stackCrypter gets the value encrypter/decrypter for the secrets provider selected by the stack's settings.
+func stackCrypter(stackName tokens.QName) (config.Crypter, error) {
+	contract.Require(stackName != "", "stackName")
+
+	info, err := workspace.DetectProjectStack(stackName)
+	if err != nil {
+		return nil, err
 	}
 
-	// Otherwise, we will use an encrypted one.
-	return symmetricCrypter(stackName)
+	passphrase := func() (config.Crypter, error) { return symmetricCrypter(stackName) }
+	return secrets.NewCrypter(info.SecretsProvider, map[string]secrets.Factory{
+		"":                         passphrase,
+		secrets.PassphraseProvider: passphrase,
+	})
 }
 
 // symmetricCrypter gets the right value encrypter/decrypter for this project.
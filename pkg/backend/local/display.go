@@ -32,22 +32,93 @@ import (
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/logging"
 )
 
 // DisplayEvents reads events from the `events` channel until it is closed, displaying each event as
-// it comes in. Once all events have been read from the channel and displayed, it closes the `done`
-// channel so the caller can await all the events being written.
+// it comes in. Once all events have been read from the channel and displayed, it signals the `done`
+// channel so the caller can await all the events being written. The value sent is false if the display
+// failed to write its output.
 func DisplayEvents(
 	action string, events <-chan engine.Event,
 	done chan<- bool, opts backend.DisplayOptions) {
 
-	if opts.DiffDisplay {
+	if opts.EventLog != nil {
+		// A failure to write the event log does not fail the operation, but the user is warned--once--that the log
+		// is incomplete once the operation is finished.
+		var logErr error
+		events, done = startEventRecorder(events, done, func(e engine.Event) {
+			if err := opts.EventLog.Write(e); err != nil {
+				logging.V(7).Infof("failed to write event to event log: %v", err)
+				if logErr == nil {
+					logErr = err
+				}
+			}
+		}, func() {
+			if err := opts.EventLog.Close(); err != nil {
+				logging.V(7).Infof("failed to close event log: %v", err)
+				if logErr == nil {
+					logErr = err
+				}
+			}
+			if logErr != nil {
+				cmdutil.Diag().Warningf(diag.Message("", "the event log is incomplete: %v"), logErr)
+			}
+		})
+	}
+	if opts.DriftReport != nil {
+		events, done = startEventRecorder(events, done, opts.DriftReport.Record, nil)
+	}
+
+	switch {
+	case opts.JSONDisplay:
+		DisplayJSON(action, events, done, opts)
+	case opts.DiffDisplay:
 		DisplayDiffEvents(action, events, done, opts)
-	} else {
+	default:
 		DisplayProgressEvents(action, events, done, opts)
 	}
 }
 
+// startEventRecorder starts a goroutine that passes each event read from `events` to the given function before passing
+// it along to the returned channel. The goroutine stops reading events once it sees a cancel event or `events` is
+// closed. In either case it then closes the returned channel (sending a cancel event first if none was seen, so that
+// the display stops), waits for the returned done channel to be signaled, calls `finish` if it is non-nil, and signals
+// the `done` channel with the value received from the returned done channel.
+func startEventRecorder(events <-chan engine.Event, done chan<- bool,
+	record func(engine.Event), finish func()) (<-chan engine.Event, chan<- bool) {
+
+	outEvents, outDone := make(chan engine.Event), make(chan bool)
+	go func() {
+		canceled := false
+		defer func() {
+			if !canceled {
+				outEvents <- engine.Event{Type: engine.CancelEvent}
+			}
+			close(outEvents)
+
+			ok := <-outDone
+			if finish != nil {
+				finish()
+			}
+			done <- ok
+		}()
+
+		for e := range events {
+			record(e)
+
+			outEvents <- e
+
+			if e.Type == engine.CancelEvent {
+				canceled = true
+				return
+			}
+		}
+	}()
+
+	return outEvents, outDone
+}
+
 type nopSpinner struct {
 }
 
@@ -202,6 +273,23 @@ func renderSummaryEvent(event engine.SummaryEventPayload, opts backend.DisplayOp
 		fprintfIgnoreError(out, "      %v %v unchanged\n", c, plural("resource", c))
 	}
 
+	// Summarize any policy violations reported by analyzers. Mandatory violations fail the operation, so typically
+	// only advisory violations make it this far.
+	if c := len(event.PolicyViolations); c > 0 {
+		fprintIgnoreError(out, opts.Color.Colorize(fmt.Sprintf("%v%v %v reported:%v\n",
+			colors.SpecWarning, c, plural("policy violation", c), colors.Reset)))
+		for _, v := range event.PolicyViolations {
+			policy := v.PolicyName
+			if policy == "" {
+				policy = string(v.Analyzer)
+			}
+			fprintfIgnoreError(out, "    [%v] %v: %v\n", v.EnforcementLevel, policy, v.Reason)
+			if v.URN != "" {
+				fprintfIgnoreError(out, "        %v\n", v.URN)
+			}
+		}
+	}
+
 	// For actual deploys, we print some additional summary information
 	if !event.IsPreview {
 		if changeCount > 0 {
@@ -307,7 +395,7 @@ func shouldShow(step engine.StepEventMetadata, opts backend.DisplayOptions) bool
 	// For certain operations, whether they are tracked is controlled by flags (to cut down on superfluous output).
 	if step.Op == deploy.OpSame {
 		// If the op is the same, it is possible that the resource's metadata changed.  In that case, still show it.
-		if step.Old.Protect != step.New.Protect {
+		if step.Old != nil && step.Old.Protect != step.New.Protect {
 			return true
 		}
 		return opts.ShowSameResources
@@ -0,0 +1,100 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/engine"
+)
+
+func readEventLog(t *testing.T, path string) []apitype.EngineEvent {
+	f, err := os.Open(path)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() { assert.NoError(t, f.Close()) }()
+
+	var events []apitype.EngineEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e apitype.EngineEvent
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, e)
+	}
+	assert.NoError(t, scanner.Err())
+	return events
+}
+
+func TestDisplayEventsEventLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulumi-event-log")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() { assert.NoError(t, os.RemoveAll(dir)) }()
+
+	path := filepath.Join(dir, "events.json")
+	log, err := backend.NewEventLog(path)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	opts := backend.DisplayOptions{Color: colors.Never, DiffDisplay: true, EventLog: log}
+	diagEvent := engine.Event{Type: engine.DiagEvent, Payload: engine.DiagEventPayload{
+		Message:  "hello",
+		Color:    colors.Never,
+		Severity: diag.Info,
+	}}
+
+	// An operation that ends with a cancel event should have every event in the log once the display is done.
+	events, done := make(chan engine.Event), make(chan bool)
+	go DisplayEvents("testing", events, done, opts)
+	events <- diagEvent
+	events <- engine.Event{Type: engine.CancelEvent}
+	assert.True(t, <-done)
+	close(events)
+	close(done)
+
+	logged := readEventLog(t, path)
+	if assert.Len(t, logged, 2) {
+		assert.NotNil(t, logged[0].DiagnosticEvent)
+		assert.NotNil(t, logged[1].CancelEvent)
+	}
+
+	// An operation whose event stream is closed without a cancel event should still stop the display and flush
+	// the log, appending to the events written by the first operation.
+	events, done = make(chan engine.Event), make(chan bool)
+	go DisplayEvents("testing", events, done, opts)
+	events <- diagEvent
+	close(events)
+	assert.True(t, <-done)
+	close(done)
+
+	logged = readEventLog(t, path)
+	if assert.Len(t, logged, 3) {
+		assert.NotNil(t, logged[2].DiagnosticEvent)
+		assert.Equal(t, 2, logged[2].Sequence)
+	}
+}
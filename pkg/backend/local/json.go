@@ -0,0 +1,223 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// JSONDigest is the document written by the JSON display. It summarizes an entire preview or update, and is built
+// up from the engine's event stream.
+type JSONDigest struct {
+	// Config contains the configuration used by the operation. Secret values are blinded.
+	Config map[string]string `json:"config,omitempty"`
+	// Steps contains every step performed (or planned) by the operation, in the order in which they began.
+	Steps []JSONStep `json:"steps,omitempty"`
+	// Diagnostics contains the diagnostic messages issued during the operation.
+	Diagnostics []JSONDiagnostic `json:"diagnostics,omitempty"`
+	// Outputs contains the stack's outputs, if any.
+	Outputs map[string]interface{} `json:"outputs,omitempty"`
+	// Summary contains the summary of the operation, if it completed.
+	Summary *JSONSummary `json:"summary,omitempty"`
+}
+
+// JSONStep describes a single step in a JSONDigest.
+type JSONStep struct {
+	Op          deploy.StepOp          `json:"op"`                    // the operation performed by this step.
+	URN         resource.URN           `json:"urn"`                   // the URN of the resource.
+	Type        tokens.Type            `json:"type"`                  // the type of the resource.
+	Provider    string                 `json:"provider,omitempty"`    // the provider reference for the resource.
+	OldInputs   map[string]interface{} `json:"oldInputs,omitempty"`   // the resource's inputs before the step.
+	NewInputs   map[string]interface{} `json:"newInputs,omitempty"`   // the resource's inputs after the step.
+	DiffKeys    []resource.PropertyKey `json:"diffKeys,omitempty"`    // the input keys that changed.
+	ReplaceKeys []resource.PropertyKey `json:"replaceKeys,omitempty"` // the keys that caused a replacement.
+	Forced      bool                   `json:"forced,omitempty"`      // true if the user forced a replacement.
+}
+
+// JSONDiagnostic describes a single diagnostic message in a JSONDigest.
+type JSONDiagnostic struct {
+	URN      resource.URN  `json:"urn,omitempty"`
+	Prefix   string        `json:"prefix,omitempty"`
+	Message  string        `json:"message"`
+	Severity diag.Severity `json:"severity"`
+}
+
+// JSONSummary is the summary of the operation described by a JSONDigest.
+type JSONSummary struct {
+	IsPreview        bool                   `json:"isPreview"`                  // true if the operation was a preview.
+	MaybeCorrupt     bool                   `json:"maybeCorrupt,omitempty"`     // true if resources may be corrupt.
+	Duration         time.Duration          `json:"duration,omitempty"`         // the duration of the operation.
+	ResourceChanges  engine.ResourceChanges `json:"resourceChanges"`            // the count of steps by operation.
+	PolicyViolations []JSONPolicyViolation  `json:"policyViolations,omitempty"` // any reported policy violations.
+}
+
+// JSONPolicyViolation describes a policy violation reported by an analyzer.
+type JSONPolicyViolation struct {
+	Analyzer         tokens.QName               `json:"analyzer"`
+	PolicyName       string                     `json:"policyName,omitempty"`
+	Description      string                     `json:"description,omitempty"`
+	Severity         string                     `json:"severity,omitempty"`
+	EnforcementLevel workspace.EnforcementLevel `json:"enforcementLevel"`
+	URN              resource.URN               `json:"urn,omitempty"`
+	Property         resource.PropertyKey       `json:"property,omitempty"`
+	Reason           string                     `json:"reason"`
+}
+
+// DisplayJSON reads events from the `events` channel until it sees a cancel event, accumulating them into a single
+// JSONDigest. The digest is then written to stdout and the `done` channel is signaled with true, or with false if the
+// digest could not be written.
+func DisplayJSON(action string, events <-chan engine.Event, done chan<- bool, opts backend.DisplayOptions) {
+	displayJSON(os.Stdout, events, done, opts)
+}
+
+// displayJSON implements DisplayJSON, writing the digest to the given writer.
+func displayJSON(w io.Writer, events <-chan engine.Event, done chan<- bool, opts backend.DisplayOptions) {
+	digest := &JSONDigest{}
+	for event := range events {
+		if event.Type == engine.CancelEvent {
+			break
+		}
+		addEventToJSONDigest(digest, event, opts)
+	}
+
+	err := writeJSONDigest(w, digest)
+	if err != nil {
+		logging.V(3).Infof("failed to write JSON digest: %v", err)
+	}
+	done <- err == nil
+}
+
+// addEventToJSONDigest records the information carried by a single engine event in the given digest.
+func addEventToJSONDigest(digest *JSONDigest, event engine.Event, opts backend.DisplayOptions) {
+	switch event.Type {
+	case engine.PreludeEvent:
+		digest.Config = event.Payload.(engine.PreludeEventPayload).Config
+	case engine.ResourcePreEvent:
+		digest.Steps = append(digest.Steps, makeJSONStep(event.Payload.(engine.ResourcePreEventPayload).Metadata))
+	case engine.ResourceOutputsEvent:
+		metadata := event.Payload.(engine.ResourceOutputsEventPayload).Metadata
+		if isRootStack(metadata) && metadata.New != nil {
+			digest.Outputs = serializeMaskedProperties(metadata.New.Outputs)
+		}
+	case engine.DiagEvent:
+		payload := event.Payload.(engine.DiagEventPayload)
+		if payload.Severity == diag.Debug && !opts.Debug {
+			return
+		}
+		digest.Diagnostics = append(digest.Diagnostics, JSONDiagnostic{
+			URN:      payload.URN,
+			Prefix:   colors.Never.Colorize(payload.Prefix),
+			Message:  colors.Never.Colorize(payload.Message),
+			Severity: payload.Severity,
+		})
+	case engine.SummaryEvent:
+		payload := event.Payload.(engine.SummaryEventPayload)
+		digest.Summary = &JSONSummary{
+			IsPreview:       payload.IsPreview,
+			MaybeCorrupt:    payload.MaybeCorrupt,
+			Duration:        payload.Duration,
+			ResourceChanges: payload.ResourceChanges,
+		}
+		for _, v := range payload.PolicyViolations {
+			digest.Summary.PolicyViolations = append(digest.Summary.PolicyViolations, JSONPolicyViolation{
+				Analyzer:         v.Analyzer,
+				PolicyName:       v.PolicyName,
+				Description:      v.Description,
+				Severity:         v.Severity,
+				EnforcementLevel: v.EnforcementLevel,
+				URN:              v.URN,
+				Property:         v.Property,
+				Reason:           v.Reason,
+			})
+		}
+	}
+}
+
+// serializeMaskedProperties serializes a property map for display, masking any secrets it contains.
+func serializeMaskedProperties(props resource.PropertyMap) map[string]interface{} {
+	serialized, err := stack.SerializeProperties(props, nil)
+	contract.AssertNoError(err) // masking secrets cannot fail.
+	return serialized
+}
+
+// makeJSONStep converts the metadata for a step into its JSON form.
+func makeJSONStep(step engine.StepEventMetadata) JSONStep {
+	result := JSONStep{
+		Op:          step.Op,
+		URN:         step.URN,
+		Type:        step.Type,
+		Provider:    step.Provider,
+		ReplaceKeys: step.Keys,
+		Forced:      step.Forced,
+	}
+	if step.Old != nil {
+		result.OldInputs = serializeMaskedProperties(step.Old.Inputs)
+	}
+	if step.New != nil {
+		result.NewInputs = serializeMaskedProperties(step.New.Inputs)
+	}
+	if step.Old != nil && step.New != nil {
+		result.DiffKeys = getDiffKeys(step.Old, step.New)
+	}
+	return result
+}
+
+// getDiffKeys returns the sorted list of input keys that differ between the given states. A change in provider
+// reference is reported as a change to the "provider" key.
+func getDiffKeys(old, new *engine.StepEventStateMetadata) []resource.PropertyKey {
+	var keys []resource.PropertyKey
+	if diff := old.Inputs.Diff(new.Inputs); diff != nil {
+		for k := range diff.Adds {
+			keys = append(keys, k)
+		}
+		for k := range diff.Deletes {
+			keys = append(keys, k)
+		}
+		for k := range diff.Updates {
+			keys = append(keys, k)
+		}
+	}
+	if old.Provider != new.Provider {
+		keys = append(keys, "provider")
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// writeJSONDigest writes the given digest to the given writer as indented JSON.
+func writeJSONDigest(w io.Writer, digest *JSONDigest) error {
+	b, err := json.MarshalIndent(digest, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
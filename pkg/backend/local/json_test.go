@@ -0,0 +1,124 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/engine"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+// runJSONDisplay feeds the given events through the JSON display and returns the value signaled on its done channel.
+func runJSONDisplay(w io.Writer, opts backend.DisplayOptions, evts ...engine.Event) bool {
+	events, done := make(chan engine.Event), make(chan bool)
+	go displayJSON(w, events, done, opts)
+	for _, e := range evts {
+		events <- e
+	}
+	events <- engine.Event{Type: engine.CancelEvent}
+	return <-done
+}
+
+func TestJSONDisplay(t *testing.T) {
+	stackURN := resource.NewURN("test", "proj", "", resource.RootStackType, "proj-test")
+	resURN := resource.NewURN("test", "proj", "", "pkgA:m:typA", "resA")
+
+	var buf bytes.Buffer
+	ok := runJSONDisplay(&buf, backend.DisplayOptions{},
+		engine.Event{Type: engine.PreludeEvent, Payload: engine.PreludeEventPayload{
+			Config: map[string]string{"proj:key": "value"},
+		}},
+		engine.Event{Type: engine.ResourcePreEvent, Payload: engine.ResourcePreEventPayload{
+			Metadata: engine.StepEventMetadata{
+				Op:   deploy.OpUpdate,
+				URN:  resURN,
+				Type: "pkgA:m:typA",
+				Old: &engine.StepEventStateMetadata{
+					Inputs: resource.PropertyMap{"foo": resource.NewStringProperty("bar")},
+				},
+				New: &engine.StepEventStateMetadata{
+					Inputs: resource.PropertyMap{
+						"foo":      resource.NewStringProperty("baz"),
+						"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+					},
+				},
+			},
+		}},
+		engine.Event{Type: engine.DiagEvent, Payload: engine.DiagEventPayload{
+			URN: resURN, Message: "a debug message", Severity: diag.Debug,
+		}},
+		engine.Event{Type: engine.DiagEvent, Payload: engine.DiagEventPayload{
+			URN: resURN, Message: "a warning", Severity: diag.Warning,
+		}},
+		engine.Event{Type: engine.ResourceOutputsEvent, Payload: engine.ResourceOutputsEventPayload{
+			Metadata: engine.StepEventMetadata{
+				Op:  deploy.OpSame,
+				URN: stackURN,
+				New: &engine.StepEventStateMetadata{
+					Outputs: resource.PropertyMap{"out": resource.NewNumberProperty(42)},
+				},
+			},
+		}},
+		engine.Event{Type: engine.SummaryEvent, Payload: engine.SummaryEventPayload{
+			IsPreview:       true,
+			ResourceChanges: engine.ResourceChanges{deploy.OpUpdate: 1},
+		}})
+	assert.True(t, ok)
+
+	var doc map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, map[string]interface{}{"proj:key": "value"}, doc["config"])
+	assert.Equal(t, map[string]interface{}{"out": float64(42)}, doc["outputs"])
+
+	steps := doc["steps"].([]interface{})
+	if assert.Len(t, steps, 1) {
+		step := steps[0].(map[string]interface{})
+		assert.Equal(t, "update", step["op"])
+		assert.Equal(t, string(resURN), step["urn"])
+		assert.Equal(t, []interface{}{"foo", "password"}, step["diffKeys"])
+		newInputs := step["newInputs"].(map[string]interface{})
+		assert.Equal(t, "baz", newInputs["foo"])
+		assert.NotContains(t, buf.String(), "hunter2")
+	}
+
+	diags := doc["diagnostics"].([]interface{})
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "a warning", diags[0].(map[string]interface{})["message"])
+	}
+
+	summary := doc["summary"].(map[string]interface{})
+	assert.Equal(t, true, summary["isPreview"])
+	assert.Equal(t, map[string]interface{}{"update": float64(1)}, summary["resourceChanges"])
+}
+
+func TestJSONDisplayWriteFailure(t *testing.T) {
+	assert.False(t, runJSONDisplay(failingWriter{}, backend.DisplayOptions{}))
+}
@@ -0,0 +1,201 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/local/blob"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// LockInfo describes the process that holds the lock on a stack.
+type LockInfo struct {
+	Owner     string    `json:"owner"`     // the name of the user that took the lock.
+	PID       int       `json:"pid"`       // the process ID of the process that took the lock.
+	Host      string    `json:"host"`      // the name of the host on which that process is running.
+	Operation string    `json:"operation"` // the operation being performed, e.g. "updating".
+	StartTime time.Time `json:"startTime"` // the time at which the lock was taken.
+}
+
+// StackLockedError is returned when an operation cannot proceed because another process holds the stack's lock.
+type StackLockedError struct {
+	StackName tokens.QName
+	Lock      LockInfo
+}
+
+func (e StackLockedError) Error() string {
+	return fmt.Sprintf("the stack '%s' is locked: %s is currently %s it (pid %d on host '%s', since %s); "+
+		"if this is not the case, run `pulumi cancel %s` to remove the lock",
+		e.StackName, e.Lock.Owner, e.Lock.Operation, e.Lock.PID, e.Lock.Host,
+		e.Lock.StartTime.Format(time.RFC1123), e.StackName)
+}
+
+// newLockInfo describes a lock taken by this process for the given operation.
+func newLockInfo(op string) LockInfo {
+	owner := "unknown"
+	if u, err := user.Current(); err == nil {
+		owner = u.Username
+	}
+	return LockInfo{
+		Owner:     owner,
+		PID:       os.Getpid(),
+		Host:      hostname(),
+		Operation: op,
+		StartTime: time.Now(),
+	}
+}
+
+// isStale returns true if the process that took the lock is known to have exited without releasing it. Only locks
+// taken on this host can be checked; a lock taken on another host is never considered stale.
+func (l LockInfo) isStale() bool {
+	return l.Host == hostname() && l.PID != os.Getpid() && !cmdutil.ProcessExists(l.PID)
+}
+
+// hostname returns the name of this host.
+func hostname() string {
+	name, err := os.Hostname()
+	contract.IgnoreError(err)
+	return name
+}
+
+func (b *localBackend) lockKey(stack tokens.QName) string {
+	contract.Require(stack != "", "stack")
+
+	return path.Join(workspace.LockDir, string(stack)+".json")
+}
+
+// readLock reads the lock for the given stack. If the stack is not locked, the returned error satisfies
+// blob.IsNotExist.
+func (b *localBackend) readLock(stack tokens.QName) (*LockInfo, error) {
+	byts, err := b.bucket.Get(b.lockKey(stack))
+	if err != nil {
+		return nil, err
+	}
+	var lock LockInfo
+	if err = json.Unmarshal(byts, &lock); err != nil {
+		return nil, errors.Wrapf(err, "reading lock file %s", b.location(b.lockKey(stack)))
+	}
+	return &lock, nil
+}
+
+// lockStack takes an advisory lock on the given stack for the duration of the given operation. If another process
+// already holds the lock, a StackLockedError is returned; if that process has exited without releasing the lock, the
+// stale lock is removed and taken over. On success, the returned function must be called to release the lock.
+func (b *localBackend) lockStack(stack tokens.QName, op string) (func(), error) {
+	key := b.lockKey(stack)
+	lock := newLockInfo(op)
+	byts, err := json.MarshalIndent(&lock, "", "    ")
+	contract.AssertNoError(err)
+
+	for attempt := 0; ; attempt++ {
+		// Creating the lock file exclusively ensures that at most one process can hold the lock at a time.
+		createErr := b.bucket.Put(key, byts, blob.PutOptions{IfNotExists: true})
+		if createErr == nil {
+			break
+		}
+		if !blob.IsExists(createErr) {
+			return nil, errors.Wrap(createErr, "creating lock file")
+		}
+
+		// Someone else holds the lock. If it was released in the meantime or is stale, try again.
+		existing, readErr := b.readLock(stack)
+		switch {
+		case attempt >= 2:
+			if readErr != nil {
+				return nil, errors.Errorf("the stack '%s' is locked by another process", stack)
+			}
+			return nil, StackLockedError{StackName: stack, Lock: *existing}
+		case readErr != nil && blob.IsNotExist(readErr):
+			continue
+		case readErr != nil:
+			return nil, readErr
+		case !existing.isStale():
+			return nil, StackLockedError{StackName: stack, Lock: *existing}
+		}
+
+		logging.V(7).Infof("removing stale lock on stack %s held by pid %d since %v",
+			stack, existing.PID, existing.StartTime)
+		if err = b.bucket.Delete(key); err != nil {
+			return nil, errors.Wrap(err, "removing stale lock file")
+		}
+
+		// Another process may have found the same stale lock and removed it first, in which case it may now hold the
+		// lock. Only one of us can create the lock file, so whoever fails to do so must give up.
+		createErr = b.bucket.Put(key, byts, blob.PutOptions{IfNotExists: true})
+		if createErr == nil {
+			break
+		}
+		if !blob.IsExists(createErr) {
+			return nil, errors.Wrap(createErr, "creating lock file")
+		}
+		if existing, readErr = b.readLock(stack); readErr != nil {
+			return nil, errors.Errorf("the stack '%s' is locked by another process", stack)
+		}
+		return nil, StackLockedError{StackName: stack, Lock: *existing}
+	}
+
+	return func() { b.unlockStack(stack, lock) }, nil
+}
+
+// unlockStack releases a lock taken by lockStack. If the lock was removed by `pulumi cancel` in the meantime--and
+// perhaps taken by another process--it is left alone.
+func (b *localBackend) unlockStack(stack tokens.QName, lock LockInfo) {
+	existing, err := b.readLock(stack)
+	if err != nil || existing.PID != lock.PID || existing.Host != lock.Host ||
+		!existing.StartTime.Equal(lock.StartTime) {
+		logging.V(7).Infof("not releasing lock on stack %s: it is no longer held by this process", stack)
+		return
+	}
+	if err = b.bucket.Delete(b.lockKey(stack)); err != nil {
+		logging.V(3).Infof("failed to release lock on stack %s: %v", stack, err)
+	}
+}
+
+func (b *localBackend) CancelCurrentUpdate(ctx context.Context, stackRef backend.StackReference) error {
+	stackName := stackRef.StackName()
+	lock, err := b.readLock(stackName)
+	if err != nil {
+		if blob.IsNotExist(err) {
+			return errors.Errorf("the stack '%s' does not have an update in progress", stackName)
+		}
+		return err
+	}
+
+	// If the update is running on this host, ask it to stop. Either way, remove its lock so that the stack is ready
+	// for further updates.
+	if lock.Host == hostname() && lock.PID != os.Getpid() && cmdutil.ProcessExists(lock.PID) {
+		if err = cmdutil.InterruptProcess(lock.PID); err != nil {
+			logging.V(3).Infof("failed to interrupt pid %d: %v", lock.PID, err)
+		}
+	}
+	if err = b.bucket.Delete(b.lockKey(stackName)); err != nil {
+		return errors.Wrap(err, "removing lock file")
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/backend/local/blob"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+func newTestBackend(t *testing.T) (*localBackend, func()) {
+	dir, err := ioutil.TempDir("", "pulumi-lock-test")
+	assert.NoError(t, err)
+	b, err := New(nil, localBackendURLPrefix+dir)
+	assert.NoError(t, err)
+	return b.(*localBackend), func() { contract.IgnoreError(os.RemoveAll(dir)) }
+}
+
+func TestStackLock(t *testing.T) {
+	b, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	stack := tokens.QName("dev")
+	unlock, err := b.lockStack(stack, "updating")
+	assert.NoError(t, err)
+
+	// A second attempt to lock the stack fails, and reports who holds the lock.
+	_, err = b.lockStack(stack, "updating")
+	if assert.Error(t, err) {
+		lockedErr, ok := err.(StackLockedError)
+		if assert.True(t, ok) {
+			assert.Equal(t, stack, lockedErr.StackName)
+			assert.Equal(t, os.Getpid(), lockedErr.Lock.PID)
+			assert.Equal(t, "updating", lockedErr.Lock.Operation)
+		}
+	}
+
+	// Other stacks are unaffected.
+	unlockOther, err := b.lockStack("prod", "updating")
+	assert.NoError(t, err)
+	unlockOther()
+
+	// Once the lock is released, the stack may be locked again.
+	unlock()
+	unlock, err = b.lockStack(stack, "updating")
+	assert.NoError(t, err)
+	unlock()
+}
+
+func TestStaleStackLock(t *testing.T) {
+	b, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	// Write a lock that claims to be held by a process on this host that does not exist.
+	stack := tokens.QName("dev")
+	stale := newLockInfo("updating")
+	stale.PID = 1 << 30
+	byts, err := json.Marshal(&stale)
+	assert.NoError(t, err)
+	assert.NoError(t, b.bucket.Put(b.lockKey(stack), byts, blob.PutOptions{}))
+
+	unlock, err := b.lockStack(stack, "updating")
+	assert.NoError(t, err)
+	lock, err := b.readLock(stack)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.Getpid(), lock.PID)
+	}
+	unlock()
+
+	// A lock held by a process on another host is never considered stale.
+	stale.Host = "some-other-host"
+	byts, err = json.Marshal(&stale)
+	assert.NoError(t, err)
+	assert.NoError(t, b.bucket.Put(b.lockKey(stack), byts, blob.PutOptions{}))
+	_, err = b.lockStack(stack, "updating")
+	assert.Error(t, err)
+}
+
+// racingBucket is a bucket in which another process takes a lock as soon as a stale lock has been removed.
+type racingBucket struct {
+	blob.Bucket
+	lock []byte
+}
+
+func (b *racingBucket) Delete(key string) error {
+	if err := b.Bucket.Delete(key); err != nil {
+		return err
+	}
+	return b.Bucket.Put(key, b.lock, blob.PutOptions{})
+}
+
+func TestStaleStackLockRace(t *testing.T) {
+	b, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	stack := tokens.QName("dev")
+	stale := newLockInfo("updating")
+	stale.PID = 1 << 30
+	byts, err := json.Marshal(&stale)
+	assert.NoError(t, err)
+	assert.NoError(t, b.bucket.Put(b.lockKey(stack), byts, blob.PutOptions{}))
+
+	// If another process takes the lock after the stale lock is removed, taking the lock fails.
+	winner := newLockInfo("refreshing")
+	winner.Host = "some-other-host"
+	winnerBytes, err := json.Marshal(&winner)
+	assert.NoError(t, err)
+	b.bucket = &racingBucket{Bucket: b.bucket, lock: winnerBytes}
+
+	_, err = b.lockStack(stack, "updating")
+	if assert.Error(t, err) {
+		lockedErr, ok := err.(StackLockedError)
+		if assert.True(t, ok) {
+			assert.Equal(t, "refreshing", lockedErr.Lock.Operation)
+		}
+	}
+}
+
+func TestCancelStackLock(t *testing.T) {
+	b, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	stack := tokens.QName("dev")
+	assert.Error(t, b.CancelCurrentUpdate(context.Background(), localBackendReference{name: stack}))
+
+	unlock, err := b.lockStack(stack, "updating")
+	assert.NoError(t, err)
+	assert.NoError(t, b.CancelCurrentUpdate(context.Background(), localBackendReference{name: stack}))
+
+	// The stack may be locked by someone else once the lock has been removed, and releasing the canceled lock must
+	// not release the new one.
+	unlockNew, err := b.lockStack(stack, "updating")
+	assert.NoError(t, err)
+	unlock()
+	_, err = b.readLock(stack)
+	assert.NoError(t, err)
+	unlockNew()
+	_, err = b.readLock(stack)
+	assert.True(t, blob.IsNotExist(err))
+}
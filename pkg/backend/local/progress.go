@@ -1011,6 +1011,8 @@ func (display *ProgressDisplay) getStepDoneDescription(step engine.StepEventMeta
 				return "replacing failed"
 			case deploy.OpRead, deploy.OpReadReplacement:
 				return "reading failed"
+			case deploy.OpImport, deploy.OpImportReplacement:
+				return "importing failed"
 			}
 		} else {
 			switch op {
@@ -1032,6 +1034,10 @@ func (display *ProgressDisplay) getStepDoneDescription(step engine.StepEventMeta
 				return "read"
 			case deploy.OpReadReplacement:
 				return "read for replacement"
+			case deploy.OpImport:
+				return "imported"
+			case deploy.OpImportReplacement:
+				return "imported replacement"
 			}
 		}
 
@@ -1066,6 +1072,10 @@ func (display *ProgressDisplay) getPreviewText(op deploy.StepOp) string {
 		return "read"
 	case deploy.OpReadReplacement:
 		return "read for replacement"
+	case deploy.OpImport:
+		return "import"
+	case deploy.OpImportReplacement:
+		return "import replacement"
 	}
 
 	contract.Failf("Unrecognized resource step op: %v", op)
@@ -1134,6 +1144,10 @@ func (display *ProgressDisplay) getStepInProgressDescription(step engine.StepEve
 			return "reading"
 		case deploy.OpReadReplacement:
 			return "reading for replacement"
+		case deploy.OpImport:
+			return "importing"
+		case deploy.OpImportReplacement:
+			return "importing replacement"
 		}
 
 		contract.Failf("Unrecognized resource step op: %v", op)
@@ -350,6 +350,11 @@ func (data *resourceRowData) getInfoColumn() string {
 			writePropertyKeys(changesBuf, diff.Adds, deploy.OpCreate)
 			writePropertyKeys(changesBuf, diff.Deletes, deploy.OpDelete)
 			writePropertyKeys(changesBuf, updates, deploy.OpUpdate)
+
+			// Note any replacement that was forced by the user rather than caused by a change.
+			if step.Forced {
+				writeString(changesBuf, " "+deploy.OpReplace.Prefix()+"(forced)"+colors.Reset)
+			}
 		}
 	}
 
@@ -15,17 +15,26 @@
 package local
 
 import (
+	"encoding/json"
 	"os"
 
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/local/blob"
+	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 )
 
 // localSnapshotManager is a simple SnapshotManager implementation that persists snapshots
 // to disk on the local machine.
 type localSnapshotPersister struct {
-	name    tokens.QName
-	backend *localBackend
+	name     tokens.QName
+	backend  *localBackend
+	backedUp bool // true once the checkpoint that preceded the update has been backed up
 }
 
 func (sm *localSnapshotPersister) Invalidate() error {
@@ -34,15 +43,61 @@ func (sm *localSnapshotPersister) Invalidate() error {
 
 func (sm *localSnapshotPersister) Save(snapshot *deploy.Snapshot) error {
 	config, _, _, err := sm.backend.getStack(sm.name)
-	if err != nil && !os.IsNotExist(err) {
+	if err != nil && !blob.IsNotExist(err) {
 		return err
 	}
 
-	_, err = sm.backend.saveStack(sm.name, config, snapshot)
-	return err
+	// Only the first save backs up the stack's existing checkpoint, so that the backups hold the checkpoints of
+	// earlier updates rather than the intermediate checkpoints of this one.
+	if _, err = sm.backend.writeStack(sm.name, config, snapshot, !sm.backedUp /*backup*/); err != nil {
+		return err
+	}
+	sm.backedUp = true
+	return nil
+}
 
+// localSnapshotJournaler is a localSnapshotPersister that can also journal changes to a snapshot. Each batch of
+// journal entries is written to its own blob alongside the stack's checkpoint, keyed by the generation of the
+// checkpoint it applies to and its position in the journal.
+type localSnapshotJournaler struct {
+	localSnapshotPersister
+	gen int64 // the generation of the last checkpoint saved, or zero if none has been saved yet
+	seq int   // the sequence number of the next batch of journal entries
 }
 
-func (b *localBackend) newSnapshotPersister(stackName tokens.QName) *localSnapshotPersister {
-	return &localSnapshotPersister{name: stackName, backend: b}
+func (sm *localSnapshotJournaler) Save(snapshot *deploy.Snapshot) error {
+	if err := sm.localSnapshotPersister.Save(snapshot); err != nil {
+		return err
+	}
+	sm.gen, sm.seq = journalGeneration(snapshot.Manifest.Time), 0
+	return nil
+}
+
+func (sm *localSnapshotJournaler) Encrypter() config.Encrypter {
+	return sm.backend.stateCrypter(sm.name)
+}
+
+func (sm *localSnapshotJournaler) Append(entries []apitype.JournalEntryV1) error {
+	if sm.gen == 0 {
+		return errors.New("cannot journal changes to a snapshot that has not been saved")
+	}
+
+	byts, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	key := sm.backend.journalKey(sm.name, sm.gen, sm.seq)
+	if err = sm.backend.bucket.Put(key, byts, blob.PutOptions{IfNotExists: true}); err != nil {
+		return errors.Wrapf(err, "writing journal entries to %s", sm.backend.location(key))
+	}
+	sm.seq++
+	return nil
+}
+
+func (b *localBackend) newSnapshotPersister(stackName tokens.QName) backend.SnapshotPersister {
+	persister := localSnapshotPersister{name: stackName, backend: b}
+	if cmdutil.IsTruthy(os.Getenv(JournalCheckpointsEnvVar)) {
+		return &localSnapshotJournaler{localSnapshotPersister: persister}
+	}
+	return &persister
 }
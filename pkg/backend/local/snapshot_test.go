@@ -0,0 +1,100 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+func setEnv(t *testing.T, key, value string) func() {
+	old, had := os.LookupEnv(key)
+	assert.NoError(t, os.Setenv(key, value))
+	return func() {
+		if had {
+			contract.IgnoreError(os.Setenv(key, old))
+		} else {
+			contract.IgnoreError(os.Unsetenv(key))
+		}
+	}
+}
+
+func TestJournaledCheckpoint(t *testing.T) {
+	defer setEnv(t, JournalCheckpointsEnvVar, "true")()
+	defer setEnv(t, CompressCheckpointsEnvVar, "true")()
+	b, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	name := tokens.QName("dev")
+	persister := b.newSnapshotPersister(name)
+	journaler, ok := persister.(backend.SnapshotJournaler)
+	if !assert.True(t, ok) {
+		t.FailNow()
+	}
+
+	// Nothing can be journaled before a checkpoint has been saved.
+	res, err := stack.SerializeResource(&resource.State{Type: "test", URN: "b"}, journaler.Encrypter())
+	assert.NoError(t, err)
+	add := apitype.JournalEntryV1{Kind: apitype.JournalEntryAddResource, ID: 1, Index: 1, Resource: &res}
+	assert.Error(t, journaler.Append([]apitype.JournalEntryV1{add}))
+
+	snap := deploy.NewSnapshot(deploy.Manifest{Time: time.Now()}, []*resource.State{
+		{Type: "test", URN: "a"},
+	}, nil)
+	assert.NoError(t, persister.Save(snap))
+
+	// The checkpoint is compressed.
+	byts, err := b.bucket.Get(b.stackKey(name))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x1f, 0x8b}, byts[:2])
+
+	// Journaled entries are applied when the stack is loaded.
+	assert.NoError(t, journaler.Append([]apitype.JournalEntryV1{add}))
+	_, loaded, _, err := b.getStack(name)
+	assert.NoError(t, err)
+	if assert.Len(t, loaded.Resources, 2) {
+		assert.Equal(t, resource.URN("b"), loaded.Resources[1].URN)
+	}
+
+	// Saving a new checkpoint discards the journal.
+	snap = deploy.NewSnapshot(deploy.Manifest{Time: time.Now().Add(time.Second)}, []*resource.State{
+		{Type: "test", URN: "c"},
+	}, nil)
+	assert.NoError(t, persister.Save(snap))
+	files, err := b.bucket.List(b.journalDirectory(name))
+	assert.NoError(t, err)
+	assert.Len(t, files, 0)
+	_, loaded, _, err = b.getStack(name)
+	assert.NoError(t, err)
+	assert.Len(t, loaded.Resources, 1)
+
+	// Removing the stack removes its journal too.
+	assert.NoError(t, journaler.Append([]apitype.JournalEntryV1{add}))
+	assert.NoError(t, b.removeStack(name))
+	files, err = b.bucket.List(b.journalDirectory(name))
+	assert.NoError(t, err)
+	assert.Len(t, files, 0)
+}
@@ -17,10 +17,8 @@ package local
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -28,6 +26,7 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/backend/local/blob"
 	"github.com/pulumi/pulumi/pkg/encoding"
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
@@ -35,13 +34,25 @@ import (
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/cmdutil"
 	"github.com/pulumi/pulumi/pkg/util/contract"
-	"github.com/pulumi/pulumi/pkg/util/fsutil"
 	"github.com/pulumi/pulumi/pkg/util/logging"
 	"github.com/pulumi/pulumi/pkg/workspace"
 )
 
 const DisableCheckpointBackupsEnvVar = "PULUMI_DISABLE_CHECKPOINT_BACKUPS"
 
+// CompressCheckpointsEnvVar can be set to a truthy value to gzip-compress stack checkpoints when they are saved.
+// Compressed checkpoints are read transparently regardless of this setting.
+const CompressCheckpointsEnvVar = "PULUMI_COMPRESS_CHECKPOINTS"
+
+// JournalCheckpointsEnvVar can be set to a truthy value to record changes to a stack's checkpoint during an update
+// incrementally, by appending small journal entries, rather than by rewriting the entire checkpoint on every change.
+// This substantially reduces the I/O performed when updating large stacks.
+const JournalCheckpointsEnvVar = "PULUMI_JOURNAL_CHECKPOINTS"
+
+// checkpointBackupCount is the number of numbered backups (.bak.1, .bak.2, ...) of a stack's checkpoint file that are
+// retained alongside it. The lowest-numbered backup is the most recent.
+const checkpointBackupCount = 10
+
 // DisableIntegrityChecking can be set to true to disable checkpoint state integrity verification.  This is not
 // recommended, because it could mean proceeding even in the face of a corrupted checkpoint state file, but can
 // be used as a last resort when a command absolutely must be run.
@@ -90,10 +101,7 @@ func (b *localBackend) getTarget(stackName tokens.QName) (*deploy.Target, error)
 	if err != nil {
 		return nil, err
 	}
-	decrypter, err := defaultCrypter(stackName, stk.Config)
-	if err != nil {
-		return nil, err
-	}
+	decrypter := b.stateCrypter(stackName)
 	_, snapshot, _, err := b.getStack(stackName)
 	if err != nil {
 		return nil, err
@@ -111,7 +119,7 @@ func (b *localBackend) getStack(name tokens.QName) (config.Map, *deploy.Snapshot
 		return nil, nil, "", errors.New("invalid empty stack name")
 	}
 
-	file := b.stackPath(name)
+	file := b.location(b.stackKey(name))
 
 	chk, err := b.getCheckpoint(name)
 	if err != nil {
@@ -119,7 +127,7 @@ func (b *localBackend) getStack(name tokens.QName) (config.Map, *deploy.Snapshot
 	}
 
 	// Materialize an actual snapshot object.
-	snapshot, err := stack.DeserializeCheckpoint(chk)
+	snapshot, err := stack.DeserializeCheckpoint(chk, b.stateCrypter(name))
 	if err != nil {
 		return nil, nil, "", err
 	}
@@ -137,50 +145,132 @@ func (b *localBackend) getStack(name tokens.QName) (config.Map, *deploy.Snapshot
 
 // GetCheckpoint loads a checkpoint file for the given stack in this project, from the current project workspace.
 func (b *localBackend) getCheckpoint(stackName tokens.QName) (*apitype.CheckpointV2, error) {
-	chkpath := b.stackPath(stackName)
-	bytes, err := ioutil.ReadFile(chkpath)
+	bytes, err := b.bucket.Get(b.stackKey(stackName))
 	if err != nil {
 		return nil, err
 	}
 
-	return stack.UnmarshalVersionedCheckpointToLatestCheckpoint(bytes)
+	chk, err := stack.UnmarshalVersionedCheckpointToLatestCheckpoint(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// If changes to the checkpoint were journaled since it was last saved, pick them up too.
+	if chk.Latest != nil {
+		journal, err := b.getJournal(stackName, journalGeneration(chk.Latest.Manifest.Time))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load checkpoint journal")
+		}
+		chk.Journal = append(chk.Journal, journal...)
+	}
+	return chk, nil
+}
+
+// journalGeneration returns the generation of the journal that applies to a checkpoint whose manifest has the given
+// time. Journal entries belonging to any other generation are stale and are ignored.
+func journalGeneration(t time.Time) int64 {
+	return t.UnixNano()
+}
+
+// journalKey returns the key of the seq'th batch of journal entries for the given generation of a stack's checkpoint.
+// Keys are zero-padded so that listing them returns the batches in order.
+func (b *localBackend) journalKey(name tokens.QName, gen int64, seq int) string {
+	return path.Join(b.journalDirectory(name), fmt.Sprintf("%020d-%010d.json", gen, seq))
+}
+
+// getJournal loads the journal entries recorded for the given generation of a stack's checkpoint, in order.
+func (b *localBackend) getJournal(name tokens.QName, gen int64) ([]apitype.JournalEntryV1, error) {
+	files, err := b.bucket.List(b.journalDirectory(name))
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Sprintf("%020d-", gen)
+	var journal []apitype.JournalEntryV1
+	for _, file := range files {
+		if !strings.HasPrefix(path.Base(file.Key), prefix) {
+			continue
+		}
+		byts, err := b.bucket.Get(file.Key)
+		if err != nil {
+			return nil, err
+		}
+		var entries []apitype.JournalEntryV1
+		if err = json.Unmarshal(byts, &entries); err != nil {
+			return nil, errors.Wrapf(err, "%s", b.location(file.Key))
+		}
+		journal = append(journal, entries...)
+	}
+	return journal, nil
+}
+
+// removeJournal deletes all journal entries recorded for a stack.
+func (b *localBackend) removeJournal(name tokens.QName) error {
+	files, err := b.bucket.List(b.journalDirectory(name))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err = b.bucket.Delete(file.Key); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (b *localBackend) saveStack(name tokens.QName,
 	config map[config.Key]config.Value, snap *deploy.Snapshot) (string, error) {
+	return b.writeStack(name, config, snap, true /*backup*/)
+}
+
+// writeStack writes out the stack's checkpoint, first backing up the existing checkpoint if backup is true. An update
+// saves its stack's checkpoint many times, but only the checkpoint that preceded the update needs to be backed up.
+func (b *localBackend) writeStack(name tokens.QName,
+	config map[config.Key]config.Value, snap *deploy.Snapshot, backup bool) (string, error) {
 	// Make a serializable stack and then use the encoder to encode it.
-	file := b.stackPath(name)
-	m, ext := encoding.Detect(file)
+	key := b.stackKey(name)
+	m, ext := encoding.Detect(key)
 	if m == nil {
 		return "", errors.Errorf("resource serialization failed; illegal markup extension: '%v'", ext)
 	}
-	if filepath.Ext(file) == "" {
-		file = file + ext
+	chk, err := stack.SerializeCheckpoint(name, config, snap, b.stateCrypter(name))
+	if err != nil {
+		return "", errors.Wrap(err, "serializing checkpoint")
 	}
-	chk := stack.SerializeCheckpoint(name, config, snap)
 	byts, err := m.Marshal(chk)
 	if err != nil {
 		return "", errors.Wrap(err, "An IO error occurred during the current operation")
 	}
+	if cmdutil.IsTruthy(os.Getenv(CompressCheckpointsEnvVar)) {
+		if byts, err = stack.CompressCheckpoint(byts); err != nil {
+			return "", errors.Wrap(err, "An IO error occurred during the current operation")
+		}
+	}
 
 	// Back up the existing file if it already exists.
-	bck := backupTarget(file)
-
-	// Ensure the directory exists.
-	if err = os.MkdirAll(filepath.Dir(file), 0700); err != nil {
-		return "", errors.Wrap(err, "An IO error occurred during the current operation")
+	bck := b.location(backupTargetKey(key, 1))
+	if backup {
+		bck = b.backupTarget(key, true /*keepOriginal*/)
 	}
 
-	// And now write out the new snapshot file, overwriting that location.
-	if err = ioutil.WriteFile(file, byts, 0600); err != nil {
+	// And now write out the new snapshot file, atomically replacing the old one, so that a crash midway through
+	// cannot leave us without a valid checkpoint.
+	if err = b.bucket.Put(key, byts, blob.PutOptions{}); err != nil {
 		return "", errors.Wrap(err, "An IO error occurred during the current operation")
 	}
 
+	file := b.location(key)
 	logging.V(7).Infof("Saved stack %s checkpoint to: %s (backup=%s)", name, file, bck)
 
+	// The checkpoint now reflects any changes that were journaled, so the journal can be discarded.
+	if err = b.removeJournal(name); err != nil {
+		return "", errors.Wrap(err, "removing checkpoint journal")
+	}
+
 	// And if we are retaining historical checkpoint information, write it out again
 	if cmdutil.IsTruthy(os.Getenv("PULUMI_RETAIN_CHECKPOINTS")) {
-		if err = ioutil.WriteFile(fmt.Sprintf("%v.%v", file, time.Now().UnixNano()), byts, 0600); err != nil {
+		retainKey := fmt.Sprintf("%v.%v", key, time.Now().UnixNano())
+		if err = b.bucket.Put(retainKey, byts, blob.PutOptions{}); err != nil {
 			return "", errors.Wrap(err, "An IO error occurred during the current operation")
 		}
 	}
@@ -204,22 +294,55 @@ func (b *localBackend) removeStack(name tokens.QName) error {
 	contract.Require(name != "", "name")
 
 	// Just make a backup of the file and don't write out anything new.
-	file := b.stackPath(name)
-	backupTarget(file)
+	b.backupTarget(b.stackKey(name), false /*keepOriginal*/)
+
+	if err := b.removeJournal(name); err != nil {
+		return err
+	}
 
 	historyDir := b.historyDirectory(name)
-	return os.RemoveAll(historyDir)
+	files, err := b.bucket.List(historyDir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err = b.bucket.Delete(file.Key); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// backupTarget makes a backup of an existing file, in preparation for writing a new one.  Instead of a copy, it
-// simply renames the file, which is simpler, more efficient, etc.
-func backupTarget(file string) string {
-	contract.Require(file != "", "file")
-	bck := file + ".bak"
-	err := os.Rename(file, bck)
+// backupTarget makes a backup of an existing file, in preparation for writing a new one or removing it, and returns
+// the location of the backup.  Up to checkpointBackupCount numbered backups are retained: the existing backups are
+// shifted up by one (discarding the oldest) and the file becomes the new ".bak.1".  If keepOriginal is false the file
+// is simply renamed, which is simpler and more efficient; otherwise it is copied, so that a valid checkpoint exists at
+// all times.
+func (b *localBackend) backupTarget(key string, keepOriginal bool) string {
+	contract.Require(key != "", "key")
+	bck := backupTargetKey(key, 1)
+	byts, err := b.bucket.Get(key)
+	if err != nil {
+		return b.location(bck)
+	}
+
+	for i := checkpointBackupCount - 1; i > 0; i-- {
+		err = b.bucket.Rename(backupTargetKey(key, i), backupTargetKey(key, i+1))
+		contract.IgnoreError(err) // ignore errors; this backup may not exist yet.
+	}
+
+	if keepOriginal {
+		err = b.bucket.Put(bck, byts, blob.PutOptions{})
+	} else {
+		err = b.bucket.Rename(key, bck)
+	}
 	contract.IgnoreError(err) // ignore errors.
-	// IDEA: consider multiple backups (.bak.bak.bak...etc).
-	return bck
+	return b.location(bck)
+}
+
+// backupTargetKey returns the key of the nth numbered backup of the given file.
+func backupTargetKey(key string, n int) string {
+	return fmt.Sprintf("%s.bak.%d", key, n)
 }
 
 // backupStack copies the current Checkpoint file to ~/.pulumi/backups.
@@ -232,47 +355,47 @@ func (b *localBackend) backupStack(name tokens.QName) error {
 	}
 
 	// Read the current checkpoint file. (Assuming it aleady exists.)
-	stackPath := b.stackPath(name)
-	byts, err := ioutil.ReadFile(stackPath)
+	stackKey := b.stackKey(name)
+	byts, err := b.bucket.Get(stackKey)
 	if err != nil {
 		return err
 	}
 
-	// Get the backup directory.
-	backupDir := b.backupDirectory(name)
-
-	// Ensure the backup directory exists.
-	if err = os.MkdirAll(backupDir, 0700); err != nil {
-		return err
-	}
-
 	// Write out the new backup checkpoint file.
-	stackFile := filepath.Base(stackPath)
-	ext := filepath.Ext(stackFile)
+	stackFile := path.Base(stackKey)
+	ext := path.Ext(stackFile)
 	base := strings.TrimSuffix(stackFile, ext)
 	backupFile := fmt.Sprintf("%s.%v%s", base, time.Now().UnixNano(), ext)
-	return ioutil.WriteFile(filepath.Join(backupDir, backupFile), byts, 0600)
+	return b.bucket.Put(path.Join(b.backupDirectory(name), backupFile), byts, blob.PutOptions{})
 }
 
-func (b *localBackend) stackPath(stack tokens.QName) string {
-	path := filepath.Join(b.stateRoot, workspace.StackDir)
-	if stack != "" {
-		path = filepath.Join(path, fsutil.QnamePath(stack)+".json")
-	}
+// location returns a human-readable location for the blob with the given key, for use in messages.
+func (b *localBackend) location(key string) string {
+	return strings.TrimSuffix(b.bucket.String(), "/") + "/" + key
+}
 
-	return path
+func (b *localBackend) stackKey(stack tokens.QName) string {
+	contract.Require(stack != "", "stack")
+
+	return path.Join(workspace.StackDir, string(stack)+".json")
 }
 
 func (b *localBackend) historyDirectory(stack tokens.QName) string {
 	contract.Require(stack != "", "stack")
 
-	return filepath.Join(b.stateRoot, workspace.HistoryDir, fsutil.QnamePath(stack))
+	return path.Join(workspace.HistoryDir, string(stack))
+}
+
+func (b *localBackend) journalDirectory(stack tokens.QName) string {
+	contract.Require(stack != "", "stack")
+
+	return path.Join(workspace.JournalDir, string(stack))
 }
 
 func (b *localBackend) backupDirectory(stack tokens.QName) string {
 	contract.Require(stack != "", "stack")
 
-	return filepath.Join(b.stateRoot, workspace.BackupDir, fsutil.QnamePath(stack))
+	return path.Join(workspace.BackupDir, string(stack))
 }
 
 // getHistory returns locally stored update history. The first element of the result will be
@@ -280,53 +403,82 @@ func (b *localBackend) backupDirectory(stack tokens.QName) string {
 func (b *localBackend) getHistory(name tokens.QName) ([]backend.UpdateInfo, error) {
 	contract.Require(name != "", "name")
 
+	// History doesn't exist until a stack has been updated, in which case the listing is empty.
 	dir := b.historyDirectory(name)
-	allFiles, err := ioutil.ReadDir(dir)
+	allFiles, err := b.bucket.List(dir)
 	if err != nil {
-		// History doesn't exist until a stack has been updated.
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
 		return nil, err
 	}
 
 	var updates []backend.UpdateInfo
 
-	// os.ReadDir returns the array sorted by file name, but because of how we name files, older updates come before
-	// newer ones. Loop backwards so we added the newest updates to the array we will return first.
-	for i := len(allFiles) - 1; i >= 0; i-- {
-		file := allFiles[i]
-		filepath := path.Join(dir, file.Name())
-
-		// Open all of the history files, ignoring the checkpoints.
-		if !strings.HasSuffix(filepath, ".history.json") {
-			continue
-		}
+	// The listing is sorted by key, but because of how we name files, older updates come before newer ones. Loop
+	// backwards so we added the newest updates to the array we will return first.
+	historyKeys := historyFiles(allFiles, ".history.json")
+	for i := len(historyKeys) - 1; i >= 0; i-- {
+		key := historyKeys[i]
 
 		var update backend.UpdateInfo
-		b, err := ioutil.ReadFile(filepath)
+		byts, err := b.bucket.Get(key)
 		if err != nil {
-			return nil, errors.Wrapf(err, "reading history file %s", filepath)
+			return nil, errors.Wrapf(err, "reading history file %s", key)
 		}
-		err = json.Unmarshal(b, &update)
+		err = json.Unmarshal(byts, &update)
 		if err != nil {
-			return nil, errors.Wrapf(err, "reading history file %s", filepath)
+			return nil, errors.Wrapf(err, "reading history file %s", key)
 		}
 
+		// Updates are versioned in the order in which they occurred.
+		update.Version = i + 1
 		updates = append(updates, update)
 	}
 
 	return updates, nil
 }
 
+// getHistoricalCheckpoint loads the copy of a stack's checkpoint that was saved to its history by the update that
+// produced the given version, as numbered by getHistory.
+func (b *localBackend) getHistoricalCheckpoint(name tokens.QName, version int) (*apitype.CheckpointV2, error) {
+	contract.Require(name != "", "name")
+
+	allFiles, err := b.bucket.List(b.historyDirectory(name))
+	if err != nil {
+		return nil, err
+	}
+
+	historyKeys := historyFiles(allFiles, ".history.json")
+	if version < 1 || version > len(historyKeys) {
+		return nil, errors.Errorf("stack '%s' has no version %d", name, version)
+	}
+
+	key := strings.TrimSuffix(historyKeys[version-1], ".history.json") + ".checkpoint.json"
+	byts, err := b.bucket.Get(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading checkpoint file %s", key)
+	}
+	chk, err := stack.UnmarshalVersionedCheckpointToLatestCheckpoint(byts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading checkpoint file %s", key)
+	}
+	return chk, nil
+}
+
+// historyFiles returns the keys of the history files with the given suffix, oldest first.
+func historyFiles(files []blob.Attributes, suffix string) []string {
+	var keys []string
+	for _, file := range files {
+		if strings.HasSuffix(file.Key, suffix) {
+			keys = append(keys, file.Key)
+		}
+	}
+	return keys
+}
+
 // addToHistory saves the UpdateInfo and makes a copy of the current Checkpoint file.
 func (b *localBackend) addToHistory(name tokens.QName, update backend.UpdateInfo) error {
 	contract.Require(name != "", "name")
 
 	dir := b.historyDirectory(name)
-	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-		return err
-	}
 
 	// Prefix for the update and checkpoint files.
 	pathPrefix := path.Join(dir, fmt.Sprintf("%s-%d", name, time.Now().UnixNano()))
@@ -338,16 +490,16 @@ func (b *localBackend) addToHistory(name tokens.QName, update backend.UpdateInfo
 	}
 
 	historyFile := fmt.Sprintf("%s.history.json", pathPrefix)
-	if err = ioutil.WriteFile(historyFile, byts, os.ModePerm); err != nil {
+	if err = b.bucket.Put(historyFile, byts, blob.PutOptions{}); err != nil {
 		return err
 	}
 
 	// Make a copy of the checkpoint file. (Assuming it aleady exists.)
-	byts, err = ioutil.ReadFile(b.stackPath(name))
+	byts, err = b.bucket.Get(b.stackKey(name))
 	if err != nil {
 		return err
 	}
 
 	checkpointFile := fmt.Sprintf("%s.checkpoint.json", pathPrefix)
-	return ioutil.WriteFile(checkpointFile, byts, os.ModePerm)
+	return b.bucket.Put(checkpointFile, byts, blob.PutOptions{})
 }
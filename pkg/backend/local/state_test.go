@@ -0,0 +1,139 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/backend"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+func TestHistoryVersions(t *testing.T) {
+	b, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	name := tokens.QName("dev")
+	ref := localBackendReference{name: name}
+	for _, urn := range []resource.URN{"a", "b"} {
+		snap := deploy.NewSnapshot(deploy.Manifest{}, []*resource.State{{Type: "test", URN: urn}}, nil)
+		_, err := b.saveStack(name, nil, snap)
+		assert.NoError(t, err)
+		assert.NoError(t, b.addToHistory(name, backend.UpdateInfo{Message: string(urn)}))
+	}
+
+	// Updates are listed newest first, and numbered from the oldest.
+	updates, err := b.GetHistory(context.Background(), ref)
+	assert.NoError(t, err)
+	if assert.Len(t, updates, 2) {
+		assert.Equal(t, "b", updates[0].Message)
+		assert.Equal(t, 2, updates[0].Version)
+		assert.Equal(t, "a", updates[1].Message)
+		assert.Equal(t, 1, updates[1].Version)
+	}
+
+	// Each version's deployment reflects the checkpoint as of that update.
+	deployment, err := b.ExportDeploymentVersion(context.Background(), ref, 1)
+	assert.NoError(t, err)
+	snap, err := stack.DeserializeUntypedDeployment(deployment, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, snap.Resources, 1) {
+		assert.Equal(t, resource.URN("a"), snap.Resources[0].URN)
+	}
+
+	_, err = b.ExportDeploymentVersion(context.Background(), ref, 3)
+	assert.Error(t, err)
+	_, err = b.ExportDeploymentVersion(context.Background(), ref, 0)
+	assert.Error(t, err)
+}
+
+func TestRenameStack(t *testing.T) {
+	b, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	oldName, newName := tokens.QName("old"), tokens.QName("new")
+	rootURN := resource.NewURN(oldName, "proj", "", resource.RootStackType, "proj-old")
+	childType := tokens.Type("pkgA:m:typA")
+	childURN := resource.NewURN(oldName, "proj", resource.RootStackType, childType, "child")
+	snap := deploy.NewSnapshot(deploy.Manifest{}, []*resource.State{
+		{Type: resource.RootStackType, URN: rootURN},
+		{Type: childType, URN: childURN, Parent: rootURN, Dependencies: []resource.URN{rootURN}},
+	}, nil)
+	_, err := b.saveStack(oldName, nil, snap)
+	assert.NoError(t, err)
+
+	assert.NoError(t, b.RenameStack(context.Background(), localBackendReference{name: oldName}, newName))
+
+	_, _, _, err = b.getStack(oldName)
+	assert.Error(t, err)
+	_, renamed, _, err := b.getStack(newName)
+	assert.NoError(t, err)
+
+	newRootURN := resource.NewURN(newName, "proj", "", resource.RootStackType, "proj-new")
+	if assert.Len(t, renamed.Resources, 2) {
+		assert.Equal(t, newRootURN, renamed.Resources[0].URN)
+		assert.Equal(t, resource.NewURN(newName, "proj", resource.RootStackType, childType, "child"),
+			renamed.Resources[1].URN)
+		assert.Equal(t, newRootURN, renamed.Resources[1].Parent)
+		assert.Equal(t, []resource.URN{newRootURN}, renamed.Resources[1].Dependencies)
+	}
+}
+
+func TestImportDeploymentWithNewCrypter(t *testing.T) {
+	b, cleanup := newTestBackend(t)
+	defer cleanup()
+
+	// Save a stack whose state holds a secret encrypted by one crypter.
+	name := tokens.QName("dev")
+	b.crypters[name] = config.NewSymmetricCrypterFromPassphrase("old", []byte("salt"))
+	urn := resource.NewURN(name, "proj", "", "pkgA:m:typA", "resA")
+	snap := deploy.NewSnapshot(deploy.Manifest{}, []*resource.State{{
+		Type:    "pkgA:m:typA",
+		URN:     urn,
+		Custom:  true,
+		ID:      "id",
+		Outputs: resource.PropertyMap{"password": resource.MakeSecret(resource.NewStringProperty("hunter2"))},
+	}}, nil)
+	_, err := b.saveStack(name, nil, snap)
+	assert.NoError(t, err)
+
+	// Importing state encrypted by a different crypter should succeed once the stack uses that crypter, even though
+	// the existing state cannot be decrypted by it.
+	newCrypter := config.NewSymmetricCrypterFromPassphrase("new", []byte("salt"))
+	b.crypters[name] = newCrypter
+	deployment, err := stack.SerializeDeployment(snap, newCrypter)
+	assert.NoError(t, err)
+	bytes, err := json.Marshal(deployment)
+	assert.NoError(t, err)
+	err = b.ImportDeployment(context.Background(), localBackendReference{name: name}, &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: bytes,
+	})
+	assert.NoError(t, err)
+
+	_, imported, _, err := b.getStack(name)
+	if assert.NoError(t, err) && assert.Len(t, imported.Resources, 1) {
+		assert.Equal(t, snap.Resources[0].Outputs, imported.Resources[0].Outputs)
+	}
+}
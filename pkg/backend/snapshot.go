@@ -18,9 +18,13 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/engine"
 	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/logging"
 	"github.com/pulumi/pulumi/pkg/version"
@@ -35,6 +39,23 @@ type SnapshotPersister interface {
 	Save(snapshot *deploy.Snapshot) error
 }
 
+// SnapshotJournaler is an optional interface implemented by snapshot persisters that can record incremental changes to
+// a snapshot. When the persister given to a SnapshotManager implements it, each mutation of the snapshot is recorded
+// by appending a small number of journal entries, rather than by saving the entire snapshot. The journal is
+// periodically compacted by saving the entire snapshot, which must discard any entries appended before it.
+type SnapshotJournaler interface {
+	// Encrypter returns the encrypter with which secret values are protected in journal entries.
+	Encrypter() config.Encrypter
+	// Append durably records the given journal entries, which apply on top of the last snapshot saved and any entries
+	// appended since. Returns an error if the entries could not be recorded.
+	Append(entries []apitype.JournalEntryV1) error
+}
+
+// journalCompactionMinimum is the minimum number of journal entries that are appended before a journal is compacted.
+// Journals are also allowed to grow as long as the snapshot they apply to, so that the cost of compaction is
+// amortized over the entries it discards.
+const journalCompactionMinimum = 256
+
 // SnapshotManager is an implementation of engine.SnapshotManager that inspects steps and performs
 // mutations on the global snapshot object serially. This implementation maintains two bits of state: the "base"
 // snapshot, which is completely immutable and represents the state of the world prior to the application
@@ -60,13 +81,30 @@ type SnapshotManager struct {
 	doVerify         bool                     // If true, verify the snapshot before persisting it
 	plugins          []workspace.PluginInfo   // The list of plugins loaded by the plan, to be saved in the manifest
 	mutationRequests chan func()              // The queue of mutation requests, to be retired serially by the manager
+
+	journaler  SnapshotJournaler        // If non-nil, the journaler to which mutations are recorded
+	journal    []apitype.JournalEntryV1 // The journal entries recorded by the mutation in progress
+	journaled  int                      // The number of journal entries appended since the last snapshot was saved
+	needsSave  bool                     // If true, the next mutation must save the entire snapshot
+	ids        map[*resource.State]int  // The journal numbers of the resources in the snapshot
+	opIDs      map[*resource.State]int  // The journal numbers of the pending operations, keyed by their resource
+	nextID     int                      // The journal number of the next resource to be added
+	nextOpID   int                      // The journal number of the next operation to begin
+	baseStates map[*resource.State]bool // The set of resources in the base snapshot
 }
 
 var _ engine.SnapshotManager = (*SnapshotManager)(nil)
 
 func (sm *SnapshotManager) Close() error {
+	// If mutations have been journaled since the snapshot was last saved, compact the journal one last time.
+	var err error
+	if sm.journaler != nil {
+		err = sm.mutate(func() {
+			sm.needsSave = sm.needsSave || sm.journaled > 0
+		})
+	}
 	close(sm.mutationRequests)
-	return nil
+	return err
 }
 
 // If you need to understand what's going on in this file, start here!
@@ -89,7 +127,7 @@ func (sm *SnapshotManager) mutate(mutator func()) error {
 		mutator()
 
 		snap := sm.snap()
-		err := sm.persister.Save(snap)
+		err := sm.persist(snap)
 		if err == nil && sm.doVerify {
 			if err = snap.VerifyIntegrity(); err != nil {
 				err = errors.Wrapf(err, "after mutation of snapshot")
@@ -106,6 +144,75 @@ func (sm *SnapshotManager) mutate(mutator func()) error {
 	return <-responseChan
 }
 
+// persist records the given snapshot, which reflects the mutation that just ran. Without a journaler, the snapshot
+// is simply saved. With one, the journal entries recorded by the mutation are appended to the journal, unless the
+// journal is due to be compacted, in which case the snapshot is saved in its entirety.
+func (sm *SnapshotManager) persist(snap *deploy.Snapshot) error {
+	entries := sm.journal
+	sm.journal = nil
+	if sm.journaler == nil {
+		return sm.persister.Save(snap)
+	}
+
+	compactAt := journalCompactionMinimum
+	if len(snap.Resources) > compactAt {
+		compactAt = len(snap.Resources)
+	}
+	if sm.needsSave || sm.journaled+len(entries) > compactAt {
+		if err := sm.persister.Save(snap); err != nil {
+			sm.needsSave = true
+			return err
+		}
+		sm.resetJournal(snap)
+		return nil
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := sm.journaler.Append(entries); err != nil {
+		// The journal numbering may no longer match what was persisted, so start afresh with the next mutation.
+		sm.needsSave = true
+		return err
+	}
+	sm.journaled += len(entries)
+	return nil
+}
+
+// resetJournal renumbers the resources and operations in the given snapshot, which was just saved, so that further
+// journal entries apply on top of it.
+func (sm *SnapshotManager) resetJournal(snap *deploy.Snapshot) {
+	sm.ids = make(map[*resource.State]int)
+	for i, res := range snap.Resources {
+		sm.ids[res] = i
+	}
+	sm.opIDs = make(map[*resource.State]int)
+	for i, op := range snap.PendingOperations {
+		sm.opIDs[op.Resource] = i
+	}
+	sm.nextID, sm.nextOpID = len(snap.Resources), len(snap.PendingOperations)
+	sm.journaled, sm.needsSave = 0, false
+}
+
+// record adds a journal entry to those recorded by the mutation in progress, if mutations are being journaled.
+func (sm *SnapshotManager) record(entry apitype.JournalEntryV1) {
+	if sm.journaler != nil && !sm.needsSave {
+		sm.journal = append(sm.journal, entry)
+	}
+}
+
+// serializeState serializes a resource state for inclusion in a journal entry. If the state cannot be serialized, the
+// journal is abandoned in favor of saving the entire snapshot, which will report the failure.
+func (sm *SnapshotManager) serializeState(state *resource.State) *apitype.ResourceV2 {
+	res, err := stack.SerializeResource(state, sm.journaler.Encrypter())
+	if err != nil {
+		logging.V(9).Infof("SnapshotManager: failed to serialize journal entry for %v: %v", state.URN, err)
+		sm.needsSave = true
+		return nil
+	}
+	return &res
+}
+
 // RegisterResourceOutputs handles the registering of outputs on a Step that has already
 // completed. This is accomplished by doing an in-place mutation of the resources currently
 // resident in the snapshot.
@@ -113,12 +220,14 @@ func (sm *SnapshotManager) mutate(mutator func()) error {
 // Due to the way this is currently implemented, the engine directly mutates output properties
 // on the resource State object that it created. Since we are storing pointers to these objects
 // in the `resources` slice, we need only to do a no-op mutation in order to flush these new
-// mutations to disk.
+// mutations to disk. When journaling, the resource's new state is recorded as an update.
 //
 // Note that this is completely not thread-safe and defeats the purpose of having a `mutate` callback
 // entirely, but the hope is that this state of things will not be permament.
 func (sm *SnapshotManager) RegisterResourceOutputs(step deploy.Step) error {
-	return sm.refresh()
+	return sm.mutate(func() {
+		sm.markChanged(step.New())
+	})
 }
 
 // RecordPlugin records that the current plan loaded a plugin and saves it in the snapshot.
@@ -126,6 +235,9 @@ func (sm *SnapshotManager) RecordPlugin(plugin workspace.PluginInfo) error {
 	logging.V(9).Infof("SnapshotManager: RecordPlugin(%v)", plugin)
 	return sm.mutate(func() {
 		sm.plugins = append(sm.plugins, plugin)
+
+		plug := stack.SerializePlugin(plugin)
+		sm.record(apitype.JournalEntryV1{Kind: apitype.JournalEntryAddPlugin, Plugin: &plug})
 	})
 }
 
@@ -149,6 +261,8 @@ func (sm *SnapshotManager) BeginMutation(step deploy.Step) (engine.SnapshotMutat
 		return &replaceSnapshotMutation{sm}, nil
 	case deploy.OpRead, deploy.OpReadReplacement:
 		return sm.doRead(step)
+	case deploy.OpImport, deploy.OpImportReplacement:
+		return sm.doImport(step)
 	}
 
 	contract.Failf("unknown StepOp: %s", step.Op())
@@ -172,6 +286,10 @@ func (ssm *sameSnapshotMutation) End(step deploy.Step, successful bool) error {
 	contract.Require(step != nil, "step != nil")
 	logging.V(9).Infof("SnapshotManager: sameSnapshotMutation.End(..., %v)", successful)
 	return ssm.manager.mutate(func() {
+		// If this step stands in for the creation of an untargeted resource, there is nothing to persist.
+		if same, ok := step.(*deploy.SameStep); ok && same.IsSkippedCreate() {
+			return
+		}
 		if successful {
 			ssm.manager.markDone(step.Old())
 			ssm.manager.markNew(step.New())
@@ -209,7 +327,11 @@ func (csm *createSnapshotMutation) End(step deploy.Step, successful bool) error
 			// being replaced as part of a Create-Before-Delete replacement sequence.
 			// Since we are storing the base snapshot and all resources by reference
 			// (we have pointers to engine-allocated objects), this transparently
-			// "just works" for the SnapshotManager.
+			// "just works" for the SnapshotManager. When journaling, though, the
+			// replaced resource's new state must be recorded explicitly.
+			if step.Old() != nil {
+				csm.manager.markChanged(step.Old())
+			}
 			csm.manager.markNew(step.New())
 		}
 	})
@@ -313,6 +435,20 @@ func (rsm *readSnapshotMutation) End(step deploy.Step, successful bool) error {
 	})
 }
 
+func (sm *SnapshotManager) doImport(step deploy.Step) (engine.SnapshotMutation, error) {
+	logging.V(9).Infof("SnapshotManager.doImport(%s)", step.URN())
+	err := sm.mutate(func() {
+		sm.markOperationPending(step.New(), resource.OperationTypeImporting)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Once the resource has been read, an import is recorded exactly like a create: the new state is added to the
+	// snapshot, and any resource it replaces has been marked for deletion by the step itself.
+	return &createSnapshotMutation{sm}, nil
+}
+
 // refresh does a no-op mutation that forces the SnapshotManager to persist the
 // snapshot exactly as it is currently to disk. This is useful when a mutation
 // has failed and we do not intend to persist the failed mutation.
@@ -325,6 +461,10 @@ func (sm *SnapshotManager) refresh() error {
 // in this manner won't be persisted in the snapshot.
 func (sm *SnapshotManager) markDone(state *resource.State) {
 	contract.Assert(state != nil)
+	if id, has := sm.ids[state]; has && sm.baseStates[state] && !sm.dones[state] {
+		sm.record(apitype.JournalEntryV1{Kind: apitype.JournalEntryRemoveResource, ID: id})
+		delete(sm.ids, state)
+	}
 	sm.dones[state] = true
 	logging.V(9).Infof("Marked old state snapshot as done: %v", state.URN)
 }
@@ -334,6 +474,16 @@ func (sm *SnapshotManager) markDone(state *resource.State) {
 // of a resource that will be persisted to the snapshot.
 func (sm *SnapshotManager) markNew(state *resource.State) {
 	contract.Assert(state != nil)
+	if sm.journaler != nil {
+		sm.record(apitype.JournalEntryV1{
+			Kind:     apitype.JournalEntryAddResource,
+			ID:       sm.nextID,
+			Index:    len(sm.resources),
+			Resource: sm.serializeState(state),
+		})
+		sm.ids[state] = sm.nextID
+		sm.nextID++
+	}
 	sm.resources = append(sm.resources, state)
 	logging.V(9).Infof("Appended new state snapshot to be written: %v", state.URN)
 }
@@ -341,6 +491,16 @@ func (sm *SnapshotManager) markNew(state *resource.State) {
 // markOperationPending marks a resource as undergoing an operation that will now be considered pending.
 func (sm *SnapshotManager) markOperationPending(state *resource.State, op resource.OperationType) {
 	contract.Assert(state != nil)
+	if sm.journaler != nil {
+		sm.record(apitype.JournalEntryV1{
+			Kind:          apitype.JournalEntryBeginOperation,
+			ID:            sm.nextOpID,
+			Resource:      sm.serializeState(state),
+			OperationType: apitype.OperationType(op),
+		})
+		sm.opIDs[state] = sm.nextOpID
+		sm.nextOpID++
+	}
 	sm.operations = append(sm.operations, resource.NewOperation(state, op))
 	logging.V(9).Infof("SnapshotManager.markPendingOperation(%s, %s)", state.URN, string(op))
 }
@@ -348,10 +508,27 @@ func (sm *SnapshotManager) markOperationPending(state *resource.State, op resour
 // markOperationComplete marks a resource as having completed the operation that it previously was performing.
 func (sm *SnapshotManager) markOperationComplete(state *resource.State) {
 	contract.Assert(state != nil)
+	if id, has := sm.opIDs[state]; has && !sm.completeOps[state] {
+		sm.record(apitype.JournalEntryV1{Kind: apitype.JournalEntryCompleteOperation, ID: id})
+		delete(sm.opIDs, state)
+	}
 	sm.completeOps[state] = true
 	logging.V(9).Infof("SnapshotManager.markOperationComplete(%s)", state.URN)
 }
 
+// markChanged records that the engine mutated a resource in place, e.g. by registering its outputs. This only has an
+// effect when journaling, as the resource's new state is otherwise persisted with the rest of the snapshot.
+func (sm *SnapshotManager) markChanged(state *resource.State) {
+	contract.Assert(state != nil)
+	if id, has := sm.ids[state]; has {
+		sm.record(apitype.JournalEntryV1{
+			Kind:     apitype.JournalEntryUpdateResource,
+			ID:       id,
+			Resource: sm.serializeState(state),
+		})
+	}
+}
+
 // snap produces a new Snapshot given the base snapshot and a list of resources that the current
 // plan has created.
 func (sm *SnapshotManager) snap() *deploy.Snapshot {
@@ -431,6 +608,21 @@ func NewSnapshotManager(persister SnapshotPersister, baseSnap *deploy.Snapshot)
 		mutationRequests: make(chan func()),
 	}
 
+	// If the persister can journal mutations, do so. The entire snapshot is saved by the first mutation, so that the
+	// journal applies to a snapshot whose resources are numbered as this manager expects.
+	if journaler, ok := persister.(SnapshotJournaler); ok {
+		manager.journaler = journaler
+		manager.needsSave = true
+		manager.ids = make(map[*resource.State]int)
+		manager.opIDs = make(map[*resource.State]int)
+		manager.baseStates = make(map[*resource.State]bool)
+		if baseSnap != nil {
+			for _, res := range baseSnap.Resources {
+				manager.baseStates[res] = true
+			}
+		}
+	}
+
 	go func() {
 		for request := range manager.mutationRequests {
 			request()
@@ -15,13 +15,17 @@
 package backend
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/version"
 	"github.com/pulumi/pulumi/pkg/workspace"
@@ -263,8 +267,8 @@ func TestVexingDeployment(t *testing.T) {
 	cPrime := NewResource(string(c.URN), bPrime.URN)
 
 	// mocking out the behavior of a provider indicating that this resource needs to be deleted
-	createReplacement := deploy.NewCreateReplacementStep(nil, MockRegisterResourceEvent{}, c, cPrime, nil, true)
-	replace := deploy.NewReplaceStep(nil, c, cPrime, nil, true)
+	createReplacement := deploy.NewCreateReplacementStep(nil, MockRegisterResourceEvent{}, c, cPrime, nil, false, true)
+	replace := deploy.NewReplaceStep(nil, c, cPrime, nil, false, true)
 	c.Delete = true
 
 	applyStep(createReplacement)
@@ -750,3 +754,123 @@ func TestSavePlugins(t *testing.T) {
 	assert.Len(t, lastSnap.Manifest.Plugins, 1)
 	assert.Equal(t, "myplugin", lastSnap.Manifest.Plugins[0].Name)
 }
+
+type MockStackJournaler struct {
+	MockStackPersister
+	Journal []apitype.JournalEntryV1
+}
+
+func (m *MockStackJournaler) Save(snap *deploy.Snapshot) error {
+	m.Journal = nil
+	return m.MockStackPersister.Save(snap)
+}
+
+var journalCrypter = config.NewSymmetricCrypter(make([]byte, config.SymmetricCrypterKeyBytes))
+
+func (m *MockStackJournaler) Encrypter() config.Encrypter {
+	return journalCrypter
+}
+
+func (m *MockStackJournaler) Append(entries []apitype.JournalEntryV1) error {
+	m.Journal = append(m.Journal, entries...)
+	return nil
+}
+
+func TestJournaledMutations(t *testing.T) {
+	a := NewResource("a")
+	b := NewResource("b", a.URN)
+	c := NewResource("c", b.URN)
+	d := NewResource("d", c.URN)
+	snap := NewSnapshot([]*resource.State{a, b, c, d})
+
+	sp := &MockStackJournaler{}
+	manager := NewSnapshotManager(sp, snap)
+
+	// serialize serializes a snapshot for comparison, masking any secrets.
+	serialize := func(snap *deploy.Snapshot) *apitype.DeploymentV2 {
+		dep, err := stack.SerializeDeployment(snap, nil)
+		assert.NoError(t, err)
+		return dep
+	}
+
+	// assertJournaled checks that replaying the journal on top of the last saved snapshot produces the same
+	// resources and pending operations as the snapshot the manager would have saved.
+	assertJournaled := func() {
+		if len(sp.SavedSnapshots) == 0 {
+			return
+		}
+		replayed, err := stack.ReplayJournal(sp.LastSnap(), sp.Journal, journalCrypter)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		expected, actual := serialize(manager.snap()), serialize(replayed)
+		assert.Equal(t, expected.Resources, actual.Resources)
+		assert.Equal(t, expected.PendingOperations, actual.PendingOperations)
+		assert.Equal(t, expected.Manifest.Plugins, actual.Manifest.Plugins)
+	}
+	applyStep := func(step deploy.Step, successful bool) {
+		mutation, err := manager.BeginMutation(step)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assertJournaled()
+		err = mutation.End(step, successful)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assertJournaled()
+	}
+
+	// The first mutation saves the entire snapshot; the rest are journaled.
+	aPrime := NewResource(string(a.URN))
+	applyStep(deploy.NewSameStep(nil, MockRegisterResourceEvent{}, a, aPrime), true)
+	assert.Len(t, sp.SavedSnapshots, 1)
+	assert.Len(t, sp.Journal, 0)
+
+	// The engine marks replaced resources for deletion in place; the journal must pick that up.
+	bPrime := NewResource(string(b.URN), aPrime.URN)
+	createReplacement := deploy.NewCreateReplacementStep(nil, MockRegisterResourceEvent{}, b, bPrime, nil, false, true)
+	replace := deploy.NewReplaceStep(nil, b, bPrime, nil, false, true)
+	b.Delete = true
+	applyStep(createReplacement, true)
+	applyStep(replace, true)
+
+	bPrime.Outputs["key"] = resource.NewStringProperty("value")
+	bPrime.Outputs["password"] = resource.MakeSecret(resource.NewStringProperty("hunter2"))
+	assert.NoError(t, manager.RegisterResourceOutputs(deploy.NewSameStep(nil, nil, bPrime, bPrime)))
+	assertJournaled()
+
+	// Secrets are encrypted in the journal.
+	entry := sp.Journal[len(sp.Journal)-1]
+	assert.NotContains(t, fmt.Sprintf("%v", entry.Resource.Outputs), "hunter2")
+	replayed, err := stack.ReplayJournal(sp.LastSnap(), sp.Journal, journalCrypter)
+	assert.NoError(t, err)
+	for _, res := range replayed.Resources {
+		if res.URN == bPrime.URN && !res.Delete {
+			assert.True(t, res.Outputs["password"].DeepEquals(bPrime.Outputs["password"]))
+		}
+	}
+
+	assert.NoError(t, manager.RecordPlugin(workspace.PluginInfo{Name: "myplugin"}))
+	assertJournaled()
+
+	cFailed := NewResource(string(c.URN), bPrime.URN)
+	cFailed.Inputs["key"] = resource.NewStringProperty("new")
+	applyStep(deploy.NewUpdateStep(nil, MockRegisterResourceEvent{}, c, cFailed, nil), false)
+	cPrime := NewResource(string(c.URN), bPrime.URN)
+	cPrime.Inputs["key"] = resource.NewStringProperty("new")
+	applyStep(deploy.NewUpdateStep(nil, MockRegisterResourceEvent{}, c, cPrime, nil), true)
+
+	applyStep(deploy.NewDeleteStep(nil, d), true)
+	applyStep(deploy.NewDeleteReplacementStep(nil, b, true), true)
+
+	assert.Len(t, sp.SavedSnapshots, 1)
+	assert.NotEmpty(t, sp.Journal)
+
+	// Closing the manager compacts the journal.
+	expected := serialize(manager.snap())
+	assert.NoError(t, manager.Close())
+	assert.Len(t, sp.SavedSnapshots, 2)
+	assert.Len(t, sp.Journal, 0)
+	assert.Equal(t, expected.Resources, serialize(sp.LastSnap()).Resources)
+}
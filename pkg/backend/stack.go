@@ -26,6 +26,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/operations"
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/gitutil"
 	"github.com/pulumi/pulumi/pkg/workspace"
 )
@@ -52,6 +53,8 @@ type Stack interface {
 
 	// remove this stack.
 	Remove(ctx context.Context, force bool) (bool, error)
+	// rename this stack.
+	Rename(ctx context.Context, newName tokens.QName) error
 	// list log entries for this stack.
 	GetLogs(ctx context.Context, query operations.LogQuery) ([]operations.LogEntry, error)
 	// export this stack's deployment.
@@ -65,6 +68,11 @@ func RemoveStack(ctx context.Context, s Stack, force bool) (bool, error) {
 	return s.Backend().RemoveStack(ctx, s.Name(), force)
 }
 
+// RenameStack renames the stack, or returns an error if it cannot.
+func RenameStack(ctx context.Context, s Stack, newName tokens.QName) error {
+	return s.Backend().RenameStack(ctx, s.Name(), newName)
+}
+
 // PreviewStack previews changes to this stack.
 func PreviewStack(ctx context.Context, s Stack, proj *workspace.Project, root string, m UpdateMetadata,
 	opts UpdateOptions, scopes CancellationScopeSource) (engine.ResourceChanges, error) {
@@ -40,6 +40,8 @@ const (
 	RefreshUpdate UpdateKind = "refresh"
 	// DestroyUpdate is an update which removes all resources.
 	DestroyUpdate UpdateKind = "destroy"
+	// RollbackUpdate is an update which returns a stack's resources to a previous version of the stack.
+	RollbackUpdate UpdateKind = "rollback"
 )
 
 // UpdateResult is an enum for the result of the update.
@@ -109,4 +111,8 @@ type UpdateInfo struct {
 	Result          UpdateResult           `json:"result"`
 	EndTime         int64                  `json:"endTime"`
 	ResourceChanges engine.ResourceChanges `json:"resourceChanges,omitempty"`
+
+	// Version is the version of the stack's deployment that the update produced. Versions are numbered from one, in
+	// the order in which updates occurred.
+	Version int `json:"version,omitempty"`
 }
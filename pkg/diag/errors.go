@@ -49,6 +49,53 @@ func GetAnalyzeResourceFailureError(urn resource.URN) *Diag {
 			"\tReason: %v")
 }
 
+func GetAnalyzeStackFailureError(urn resource.URN) *Diag {
+	return newError(urn, 2011,
+		"Analyzer '%v' reported a stack error:\n"+
+			"\tResource: %v\n"+
+			"\tProperty: %v\n"+
+			"\tReason: %v")
+}
+
+func GetAnalyzeAdvisoryViolationWarning(urn resource.URN) *Diag {
+	return newError(urn, 2012,
+		"Analyzer '%v' reported an advisory policy violation:\n"+
+			"\tResource: %v\n"+
+			"\tProperty: %v\n"+
+			"\tReason: %v")
+}
+
 func GetPreviewFailedError(urn resource.URN) *Diag {
 	return newError(urn, 2005, "Preview failed: %v")
 }
+
+func GetTargetedResourceDependsOnUntargetedChangeError(urn resource.URN) *Diag {
+	return newError(urn, 2006,
+		"Resource '%v' depends on '%v', which has pending changes but was not targeted; "+
+			"target it as well or rerun without --target")
+}
+
+func GetTargetedResourceHasUntargetedDependentError(urn resource.URN) *Diag {
+	return newError(urn, 2007,
+		"Resource '%v' cannot be deleted or replaced because '%v' depends on it but was not targeted; "+
+			"target it as well or pass --target-dependents")
+}
+
+func GetResourceCannotBeImportedError(urn resource.URN) *Diag {
+	return newError(urn, 2008, "Resource '%v' cannot be imported: only custom resources may be imported")
+}
+
+func GetAmbiguousImportError(urn resource.URN) *Diag {
+	return newError(urn, 2009, "Resources '%v' and '%v' both match the import of %v '%v'; the import is ambiguous")
+}
+
+func GetImportedResourceNotRegisteredError(urn resource.URN) *Diag {
+	return newError(urn, 2010,
+		"No resource of type %v named '%v' was registered by the program, so it cannot be imported")
+}
+
+func GetImportAlreadyManagedError(urn resource.URN) *Diag {
+	return newError(urn, 2013,
+		"Resource '%v' is already managed by this stack with ID '%v' and cannot be imported with ID '%v'; "+
+			"remove it from the stack's state with `pulumi state delete` first")
+}
@@ -524,7 +524,7 @@ func printPropertyValueDiff(
 
 func isPrimitive(value resource.PropertyValue) bool {
 	return value.IsNull() || value.IsString() || value.IsNumber() ||
-		value.IsBool() || value.IsComputed() || value.IsOutput()
+		value.IsBool() || value.IsComputed() || value.IsOutput() || value.IsSecret()
 }
 
 func printPrimitivePropertyValue(b *bytes.Buffer, v resource.PropertyValue, planning bool, op deploy.StepOp) {
@@ -538,6 +538,9 @@ func printPrimitivePropertyValue(b *bytes.Buffer, v resource.PropertyValue, plan
 		write(b, op, "%v", v.NumberValue())
 	} else if v.IsString() {
 		write(b, op, "%q", v.StringValue())
+	} else if v.IsSecret() {
+		// Secrets are never displayed, whatever their underlying value.
+		writeVerbatim(b, op, "[secret]")
 	} else if v.IsComputed() || v.IsOutput() {
 		// We render computed and output values differently depending on whether or not we are
 		// planning or deploying: in the former case, we display `computed<type>` or `output<type>`;
@@ -74,10 +74,11 @@ type PreludeEventPayload struct {
 }
 
 type SummaryEventPayload struct {
-	IsPreview       bool            // true if this summary is for a plan operation
-	MaybeCorrupt    bool            // true if one or more resources may be corrupt
-	Duration        time.Duration   // the duration of the entire update operation (zero values for previews)
-	ResourceChanges ResourceChanges // count of changed resources, useful for reporting
+	IsPreview        bool                     // true if this summary is for a plan operation
+	MaybeCorrupt     bool                     // true if one or more resources may be corrupt
+	Duration         time.Duration            // the duration of the entire update operation (zero values for previews)
+	ResourceChanges  ResourceChanges          // count of changed resources, useful for reporting
+	PolicyViolations []deploy.PolicyViolation // any policy violations reported by analyzers, useful for reporting
 }
 
 type ResourceOperationFailedPayload struct {
@@ -106,6 +107,7 @@ type StepEventMetadata struct {
 	New      *StepEventStateMetadata // the state of the resource after performing this step.
 	Res      *StepEventStateMetadata // the latest state for the resource that is known (worst case, old).
 	Keys     []resource.PropertyKey  // the keys causing replacement (only for CreateStep and ReplaceStep).
+	Forced   bool                    // true if the user forced the replacement (only for CreateStep and ReplaceStep).
 	Logical  bool                    // true if this step represents a logical operation in the program.
 	Provider string                  // the provider that performed this step.
 }
@@ -147,10 +149,10 @@ func makeEventEmitter(events chan<- Event, update UpdateInfo) eventEmitter {
 			if !v.Secure() {
 				continue
 			}
-			secret, err := v.Value(target.Decrypter)
+			values, err := v.SecureValues(target.Decrypter)
 			contract.AssertNoError(err)
 
-			secrets = append(secrets, secret)
+			secrets = append(secrets, values...)
 		}
 	}
 
@@ -167,11 +169,12 @@ type eventEmitter struct {
 
 func makeStepEventMetadata(step deploy.Step, debug bool) StepEventMetadata {
 	var keys []resource.PropertyKey
+	var forced bool
 
 	if step.Op() == deploy.OpCreateReplacement {
-		keys = step.(*deploy.CreateStep).Keys()
+		keys, forced = step.(*deploy.CreateStep).Keys(), step.(*deploy.CreateStep).Forced()
 	} else if step.Op() == deploy.OpReplace {
-		keys = step.(*deploy.ReplaceStep).Keys()
+		keys, forced = step.(*deploy.ReplaceStep).Keys(), step.(*deploy.ReplaceStep).Forced()
 	}
 
 	return StepEventMetadata{
@@ -179,6 +182,7 @@ func makeStepEventMetadata(step deploy.Step, debug bool) StepEventMetadata {
 		URN:      step.URN(),
 		Type:     step.Type(),
 		Keys:     keys,
+		Forced:   forced,
 		Old:      makeStepEventStateMetadata(step.Old(), debug),
 		New:      makeStepEventStateMetadata(step.New(), debug),
 		Res:      makeStepEventStateMetadata(step.Res(), debug),
@@ -274,6 +278,12 @@ func filterPropertyMap(propertyMap resource.PropertyMap, debug bool) resource.Pr
 			return resource.Output{
 				Element: filterPropertyValue(t.Element),
 			}
+		case resource.Secret:
+			// Never send the underlying value of a secret over as part of an event. Keep the value's secretness
+			// so that the receiver can display it as such.
+			return resource.Secret{
+				Element: resource.NewStringProperty("[secret]"),
+			}
 		}
 
 		// Next, see if it's an array, slice, pointer or struct, and handle each accordingly.
@@ -380,31 +390,34 @@ func (e *eventEmitter) preludeEvent(isPreview bool, cfg config.Map) {
 	}
 }
 
-func (e *eventEmitter) previewSummaryEvent(resourceChanges ResourceChanges) {
+func (e *eventEmitter) previewSummaryEvent(resourceChanges ResourceChanges,
+	policyViolations []deploy.PolicyViolation) {
 	contract.Requiref(e != nil, "e", "!= nil")
 
 	e.Chan <- Event{
 		Type: SummaryEvent,
 		Payload: SummaryEventPayload{
-			IsPreview:       true,
-			MaybeCorrupt:    false,
-			Duration:        0,
-			ResourceChanges: resourceChanges,
+			IsPreview:        true,
+			MaybeCorrupt:     false,
+			Duration:         0,
+			ResourceChanges:  resourceChanges,
+			PolicyViolations: policyViolations,
 		},
 	}
 }
 
 func (e *eventEmitter) updateSummaryEvent(maybeCorrupt bool,
-	duration time.Duration, resourceChanges ResourceChanges) {
+	duration time.Duration, resourceChanges ResourceChanges, policyViolations []deploy.PolicyViolation) {
 	contract.Requiref(e != nil, "e", "!= nil")
 
 	e.Chan <- Event{
 		Type: SummaryEvent,
 		Payload: SummaryEventPayload{
-			IsPreview:       false,
-			MaybeCorrupt:    maybeCorrupt,
-			Duration:        duration,
-			ResourceChanges: resourceChanges,
+			IsPreview:        false,
+			MaybeCorrupt:     maybeCorrupt,
+			Duration:         duration,
+			ResourceChanges:  resourceChanges,
+			PolicyViolations: policyViolations,
 		},
 	}
 }
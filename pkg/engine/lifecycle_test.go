@@ -16,12 +16,16 @@ package engine
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/pulumi/pulumi/pkg/diag"
+	"github.com/pulumi/pulumi/pkg/operations"
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
@@ -116,6 +120,8 @@ func (j *Journal) Snap(base *deploy.Snapshot) *deploy.Snapshot {
 				ops = append(ops, resource.NewOperation(e.Step.Old(), resource.OperationTypeDeleting))
 			case deploy.OpRead, deploy.OpReadReplacement:
 				ops = append(ops, resource.NewOperation(e.Step.New(), resource.OperationTypeReading))
+			case deploy.OpImport, deploy.OpImportReplacement:
+				ops = append(ops, resource.NewOperation(e.Step.New(), resource.OperationTypeImporting))
 			case deploy.OpUpdate:
 				ops = append(ops, resource.NewOperation(e.Step.New(), resource.OperationTypeUpdating))
 			}
@@ -125,7 +131,8 @@ func (j *Journal) Snap(base *deploy.Snapshot) *deploy.Snapshot {
 
 		if e.Kind != JournalEntryOutputs {
 			switch e.Step.Op() {
-			case deploy.OpCreate, deploy.OpCreateReplacement, deploy.OpRead, deploy.OpReadReplacement, deploy.OpUpdate:
+			case deploy.OpCreate, deploy.OpCreateReplacement, deploy.OpRead, deploy.OpReadReplacement, deploy.OpUpdate,
+				deploy.OpImport, deploy.OpImportReplacement:
 				doneOps[e.Step.New()] = true
 			case deploy.OpDelete, deploy.OpDeleteReplaced:
 				doneOps[e.Step.Old()] = true
@@ -138,9 +145,12 @@ func (j *Journal) Snap(base *deploy.Snapshot) *deploy.Snapshot {
 
 		switch e.Step.Op() {
 		case deploy.OpSame, deploy.OpUpdate:
+			if same, ok := e.Step.(*deploy.SameStep); ok && same.IsSkippedCreate() {
+				continue
+			}
 			resources = append(resources, e.Step.New())
 			dones[e.Step.Old()] = true
-		case deploy.OpCreate, deploy.OpCreateReplacement:
+		case deploy.OpCreate, deploy.OpCreateReplacement, deploy.OpImport, deploy.OpImportReplacement:
 			resources = append(resources, e.Step.New())
 		case deploy.OpDelete, deploy.OpDeleteReplaced:
 			dones[e.Step.Old()] = true
@@ -215,6 +225,14 @@ type ValidateFunc func(project workspace.Project, target deploy.Target, j *Journ
 func (op TestOp) Run(project workspace.Project, target deploy.Target, opts UpdateOptions,
 	dryRun bool, validate ValidateFunc) (*deploy.Snapshot, error) {
 
+	snap, _, err := op.RunWithEvents(project, target, opts, dryRun, validate)
+	return snap, err
+}
+
+// RunWithEvents runs the operation like Run, and additionally returns the events that the operation produced.
+func (op TestOp) RunWithEvents(project workspace.Project, target deploy.Target, opts UpdateOptions,
+	dryRun bool, validate ValidateFunc) (*deploy.Snapshot, []Event, error) {
+
 	// Create an appropriate update info and context.
 	info := &updateInfo{project: project, target: target}
 
@@ -228,18 +246,24 @@ func (op TestOp) Run(project workspace.Project, target deploy.Target, opts Updat
 		SnapshotManager: journal,
 	}
 
-	// Begin draining events.
+	// Begin collecting events.
+	var collected []Event
+	eventsDone := make(chan bool)
 	go func() {
-		for range events {
+		for e := range events {
+			collected = append(collected, e)
 		}
+		close(eventsDone)
 	}()
 
 	// Run the step and its validator.
 	_, err := op(info, ctx, opts, dryRun)
 	contract.IgnoreClose(journal)
+	close(events)
+	<-eventsDone
 
 	if dryRun {
-		return nil, err
+		return nil, collected, err
 	}
 	if validate != nil {
 		err = validate(project, target, journal, err)
@@ -249,7 +273,20 @@ func (op TestOp) Run(project workspace.Project, target deploy.Target, opts Updat
 	if snap != nil {
 		err = snap.VerifyIntegrity()
 	}
-	return snap, err
+	return snap, collected, err
+}
+
+// diagMessages returns the messages of the diagnostic events of the given severity among the given events.
+func diagMessages(events []Event, severity diag.Severity) []string {
+	var messages []string
+	for _, e := range events {
+		if e.Type == DiagEvent {
+			if payload := e.Payload.(DiagEventPayload); payload.Severity == severity {
+				messages = append(messages, payload.Message)
+			}
+		}
+	}
+	return messages
 }
 
 type TestStep struct {
@@ -296,29 +333,39 @@ func (p *TestPlan) NewProviderURN(pkg tokens.Package, name string, parent resour
 	return p.NewURN(providers.MakeProviderType(pkg), name, parent)
 }
 
-func (p *TestPlan) Run(t *testing.T, snapshot *deploy.Snapshot) *deploy.Snapshot {
-	stack, projectName, runtime := p.getNames()
+func (p *TestPlan) GetProject() workspace.Project {
+	_, projectName, runtime := p.getNames()
+
+	return workspace.Project{
+		Name:        projectName,
+		RuntimeInfo: workspace.NewProjectRuntimeInfo(runtime, nil),
+	}
+}
+
+func (p *TestPlan) GetTarget(snapshot *deploy.Snapshot) deploy.Target {
+	stack, _, _ := p.getNames()
 
 	cfg := p.Config
 	if cfg == nil {
 		cfg = config.Map{}
 	}
 
-	project := &workspace.Project{
-		Name:        projectName,
-		RuntimeInfo: workspace.NewProjectRuntimeInfo(runtime, nil),
-	}
-	target := &deploy.Target{
+	return deploy.Target{
 		Name:      stack,
 		Config:    cfg,
 		Decrypter: p.Decrypter,
 		Snapshot:  snapshot,
 	}
+}
+
+func (p *TestPlan) Run(t *testing.T, snapshot *deploy.Snapshot) *deploy.Snapshot {
+	project := p.GetProject()
+	target := p.GetTarget(snapshot)
 
 	for _, step := range p.Steps {
-		_, err := step.Op.Run(*project, *target, p.Options, true, step.Validate)
+		_, err := step.Op.Run(project, target, p.Options, true, step.Validate)
 		assert.NoError(t, err)
-		target.Snapshot, err = step.Op.Run(*project, *target, p.Options, false, step.Validate)
+		target.Snapshot, err = step.Op.Run(project, target, p.Options, false, step.Validate)
 		assert.NoError(t, err)
 	}
 
@@ -1023,6 +1070,96 @@ func TestParallelRefresh(t *testing.T) {
 	assert.Equal(t, string(snap.Resources[4].URN.Name()), "resD")
 }
 
+func TestParallelDelete(t *testing.T) {
+	var m sync.Mutex
+	var active, maxActive int
+	deleted := make(map[resource.URN]bool)
+	dependents := make(map[resource.URN][]resource.URN)
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap) (resource.Status, error) {
+					m.Lock()
+					for _, dependent := range dependents[urn] {
+						assert.True(t, deleted[dependent], "%v was deleted before its dependent %v", urn, dependent)
+					}
+					active++
+					if active > maxActive {
+						maxActive = active
+					}
+					m.Unlock()
+
+					// Give any independent deletes a chance to run alongside this one.
+					time.Sleep(20 * time.Millisecond)
+
+					m.Lock()
+					active--
+					deleted[urn] = true
+					m.Unlock()
+					return resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	// Create a program that registers a diamond of four resources (resB and resC depend on resA; resD depends on
+	// resB and resC) alongside an unrelated resource, resE, and its child, resF.
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		resA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{})
+		assert.NoError(t, err)
+
+		resB, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, []resource.URN{resA}, "",
+			resource.PropertyMap{})
+		assert.NoError(t, err)
+
+		resC, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resC", true, "", false, []resource.URN{resA}, "",
+			resource.PropertyMap{})
+		assert.NoError(t, err)
+
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resD", true, "", false,
+			[]resource.URN{resB, resC}, "", resource.PropertyMap{})
+		assert.NoError(t, err)
+
+		resE, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resE", true, "", false, nil, "",
+			resource.PropertyMap{})
+		assert.NoError(t, err)
+
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resF", true, resE, false, nil, "",
+			resource.PropertyMap{})
+		assert.NoError(t, err)
+
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Parallel: 8, host: host},
+	}
+
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 7)
+
+	for _, res := range snap.Resources {
+		for _, dep := range res.Dependencies {
+			dependents[dep] = append(dependents[dep], res.URN)
+		}
+		if res.Parent != "" {
+			dependents[res.Parent] = append(dependents[res.Parent], res.URN)
+		}
+	}
+
+	// Destroy the stack. No resource may be deleted before all of its dependents are gone, but independent resources
+	// should be deleted in parallel.
+	p.Steps = []TestStep{{Op: Destroy}}
+	snap = p.Run(t, snap)
+	assert.Len(t, snap.Resources, 0)
+	assert.Len(t, deleted, 6)
+	assert.True(t, maxActive > 1, "expected independent resources to be deleted in parallel")
+}
+
 func TestExternalRefresh(t *testing.T) {
 	loaders := []*deploytest.ProviderLoader{
 		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
@@ -1157,3 +1294,808 @@ func TestRefreshInitFailure(t *testing.T) {
 		}
 	}
 }
+
+func TestTargetedUpdate(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	// Create a program that registers three resources, the second of which depends on the first. If createD is set,
+	// the program also registers a fourth resource.
+	value, createD := "foo", false
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		props := resource.NewPropertyMapFromMap(map[string]interface{}{"value": value})
+
+		resA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "", props)
+		if err != nil {
+			return err
+		}
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, []resource.URN{resA}, "",
+			props)
+		if err != nil {
+			return err
+		}
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resC", true, "", false, nil, "", props)
+		if err != nil {
+			return err
+		}
+		if createD {
+			_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resD", true, "", false, nil, "", props)
+		}
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+	}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+	resB := p.NewURN("pkgA:m:typA", "resB", "")
+	resC := p.NewURN("pkgA:m:typA", "resC", "")
+	resD := p.NewURN("pkgA:m:typA", "resD", "")
+
+	// Deploy the initial resources.
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 4)
+
+	valuesOf := func(snap *deploy.Snapshot) map[resource.URN]string {
+		values := make(map[resource.URN]string)
+		for _, res := range snap.Resources {
+			if v, ok := res.Inputs["value"]; ok {
+				values[res.URN] = v.StringValue()
+			}
+		}
+		return values
+	}
+
+	// Change every resource and add a new one, but only target resC. Only resC should be updated.
+	value, createD = "bar", true
+	p.Options.Targets = []operations.ResourceFilter{operations.ResourceFilter(resC)}
+	p.Steps = []TestStep{{
+		Op: Update,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal, err error) error {
+			for _, entry := range j.Entries {
+				switch urn := entry.Step.URN(); urn {
+				case resC:
+					assert.Equal(t, deploy.OpUpdate, entry.Step.Op())
+				case resA, resB, resD:
+					assert.Equal(t, deploy.OpSame, entry.Step.Op())
+				}
+			}
+			return err
+		},
+	}}
+	snap = p.Run(t, snap)
+	assert.Len(t, snap.Resources, 4)
+	assert.Equal(t, map[resource.URN]string{resA: "foo", resB: "foo", resC: "bar"}, valuesOf(snap))
+
+	// Targeting resB alone must fail: it depends on resA, which has pending changes that would not be applied.
+	p.Options.Targets = []operations.ResourceFilter{"resB"}
+	_, err := TestOp(Update).Run(p.GetProject(), p.GetTarget(snap), p.Options, false,
+		func(project workspace.Project, target deploy.Target, j *Journal, err error) error {
+			assert.Error(t, err)
+			return nil
+		})
+	assert.NoError(t, err)
+
+	// Targeting resA and its dependents should update both resA and resB, but still leave resD alone.
+	p.Options.Targets, p.Options.TargetDependents = []operations.ResourceFilter{"resA"}, true
+	p.Steps = []TestStep{{Op: Update}}
+	snap = p.Run(t, snap)
+	assert.Len(t, snap.Resources, 4)
+	assert.Equal(t, map[resource.URN]string{resA: "bar", resB: "bar", resC: "bar"}, valuesOf(snap))
+}
+
+func TestTargetedDestroy(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	// Create a program that registers three resources, the second of which depends on the first.
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		resA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{})
+		assert.NoError(t, err)
+
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, []resource.URN{resA}, "",
+			resource.PropertyMap{})
+		assert.NoError(t, err)
+
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resC", true, "", false, nil, "",
+			resource.PropertyMap{})
+		assert.NoError(t, err)
+
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+	}
+	provURN := p.NewProviderURN("pkgA", "default", "")
+	resB := p.NewURN("pkgA:m:typA", "resB", "")
+	resC := p.NewURN("pkgA:m:typA", "resC", "")
+
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 4)
+
+	// Destroying resA alone must fail, as resB depends on it.
+	p.Options.Targets = []operations.ResourceFilter{"resA"}
+	_, err := TestOp(Destroy).Run(p.GetProject(), p.GetTarget(snap), p.Options, false,
+		func(project workspace.Project, target deploy.Target, j *Journal, err error) error {
+			assert.Error(t, err)
+			return nil
+		})
+	assert.NoError(t, err)
+
+	// Destroying resB alone should leave everything else intact.
+	p.Options.Targets = []operations.ResourceFilter{"resB"}
+	p.Steps = []TestStep{{Op: Destroy}}
+	destroyed := p.Run(t, snap)
+	assert.Len(t, destroyed.Resources, 3)
+	for _, res := range destroyed.Resources {
+		assert.NotEqual(t, resB, res.URN)
+	}
+
+	// Destroying resA and its dependents should leave only resC and its provider.
+	p.Options.Targets, p.Options.TargetDependents = []operations.ResourceFilter{"resA"}, true
+	destroyed = p.Run(t, snap)
+	assert.Len(t, destroyed.Resources, 2)
+	for _, res := range destroyed.Resources {
+		assert.Contains(t, []resource.URN{provURN, resC}, res.URN)
+	}
+}
+
+func TestForcedReplace(t *testing.T) {
+	deleteBeforeReplace := false
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, news resource.PropertyMap) (plugin.DiffResult, error) {
+
+					return plugin.DiffResult{DeleteBeforeReplace: deleteBeforeReplace}, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, nil, "",
+			resource.PropertyMap{})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+	}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 3)
+
+	// Force the replacement of resA. The provider does not request delete-before-replace, so the replacement should
+	// be created before the original is deleted. resB should be left alone.
+	validateReplace := func(expected []deploy.StepOp) ValidateFunc {
+		return func(project workspace.Project, target deploy.Target, j *Journal, err error) error {
+			var ops []deploy.StepOp
+			for _, entry := range j.Entries {
+				if entry.Kind != JournalEntrySuccess {
+					continue
+				}
+				switch urn := entry.Step.URN(); urn {
+				case resA:
+					ops = append(ops, entry.Step.Op())
+
+					// The replacement is attributed to the user rather than to any property.
+					switch step := entry.Step.(type) {
+					case *deploy.CreateStep:
+						assert.True(t, step.Forced())
+						assert.Empty(t, step.Keys())
+					case *deploy.ReplaceStep:
+						assert.True(t, step.Forced())
+						assert.Empty(t, step.Keys())
+					}
+				default:
+					assert.Equal(t, deploy.OpSame, entry.Step.Op())
+				}
+			}
+			assert.Equal(t, expected, ops)
+			return err
+		}
+	}
+
+	//
+	// Note that we skip the previews here: previewing a replacement marks the old resource as pending deletion.
+	p.Options.ReplaceTargets = []operations.ResourceFilter{"resA"}
+	snap, err := TestOp(Update).Run(p.GetProject(), p.GetTarget(snap), p.Options, false,
+		validateReplace([]deploy.StepOp{deploy.OpCreateReplacement, deploy.OpReplace, deploy.OpDeleteReplaced}))
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 3)
+
+	// Now have the provider request delete-before-replace, which should be honored.
+	deleteBeforeReplace = true
+	snap, err = TestOp(Update).Run(p.GetProject(), p.GetTarget(snap), p.Options, false,
+		validateReplace([]deploy.StepOp{deploy.OpDeleteReplaced, deploy.OpReplace, deploy.OpCreateReplacement}))
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 3)
+}
+
+func TestImport(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, news resource.PropertyMap) (plugin.DiffResult, error) {
+
+					if olds["foo"].DeepEquals(news["foo"]) {
+						return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+					}
+					return plugin.DiffResult{Changes: plugin.DiffSome}, nil
+				},
+				CreateF: func(urn resource.URN,
+					news resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					assert.Fail(t, "imported resources must not be created")
+					return "", nil, resource.StatusOK, errors.New("unexpected create")
+				},
+				ReadF: func(urn resource.URN, id resource.ID,
+					props resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					if id != "existing-id" {
+						return nil, resource.StatusOK, nil
+					}
+					return resource.PropertyMap{
+						"foo": resource.NewStringProperty("bar"),
+						"baz": resource.NewNumberProperty(42),
+					}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	importID, inputs := resource.ID("existing-id"), resource.PropertyMap{"foo": resource.NewStringProperty("bar")}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.ImportResource("pkgA:m:typA", "resA", importID, "", nil, "", inputs)
+		return err
+	})
+	host := deploytest.NewPluginHost(nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+	}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+
+	validateOps := func(expected deploy.StepOp) ValidateFunc {
+		return func(project workspace.Project, target deploy.Target, j *Journal, err error) error {
+			for _, entry := range j.Entries {
+				if entry.Step.URN() == resA {
+					assert.Equal(t, expected, entry.Step.Op())
+				}
+			}
+			return err
+		}
+	}
+
+	// Importing the resource should read its state and record it as a managed resource.
+	p.Steps = []TestStep{{Op: Update, Validate: validateOps(deploy.OpImport)}}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 2)
+	assert.Equal(t, resA, snap.Resources[1].URN)
+	assert.Equal(t, importID, snap.Resources[1].ID)
+	assert.False(t, snap.Resources[1].External)
+	assert.Equal(t, inputs, snap.Resources[1].Inputs)
+	assert.Equal(t, resource.NewNumberProperty(42), snap.Resources[1].Outputs["baz"])
+
+	// Once imported, the resource should be left alone.
+	p.Steps = []TestStep{{Op: Update, Validate: validateOps(deploy.OpSame)}}
+	snap = p.Run(t, snap)
+	assert.Len(t, snap.Resources, 2)
+	assert.Equal(t, importID, snap.Resources[1].ID)
+
+	// Importing a different resource in place of the managed one should fail and leave the stack's state alone.
+	importID = "other-id"
+	_, events, err := TestOp(Update).RunWithEvents(p.GetProject(), p.GetTarget(snap), p.Options, false,
+		func(project workspace.Project, target deploy.Target, j *Journal, err error) error {
+			assert.Error(t, err)
+			for _, entry := range j.Entries {
+				assert.NotEqual(t, resA, entry.Step.URN())
+			}
+			return nil
+		})
+	assert.NoError(t, err)
+	if errs := diagMessages(events, diag.Error); assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0], "pulumi state delete")
+	}
+	importID = "existing-id"
+
+	// An import whose inputs do not match the existing resource should fail.
+	inputs = resource.PropertyMap{"foo": resource.NewStringProperty("qux")}
+	snap, err = TestOp(Update).Run(p.GetProject(), p.GetTarget(nil), p.Options, false,
+		func(project workspace.Project, target deploy.Target, j *Journal, err error) error {
+			assert.Error(t, err)
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 1) // provider
+
+	// As should an import of a resource that does not exist.
+	importID, inputs = "missing-id", resource.PropertyMap{"foo": resource.NewStringProperty("bar")}
+	snap, err = TestOp(Update).Run(p.GetProject(), p.GetTarget(nil), p.Options, false,
+		func(project workspace.Project, target deploy.Target, j *Journal, err error) error {
+			assert.Error(t, err)
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 1) // provider
+}
+
+func TestImportOption(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				ReadF: func(urn resource.URN, id resource.ID,
+					props resource.PropertyMap) (resource.PropertyMap, resource.Status, error) {
+
+					return resource.PropertyMap{}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, nil, "",
+			resource.PropertyMap{})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+	}
+
+	// Ask the plan to import resB. resA should be created as usual.
+	p.Options.Imports = []deploy.Import{{Type: "pkgA:m:typA", Name: "resB", ID: "existing-id"}}
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+	assert.Len(t, snap.Resources, 3)
+	assert.Equal(t, "resA", string(snap.Resources[1].URN.Name()))
+	assert.NotEqual(t, resource.ID("existing-id"), snap.Resources[1].ID)
+	assert.Equal(t, "resB", string(snap.Resources[2].URN.Name()))
+	assert.Equal(t, resource.ID("existing-id"), snap.Resources[2].ID)
+	assert.False(t, snap.Resources[2].External)
+
+	// An import that does not match any registered resource should fail.
+	p.Options.Imports = []deploy.Import{{Type: "pkgA:m:typA", Name: "resC", ID: "existing-id"}}
+	_, err := TestOp(Update).Run(p.GetProject(), p.GetTarget(nil), p.Options, false,
+		func(project workspace.Project, target deploy.Target, j *Journal, err error) error {
+			assert.Error(t, err)
+			return nil
+		})
+	assert.NoError(t, err)
+}
+
+func TestIgnoreChanges(t *testing.T) {
+	var diffNews resource.PropertyMap
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(urn resource.URN, id resource.ID,
+					olds, news resource.PropertyMap) (plugin.DiffResult, error) {
+
+					diffNews = news
+					return plugin.DiffResult{}, nil
+				},
+			}, nil
+		}),
+	}
+
+	var inputs resource.PropertyMap
+	var ignoreChanges []string
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResourceIgnoringChanges("pkgA:m:typA", "resA", true, "", false, nil, "",
+			ignoreChanges, inputs)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+	}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+
+	inputs = resource.NewPropertyMapFromMap(map[string]interface{}{
+		"a": 1,
+		"b": map[string]interface{}{"c": "foo", "d": "bar"},
+	})
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+
+	// Change every property, but ignore changes to some of them. The ignored properties should take on their old
+	// values--or be removed, if they did not previously exist--before the provider sees them.
+	inputs = resource.NewPropertyMapFromMap(map[string]interface{}{
+		"a": 2,
+		"b": map[string]interface{}{"c": "baz", "d": "qux"},
+		"e": "new",
+	})
+	ignoreChanges = []string{"a", "b.c", "e", "f.g"}
+	snap = p.Run(t, snap)
+
+	expected := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"a": 1,
+		"b": map[string]interface{}{"c": "foo", "d": "qux"},
+	})
+	assert.Equal(t, expected, diffNews)
+
+	var found bool
+	for _, res := range snap.Resources {
+		if res.URN == resA {
+			found = true
+			assert.Equal(t, expected, res.Inputs)
+			assert.Equal(t, ignoreChanges, res.IgnoreChanges)
+		}
+	}
+	assert.True(t, found)
+
+	// Ignoring changes to a property whose parent is no longer an object should fail with a diagnostic.
+	inputs = resource.NewPropertyMapFromMap(map[string]interface{}{"a": 1, "b": "flat"})
+	ignoreChanges = []string{"b.c"}
+	_, events, err := TestOp(Update).RunWithEvents(p.GetProject(), p.GetTarget(snap), p.Options, false,
+		func(project workspace.Project, target deploy.Target, j *Journal, err error) error {
+			assert.Error(t, err)
+			return nil
+		})
+	assert.NoError(t, err)
+	if errs := diagMessages(events, diag.Error); assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0], "cannot ignore changes to 'b.c'")
+	}
+}
+
+func TestRollback(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	var inputs resource.PropertyMap
+	var registerB bool
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "", inputs)
+		assert.NoError(t, err)
+		if registerB {
+			_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, "", false, nil, "",
+				resource.PropertyMap{})
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+	resB := p.NewURN("pkgA:m:typA", "resB", "")
+
+	// Create the first version of the stack, then change resA and add resB.
+	inputs = resource.NewPropertyMapFromMap(map[string]interface{}{"a": 1})
+	v1 := p.Run(t, nil)
+	inputs = resource.NewPropertyMapFromMap(map[string]interface{}{"a": 2})
+	registerB = true
+	v2 := p.Run(t, v1)
+	assert.Len(t, v2.Resources, 3)
+
+	// Rolling back to the first version should restore resA's inputs and delete resB, without running the program.
+	rollback := func(u UpdateInfo, ctx *Context, opts UpdateOptions, dryRun bool) (ResourceChanges, error) {
+		return Rollback(u, ctx, opts, v1, dryRun)
+	}
+	p.Steps = []TestStep{{
+		Op: rollback,
+		Validate: func(project workspace.Project, target deploy.Target, j *Journal, err error) error {
+			ops := make(map[resource.URN]deploy.StepOp)
+			for _, entry := range j.Entries {
+				ops[entry.Step.URN()] = entry.Step.Op()
+			}
+			assert.Equal(t, deploy.OpUpdate, ops[resA])
+			assert.Equal(t, deploy.OpDelete, ops[resB])
+			return err
+		},
+	}}
+	inputs = resource.NewPropertyMapFromMap(map[string]interface{}{"a": 3})
+	snap := p.Run(t, v2)
+
+	assert.Len(t, snap.Resources, 2)
+	for _, res := range snap.Resources {
+		assert.NotEqual(t, resB, res.URN)
+		if res.URN == resA {
+			assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{"a": 1}), res.Inputs)
+		}
+	}
+}
+
+func TestRollbackRestoresIgnoredProperties(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	var inputs resource.PropertyMap
+	var ignoreChanges []string
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResourceIgnoringChanges("pkgA:m:typA", "resA", true, "", false, nil, "",
+			ignoreChanges, inputs)
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+
+	// Create resA while ignoring changes to "a", then stop ignoring them and change "a".
+	inputs, ignoreChanges = resource.NewPropertyMapFromMap(map[string]interface{}{"a": 1}), []string{"a"}
+	v1 := p.Run(t, nil)
+	inputs, ignoreChanges = resource.NewPropertyMapFromMap(map[string]interface{}{"a": 2}), nil
+	v2 := p.Run(t, v1)
+
+	// Rolling back to the first version should restore "a", even though that version ignored changes to it.
+	p.Steps = []TestStep{{
+		Op: func(u UpdateInfo, ctx *Context, opts UpdateOptions, dryRun bool) (ResourceChanges, error) {
+			return Rollback(u, ctx, opts, v1, dryRun)
+		},
+	}}
+	snap := p.Run(t, v2)
+
+	var found bool
+	for _, res := range snap.Resources {
+		if res.URN == resA {
+			found = true
+			assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{"a": 1}), res.Inputs)
+		}
+	}
+	assert.True(t, found)
+}
+
+// analyzerHost is a plugin host that serves a fixed set of analyzers in addition to the plugins of its base host.
+type analyzerHost struct {
+	plugin.Host
+	analyzers map[tokens.QName]plugin.Analyzer
+}
+
+func (host *analyzerHost) Analyzer(nm tokens.QName) (plugin.Analyzer, error) {
+	return host.analyzers[nm], nil
+}
+
+func TestAnalyzeStack(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					inputs resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					outputs := inputs.Copy()
+					outputs["id"] = resource.NewStringProperty(string(urn.Name()))
+					return resource.ID(urn.Name()), outputs, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		resA, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{"a": resource.NewNumberProperty(1)})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, resA, false, []resource.URN{resA}, "",
+			resource.PropertyMap{"b": resource.NewNumberProperty(2)})
+		assert.NoError(t, err)
+		return nil
+	})
+
+	var analyzed []plugin.AnalyzerResource
+	var failures []plugin.AnalyzeFailure
+	analyzer := &deploytest.Analyzer{
+		Info: workspace.PluginInfo{Name: "policy"},
+		AnalyzeStackF: func(resources []plugin.AnalyzerResource) ([]plugin.AnalyzeFailure, error) {
+			analyzed = resources
+			return failures, nil
+		},
+	}
+	host := &analyzerHost{
+		Host:      deploytest.NewPluginHost(nil, program, loaders...),
+		analyzers: map[tokens.QName]plugin.Analyzer{"policy": analyzer},
+	}
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host, Analyzers: []string{"policy"}},
+		Steps:   []TestStep{{Op: Update}},
+	}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+	resB := p.NewURN("pkgA:m:typA", "resB", resA)
+
+	// The analyzer should see every resource--including the default provider--in registration order, along with
+	// their final outputs.
+	snap := p.Run(t, nil)
+	if assert.Len(t, analyzed, 3) {
+		assert.Equal(t, providers.MakeProviderType("pkgA"), analyzed[0].Type)
+
+		assert.Equal(t, resA, analyzed[1].URN)
+		assert.Equal(t, resource.NewStringProperty("resA"), analyzed[1].Properties["id"])
+
+		assert.Equal(t, resB, analyzed[2].URN)
+		assert.Equal(t, resA, analyzed[2].Parent)
+		assert.Equal(t, []resource.URN{resA}, analyzed[2].Dependencies)
+		assert.NotEmpty(t, analyzed[2].Provider)
+	}
+
+	// Any failure reported by the analyzer should fail the preview.
+	failures = []plugin.AnalyzeFailure{{URN: resB, Property: "b", Reason: "b must not be 2"}}
+	_, err := TestOp(Update).Run(p.GetProject(), p.GetTarget(snap), p.Options, true, nil)
+	assert.Error(t, err)
+}
+
+func TestAnalyzerEnforcementLevels(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{})
+		return err
+	})
+
+	var level workspace.EnforcementLevel
+	analyzer := &deploytest.Analyzer{
+		Info: workspace.PluginInfo{Name: "policy"},
+		AnalyzeF: func(t tokens.Type, props resource.PropertyMap) ([]plugin.AnalyzeFailure, error) {
+			if t != "pkgA:m:typA" {
+				return nil, nil
+			}
+			return []plugin.AnalyzeFailure{{
+				Reason:           "resA violates the policy",
+				PolicyName:       "no-typA",
+				Severity:         "high",
+				EnforcementLevel: level,
+			}}, nil
+		},
+	}
+	host := &analyzerHost{
+		Host:      deploytest.NewPluginHost(nil, program, loaders...),
+		analyzers: map[tokens.QName]plugin.Analyzer{"policy": analyzer},
+	}
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host, Analyzers: []string{"policy"}},
+	}
+	project, target := p.GetProject(), p.GetTarget(nil)
+
+	// Mandatory violations--including those whose level was not given--fail the preview.
+	for _, level = range []workspace.EnforcementLevel{"", workspace.Mandatory} {
+		_, err := TestOp(Update).Run(project, target, p.Options, true, nil)
+		assert.Error(t, err)
+	}
+
+	// Advisory violations and violations of disabled policies do not.
+	for _, level = range []workspace.EnforcementLevel{workspace.Advisory, workspace.Disabled} {
+		_, err := TestOp(Update).Run(project, target, p.Options, true, nil)
+		assert.NoError(t, err)
+	}
+
+	// The project's policy settings override the level that the analyzer asked for.
+	level = workspace.Mandatory
+	project.Policy = &workspace.ProjectPolicy{
+		Rules: map[string]workspace.EnforcementLevel{"no-typA": workspace.Advisory},
+	}
+	_, err := TestOp(Update).Run(project, target, p.Options, true, nil)
+	assert.NoError(t, err)
+
+	level = workspace.Advisory
+	project.Policy.Rules["no-typA"] = workspace.Mandatory
+	_, err = TestOp(Update).Run(project, target, p.Options, true, nil)
+	assert.Error(t, err)
+
+	// An advisory violation is reported as a warning, and the update goes ahead.
+	project.Policy = nil
+	snap, events, err := TestOp(Update).RunWithEvents(project, target, p.Options, false, nil)
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 2)
+	assert.Empty(t, diagMessages(events, diag.Error))
+	if warnings := diagMessages(events, diag.Warning); assert.Len(t, warnings, 1) {
+		assert.Contains(t, warnings[0], "no-typA (high severity): resA violates the policy")
+	}
+}
+
+func TestSecretsAreMaskedInEvents(t *testing.T) {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN,
+					news resource.PropertyMap) (resource.ID, resource.PropertyMap, resource.Status, error) {
+
+					return "created-id", resource.PropertyMap{
+						"password": news["password"],
+						"token":    resource.MakeSecret(resource.NewStringProperty("output-secret")),
+					}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, "", false, nil, "",
+			resource.PropertyMap{"password": resource.MakeSecret(resource.NewStringProperty("input-secret"))})
+		assert.NoError(t, err)
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{host: host},
+	}
+	resA := p.NewURN("pkgA:m:typA", "resA", "")
+
+	events := make(chan Event)
+	var collected []Event
+	eventsDone := make(chan bool)
+	go func() {
+		for e := range events {
+			collected = append(collected, e)
+		}
+		close(eventsDone)
+	}()
+
+	cancelCtx, _ := cancel.NewContext(context.Background())
+	journal := newJournal()
+	info := &updateInfo{project: p.GetProject(), target: p.GetTarget(nil)}
+	_, err := Update(info, &Context{Cancel: cancelCtx, Events: events, SnapshotManager: journal}, p.Options, false)
+	assert.NoError(t, err)
+	contract.IgnoreClose(journal)
+	close(events)
+	<-eventsDone
+
+	// The resource's state should retain the secrets...
+	snap := journal.Snap(nil)
+	assert.Len(t, snap.Resources, 2)
+	assert.Equal(t, resource.MakeSecret(resource.NewStringProperty("output-secret")),
+		snap.Resources[1].Outputs["token"])
+
+	// ...but the events describing it must only carry masked secrets.
+	masked := resource.MakeSecret(resource.NewStringProperty("[secret]"))
+	sawOutputs := false
+	for _, e := range collected {
+		payload, ok := e.Payload.(ResourceOutputsEventPayload)
+		if !ok || payload.Metadata.URN != resA {
+			continue
+		}
+		sawOutputs = true
+		assert.Equal(t, masked, payload.Metadata.New.Inputs["password"])
+		assert.Equal(t, masked, payload.Metadata.New.Outputs["password"])
+		assert.Equal(t, masked, payload.Metadata.New.Outputs["token"])
+	}
+	assert.True(t, sawOutputs)
+}
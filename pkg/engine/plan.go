@@ -196,8 +196,13 @@ func (res *planResult) Chdir() (func(), error) {
 func (res *planResult) Walk(cancelCtx *Context, events deploy.Events, preview bool) (deploy.PlanSummary, error) {
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	opts := deploy.Options{
-		Events:   events,
-		Parallel: res.Options.Parallel,
+		Events:           events,
+		Parallel:         res.Options.Parallel,
+		Targets:          makeTargetFilter(res.Options.Targets),
+		TargetDependents: res.Options.TargetDependents,
+		ReplaceTargets:   makeTargetFilter(res.Options.ReplaceTargets),
+		Imports:          res.Options.Imports,
+		Policy:           res.Ctx.Update.GetProject().Policy,
 	}
 
 	src, err := res.Plan.Source().Iterate(ctx, opts, res.Plan)
@@ -250,14 +255,14 @@ func printPlan(ctx *Context, result *planResult, dryRun bool) (ResourceChanges,
 
 	// Walk the plan's steps and and pretty-print them out.
 	actions := newPlanActions(result.Options)
-	_, err := result.Walk(ctx, actions, true)
+	summary, err := result.Walk(ctx, actions, true)
 	if err != nil {
 		return nil, errors.New("an error occurred while advancing the preview")
 	}
 
-	// Emit an event with a summary of operation counts.
+	// Emit an event with a summary of operation counts and any policy violations.
 	changes := ResourceChanges(actions.Ops)
-	result.Options.Events.previewSummaryEvent(changes)
+	result.Options.Events.previewSummaryEvent(changes, summary.PolicyViolations())
 	return changes, nil
 }
 
@@ -289,7 +294,7 @@ func (acts *planActions) OnResourceStepPre(step deploy.Step) (interface{}, error
 	}
 
 	// Warn the user if they're not updating a resource whose initialization failed.
-	if step.Op() == deploy.OpSame && len(step.Old().InitErrors) > 0 {
+	if step.Op() == deploy.OpSame && step.Old() != nil && len(step.Old().InitErrors) > 0 {
 		indent := "         "
 
 		// TODO: Move indentation to the display logic, instead of doing it ourselves.
@@ -0,0 +1,65 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// Rollback returns a stack to the state recorded in a previous snapshot. Rather than running the stack's program, the
+// resources in the snapshot are registered with the inputs, providers, and options that they had at the time, so that
+// the plan computes the creates, updates, and deletes necessary to return to it.
+func Rollback(u UpdateInfo, ctx *Context, opts UpdateOptions, snap *deploy.Snapshot,
+	dryRun bool) (ResourceChanges, error) {
+
+	contract.Require(u != nil, "u")
+	contract.Require(ctx != nil, "ctx")
+
+	defer func() { ctx.Events <- cancelEvent() }()
+
+	info, err := newPlanContext(u, "rollback", ctx.ParentSpan)
+	if err != nil {
+		return nil, err
+	}
+	defer info.Close()
+
+	emitter := makeEventEmitter(ctx.Events, u)
+	return update(ctx, info, planOptions{
+		UpdateOptions: opts,
+		SourceFunc:    newRollbackSourceFunc(snap),
+		Events:        emitter,
+		Diag:          newEventSink(emitter),
+	}, dryRun)
+}
+
+func newRollbackSourceFunc(snap *deploy.Snapshot) planSourceFunc {
+	return func(opts planOptions, proj *workspace.Project, pwd, main string,
+		target *deploy.Target, plugctx *plugin.Context, dryRun bool) (deploy.Source, error) {
+
+		// As with refresh, no program is run, so we only need the plugins recorded in the snapshot we are returning
+		// to, and not the language plugin. Resource plugins are loaded on demand by the provider registry.
+		if snap != nil {
+			kinds := plugin.AnalyzerPlugins
+			if err := plugctx.Host.EnsurePlugins(snap.Manifest.Plugins, kinds); err != nil {
+				return nil, err
+			}
+		}
+
+		return deploy.NewRollbackSource(proj.Name, snap), nil
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/diag/colors"
+	"github.com/pulumi/pulumi/pkg/operations"
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/resource/plugin"
@@ -44,6 +45,18 @@ type UpdateOptions struct {
 	// true if debugging output it enabled
 	Debug bool
 
+	// an optional set of filters that restrict this deployment to the resources they match.
+	Targets []operations.ResourceFilter
+
+	// true if resources that depend on targeted resources should also be targeted.
+	TargetDependents bool
+
+	// an optional set of filters that select resources that must be replaced, regardless of their diffs.
+	ReplaceTargets []operations.ResourceFilter
+
+	// an optional set of existing resources to import into the stack.
+	Imports []deploy.Import
+
 	// true if we should report events for steps that involve default providers.
 	reportDefaultProviderSteps bool
 
@@ -51,6 +64,22 @@ type UpdateOptions struct {
 	host plugin.Host
 }
 
+// makeTargetFilter returns a deploy.TargetFilter that matches any resource matched by one of the given filters, or nil
+// if no filters were specified.
+func makeTargetFilter(filters []operations.ResourceFilter) deploy.TargetFilter {
+	if len(filters) == 0 {
+		return nil
+	}
+	return func(urn resource.URN) bool {
+		for _, filter := range filters {
+			if filter.Matches(urn) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 // ResourceChanges contains the aggregate resource changes by operation type.
 type ResourceChanges map[deploy.StepOp]int
 
@@ -164,7 +193,8 @@ func update(ctx *Context, info *planContext, opts planOptions, dryRun bool) (Res
 			contract.Assert(summary != nil)
 			// Print out the total number of steps performed (and their kinds), the duration, and any summary info.
 			resourceChanges = ResourceChanges(actions.Ops)
-			opts.Events.updateSummaryEvent(actions.MaybeCorrupt, time.Since(start), resourceChanges)
+			opts.Events.updateSummaryEvent(actions.MaybeCorrupt, time.Since(start), resourceChanges,
+				summary.PolicyViolations())
 
 			if err != nil {
 				return resourceChanges, err
@@ -218,7 +248,7 @@ func (acts *updateActions) OnResourceStepPre(step deploy.Step) (interface{}, err
 		acts.Opts.Events.resourcePreEvent(step, false /*planning*/, acts.Opts.Debug)
 
 		// Warn the user if they're not updating a resource whose initialization failed.
-		if step.Op() == deploy.OpSame && len(step.Old().InitErrors) > 0 {
+		if step.Op() == deploy.OpSame && step.Old() != nil && len(step.Old().InitErrors) > 0 {
 			indent := "         "
 
 			// TODO: Move indentation to the display logic, instead of doing it ourselves.
@@ -16,6 +16,9 @@ package operations
 
 import (
 	"time"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
 )
 
 // LogEntry is a row in the logs for a running compute service
@@ -31,6 +34,23 @@ type LogEntry struct {
 // - Name: "<name>"
 type ResourceFilter string
 
+// Matches determines whether the resource with the given URN matches this filter.
+func (filter ResourceFilter) Matches(urn resource.URN) bool {
+	if resource.URN(filter) == urn {
+		// The filter matched the full URN
+		return true
+	}
+	if string(filter) == string(urn.Type())+"::"+string(urn.Name()) {
+		// The filter matched the '<type>::<name>' part of the URN
+		return true
+	}
+	if tokens.QName(filter) == urn.Name() {
+		// The filter matched the '<name>' part of the URN
+		return true
+	}
+	return false
+}
+
 // LogQuery represents the parameters to a log query operation. All fields are
 // optional, leaving them off returns all logs.
 //
@@ -32,7 +32,7 @@ func getPulumiResources(t *testing.T, path string) *Resource {
 	assert.NoError(t, err)
 	err = json.Unmarshal(byts, &checkpoint)
 	assert.NoError(t, err)
-	snapshot, err := stack.DeserializeCheckpoint(&checkpoint)
+	snapshot, err := stack.DeserializeCheckpoint(&checkpoint, nil)
 	assert.NoError(t, err)
 	resources := NewResourceTree(snapshot.Resources)
 	spew.Dump(resources)
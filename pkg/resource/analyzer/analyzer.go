@@ -0,0 +1,196 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package analyzer makes it easy to write analyzer plugins in Go. An analyzer is built up from a set of rules, each of
+// which enforces a single policy, and may then be served to the engine using Main or run directly against existing
+// state using RunSnapshot and RunDeployment.
+package analyzer
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// Policy describes a single policy enforced by an analyzer.
+type Policy struct {
+	Name             string                     // the unique name of the policy.
+	Description      string                     // a human-readable description of the policy.
+	Severity         string                     // the severity of violations of the policy (e.g. "low" or "high").
+	EnforcementLevel workspace.EnforcementLevel // how violations of the policy are enforced (mandatory if empty).
+}
+
+// Violation is a single violation of a policy reported by a rule.
+type Violation struct {
+	URN      resource.URN         // the resource in violation; may be empty for resource rules.
+	Property resource.PropertyKey // the property in violation, if any.
+	Reason   string               // a human-readable reason for the violation.
+}
+
+// ResourceRule checks a single resource, returning any policy violations that it finds.
+type ResourceRule func(res *Resource) ([]Violation, error)
+
+// StackRule checks all of the resources in a stack at once, returning any policy violations that it finds. The
+// resources are given in dependency order.
+type StackRule func(resources []*Resource) ([]Violation, error)
+
+// resourceRule is a resource rule that has been registered with an analyzer.
+type resourceRule struct {
+	policy Policy
+	check  ResourceRule
+}
+
+// stackRule is a stack rule that has been registered with an analyzer.
+type stackRule struct {
+	policy Policy
+	check  StackRule
+}
+
+// Analyzer is a collection of rules that together implement an analyzer plugin.
+type Analyzer struct {
+	Name    string // the name of the analyzer.
+	Version string // the version of the analyzer, if any.
+
+	policies      map[string]bool                // the names of all registered policies.
+	resourceRules map[tokens.Type][]resourceRule // resource rules, keyed by the type they apply to.
+	stackRules    []stackRule                    // stack rules, in the order they were registered.
+}
+
+// New creates a new analyzer with the given name and no rules.
+func New(name string) *Analyzer {
+	contract.Require(name != "", "name")
+	return &Analyzer{
+		Name:          name,
+		policies:      make(map[string]bool),
+		resourceRules: make(map[tokens.Type][]resourceRule),
+	}
+}
+
+// AnyType may be passed to AddResourceRule to register a rule that applies to resources of every type.
+const AnyType tokens.Type = ""
+
+// AddResourceRule registers a rule enforcing the given policy that is run against every resource of type t, or
+// against every resource if t is AnyType. Each policy may only be registered once.
+func (a *Analyzer) AddResourceRule(t tokens.Type, policy Policy, check ResourceRule) error {
+	if err := a.addPolicy(policy); err != nil {
+		return err
+	}
+	contract.Require(check != nil, "check")
+	a.resourceRules[t] = append(a.resourceRules[t], resourceRule{policy: policy, check: check})
+	return nil
+}
+
+// AddStackRule registers a rule enforcing the given policy that is run against all of the resources in a stack at
+// once. Each policy may only be registered once.
+func (a *Analyzer) AddStackRule(policy Policy, check StackRule) error {
+	if err := a.addPolicy(policy); err != nil {
+		return err
+	}
+	contract.Require(check != nil, "check")
+	a.stackRules = append(a.stackRules, stackRule{policy: policy, check: check})
+	return nil
+}
+
+// addPolicy validates the given policy and records that it has been registered.
+func (a *Analyzer) addPolicy(policy Policy) error {
+	if policy.Name == "" {
+		return errors.New("policies must have a name")
+	}
+	if policy.EnforcementLevel != "" && !policy.EnforcementLevel.IsValid() {
+		return errors.Errorf("policy '%s' has an invalid enforcement level '%s'", policy.Name, policy.EnforcementLevel)
+	}
+	if a.policies[policy.Name] {
+		return errors.Errorf("policy '%s' has already been registered", policy.Name)
+	}
+	a.policies[policy.Name] = true
+	return nil
+}
+
+// AnalyzeResource runs all of the resource rules that apply to the given resource and returns any failures.
+func (a *Analyzer) AnalyzeResource(res *Resource) ([]plugin.AnalyzeFailure, error) {
+	var rules []resourceRule
+	rules = append(rules, a.resourceRules[res.Type]...)
+	rules = append(rules, a.resourceRules[AnyType]...)
+
+	var failures []plugin.AnalyzeFailure
+	for _, rule := range rules {
+		violations, err := rule.check(res)
+		if err != nil {
+			return nil, errors.Wrapf(err, "policy '%s' failed", rule.policy.Name)
+		}
+		for _, v := range violations {
+			if v.URN == "" {
+				v.URN = res.URN
+			}
+			failures = append(failures, newFailure(rule.policy, v))
+		}
+	}
+	return failures, nil
+}
+
+// AnalyzeStack runs all of the stack rules against the given resources and returns any failures. Note that resource
+// rules are not run; the engine runs those as each resource is registered.
+func (a *Analyzer) AnalyzeStack(resources []*Resource) ([]plugin.AnalyzeFailure, error) {
+	var failures []plugin.AnalyzeFailure
+	for _, rule := range a.stackRules {
+		violations, err := rule.check(resources)
+		if err != nil {
+			return nil, errors.Wrapf(err, "policy '%s' failed", rule.policy.Name)
+		}
+		for _, v := range violations {
+			failures = append(failures, newFailure(rule.policy, v))
+		}
+	}
+	return failures, nil
+}
+
+// Run runs all of the resource rules against each of the given resources, followed by all of the stack rules against
+// the resources as a whole, and returns any failures. This is equivalent to what the engine does over the course of
+// an update.
+func (a *Analyzer) Run(resources []*Resource) ([]plugin.AnalyzeFailure, error) {
+	var failures []plugin.AnalyzeFailure
+	for _, res := range resources {
+		resFailures, err := a.AnalyzeResource(res)
+		if err != nil {
+			return nil, err
+		}
+		failures = append(failures, resFailures...)
+	}
+	stackFailures, err := a.AnalyzeStack(resources)
+	if err != nil {
+		return nil, err
+	}
+	return append(failures, stackFailures...), nil
+}
+
+// newFailure creates the failure that reports the given violation of a policy.
+func newFailure(policy Policy, v Violation) plugin.AnalyzeFailure {
+	level := policy.EnforcementLevel
+	if level == "" {
+		level = workspace.Mandatory
+	}
+	return plugin.AnalyzeFailure{
+		URN:              v.URN,
+		Property:         v.Property,
+		Reason:           v.Reason,
+		PolicyName:       policy.Name,
+		Description:      policy.Description,
+		Severity:         policy.Severity,
+		EnforcementLevel: level,
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/workspace"
+	pulumirpc "github.com/pulumi/pulumi/sdk/proto/go"
+)
+
+const bucketType = tokens.Type("aws:s3/bucket:Bucket")
+
+// newTestAnalyzer creates an analyzer with a resource rule that forbids public buckets and a stack rule that requires
+// every bucket to have an owner tag.
+func newTestAnalyzer(t *testing.T) *Analyzer {
+	a := New("test")
+	a.Version = "1.0.0"
+
+	err := a.AddResourceRule(bucketType, Policy{
+		Name:        "no-public-buckets",
+		Description: "Buckets must not be publicly readable.",
+		Severity:    "high",
+	}, func(res *Resource) ([]Violation, error) {
+		if acl, ok := res.GetString("acl"); ok && acl == "public-read" {
+			return []Violation{{Property: "acl", Reason: "bucket is publicly readable"}}, nil
+		}
+		return nil, nil
+	})
+	assert.NoError(t, err)
+
+	err = a.AddStackRule(Policy{
+		Name:             "owned-buckets",
+		EnforcementLevel: workspace.Advisory,
+	}, func(resources []*Resource) ([]Violation, error) {
+		var violations []Violation
+		for _, res := range resources {
+			if res.Type == bucketType && !res.Has("tags.Owner") {
+				violations = append(violations, Violation{URN: res.URN, Property: "tags", Reason: "bucket has no owner"})
+			}
+		}
+		return violations, nil
+	})
+	assert.NoError(t, err)
+
+	return a
+}
+
+func TestAddRule(t *testing.T) {
+	a := New("test")
+	check := func(res *Resource) ([]Violation, error) { return nil, nil }
+
+	assert.Error(t, a.AddResourceRule(AnyType, Policy{}, check))
+	assert.Error(t, a.AddResourceRule(AnyType, Policy{Name: "p", EnforcementLevel: "sometimes"}, check))
+	assert.NoError(t, a.AddResourceRule(AnyType, Policy{Name: "p"}, check))
+	assert.Error(t, a.AddResourceRule(bucketType, Policy{Name: "p"}, check))
+	assert.Error(t, a.AddStackRule(Policy{Name: "p"}, func([]*Resource) ([]Violation, error) { return nil, nil }))
+}
+
+func TestResourceAccessors(t *testing.T) {
+	res := &Resource{Properties: resource.PropertyMap{
+		"name":    resource.NewStringProperty("bucket"),
+		"count":   resource.NewNumberProperty(3),
+		"enabled": resource.NewBoolProperty(true),
+		"tags": resource.NewObjectProperty(resource.PropertyMap{
+			"Owner": resource.MakeSecret(resource.NewStringProperty("ops")),
+		}),
+		"rules": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"cidr": resource.NewStringProperty("0.0.0.0/0"),
+			}),
+		}),
+		"secret": resource.MakeSecret(resource.NewObjectProperty(resource.PropertyMap{
+			"key": resource.NewStringProperty("value"),
+		})),
+		"unknown": resource.MakeComputed(resource.NewStringProperty("")),
+	}}
+
+	s, ok := res.GetString("name")
+	assert.True(t, ok)
+	assert.Equal(t, "bucket", s)
+	_, ok = res.GetString("count")
+	assert.False(t, ok)
+
+	n, ok := res.GetNumber("count")
+	assert.True(t, ok)
+	assert.Equal(t, float64(3), n)
+
+	b, ok := res.GetBool("enabled")
+	assert.True(t, ok)
+	assert.True(t, b)
+
+	s, ok = res.GetString("tags.Owner")
+	assert.True(t, ok)
+	assert.Equal(t, "ops", s)
+
+	s, ok = res.GetString("rules[0].cidr")
+	assert.True(t, ok)
+	assert.Equal(t, "0.0.0.0/0", s)
+	_, ok = res.GetString("rules[1].cidr")
+	assert.False(t, ok)
+
+	s, ok = res.GetString("secret.key")
+	assert.True(t, ok)
+	assert.Equal(t, "value", s)
+
+	arr, ok := res.GetArray("rules")
+	assert.True(t, ok)
+	assert.Len(t, arr, 1)
+
+	obj, ok := res.GetObject("tags")
+	assert.True(t, ok)
+	assert.Len(t, obj, 1)
+
+	assert.True(t, res.Has("tags"))
+	assert.False(t, res.Has("unknown"))
+	assert.False(t, res.Has("missing"))
+}
+
+func TestRunSnapshot(t *testing.T) {
+	a := newTestAnalyzer(t)
+
+	stackURN := resource.NewURN("dev", "test", "", "pulumi:pulumi:Stack", "test-dev")
+	publicURN := resource.NewURN("dev", "test", "", bucketType, "public")
+	privateURN := resource.NewURN("dev", "test", "", bucketType, "private")
+	snap := deploy.NewSnapshot(deploy.Manifest{}, []*resource.State{
+		resource.NewState("pulumi:pulumi:Stack", stackURN, false, false, "", resource.PropertyMap{}, nil, "",
+			false, false, nil, nil, "", nil),
+		resource.NewState(bucketType, publicURN, true, false, "public-id", resource.PropertyMap{}, resource.PropertyMap{
+			"acl": resource.NewStringProperty("public-read"),
+		}, stackURN, false, false, nil, nil, "", nil),
+		resource.NewState(bucketType, privateURN, true, false, "", resource.PropertyMap{
+			"acl": resource.NewStringProperty("public-read"),
+		}, nil, stackURN, false, false, nil, nil, "", nil),
+		// Resources that are pending deletion are ignored.
+		resource.NewState(bucketType, privateURN, true, true, "old-id", resource.PropertyMap{}, resource.PropertyMap{
+			"acl": resource.NewStringProperty("public-read"),
+		}, stackURN, false, false, nil, nil, "", nil),
+	}, nil)
+
+	failures, err := a.RunSnapshot(snap)
+	assert.NoError(t, err)
+	assert.Equal(t, []plugin.AnalyzeFailure{
+		{
+			URN:              publicURN,
+			Property:         "acl",
+			Reason:           "bucket is publicly readable",
+			PolicyName:       "no-public-buckets",
+			Description:      "Buckets must not be publicly readable.",
+			Severity:         "high",
+			EnforcementLevel: workspace.Mandatory,
+		},
+		{
+			URN:              privateURN,
+			Property:         "acl",
+			Reason:           "bucket is publicly readable",
+			PolicyName:       "no-public-buckets",
+			Description:      "Buckets must not be publicly readable.",
+			Severity:         "high",
+			EnforcementLevel: workspace.Mandatory,
+		},
+		{
+			URN:              publicURN,
+			Property:         "tags",
+			Reason:           "bucket has no owner",
+			PolicyName:       "owned-buckets",
+			EnforcementLevel: workspace.Advisory,
+		},
+		{
+			URN:              privateURN,
+			Property:         "tags",
+			Reason:           "bucket has no owner",
+			PolicyName:       "owned-buckets",
+			EnforcementLevel: workspace.Advisory,
+		},
+	}, failures)
+}
+
+func TestRunDeploymentFile(t *testing.T) {
+	a := newTestAnalyzer(t)
+
+	failures, err := a.RunDeploymentFile("testdata/deployment.json")
+	assert.NoError(t, err)
+	if assert.Len(t, failures, 2) {
+		publicURN := resource.URN("urn:pulumi:dev::test::pulumi:pulumi:Stack$aws:s3/bucket:Bucket::public")
+		privateURN := resource.URN("urn:pulumi:dev::test::pulumi:pulumi:Stack$aws:s3/bucket:Bucket::private")
+		assert.Equal(t, publicURN, failures[0].URN)
+		assert.Equal(t, "no-public-buckets", failures[0].PolicyName)
+		assert.Equal(t, privateURN, failures[1].URN)
+		assert.Equal(t, "owned-buckets", failures[1].PolicyName)
+	}
+
+	_, err = a.RunDeploymentFile("testdata/missing.json")
+	assert.Error(t, err)
+}
+
+func TestServer(t *testing.T) {
+	srv := NewServer(newTestAnalyzer(t))
+
+	info, err := srv.GetPluginInfo(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", info.Version)
+
+	props, err := plugin.MarshalProperties(resource.PropertyMap{
+		"acl": resource.NewStringProperty("public-read"),
+	}, plugin.MarshalOptions{})
+	assert.NoError(t, err)
+
+	// Analyze only runs resource rules.
+	resp, err := srv.Analyze(context.Background(), &pulumirpc.AnalyzeRequest{
+		Type:       string(bucketType),
+		Properties: props,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, resp.Failures, 1) {
+		assert.Equal(t, "no-public-buckets", resp.Failures[0].PolicyName)
+		assert.Equal(t, "acl", resp.Failures[0].Property)
+		assert.Equal(t, pulumirpc.EnforcementLevel_MANDATORY, resp.Failures[0].EnforcementLevel)
+	}
+
+	// AnalyzeStack only runs stack rules.
+	urn := resource.NewURN("dev", "test", "", bucketType, "public")
+	resp, err = srv.AnalyzeStack(context.Background(), &pulumirpc.AnalyzeStackRequest{
+		Resources: []*pulumirpc.AnalyzerResource{{
+			Urn:        string(urn),
+			Type:       string(bucketType),
+			Properties: props,
+		}},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, resp.Failures, 1) {
+		assert.Equal(t, "owned-buckets", resp.Failures[0].PolicyName)
+		assert.Equal(t, string(urn), resp.Failures[0].Urn)
+		assert.Equal(t, pulumirpc.EnforcementLevel_ADVISORY, resp.Failures[0].EnforcementLevel)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/resource/stack"
+)
+
+// SnapshotResources returns the resources in the given snapshot in the form that they are presented to rules. The
+// resources are converted just as they are for analyzers run by the engine; see deploy.AnalyzerResources.
+func SnapshotResources(snap *deploy.Snapshot) []*Resource {
+	if snap == nil {
+		return nil
+	}
+
+	var resources []*Resource
+	for _, res := range deploy.AnalyzerResources(snap.Resources) {
+		resources = append(resources, &Resource{
+			URN:          res.URN,
+			Type:         res.Type,
+			Properties:   res.Properties,
+			Parent:       res.Parent,
+			Dependencies: res.Dependencies,
+			Provider:     res.Provider,
+		})
+	}
+	return resources
+}
+
+// RunSnapshot runs all of the analyzer's rules against the resources in the given snapshot, without the need for an
+// engine, and returns any failures.
+func (a *Analyzer) RunSnapshot(snap *deploy.Snapshot) ([]plugin.AnalyzeFailure, error) {
+	return a.Run(SnapshotResources(snap))
+}
+
+// RunDeployment runs all of the analyzer's rules against the resources in the given deployment, as produced by
+// `pulumi stack export`, and returns any failures. Secret values in the deployment are masked.
+func (a *Analyzer) RunDeployment(deployment *apitype.UntypedDeployment) ([]plugin.AnalyzeFailure, error) {
+	snap, err := stack.DeserializeUntypedDeployment(deployment, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not deserialize deployment")
+	}
+	return a.RunSnapshot(snap)
+}
+
+// RunDeploymentFile runs all of the analyzer's rules against the resources in the deployment stored in the given file,
+// as produced by `pulumi stack export --file`, and returns any failures.
+func (a *Analyzer) RunDeploymentFile(path string) ([]plugin.AnalyzeFailure, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read deployment file")
+	}
+
+	var deployment apitype.UntypedDeployment
+	if err = json.Unmarshal(b, &deployment); err != nil {
+		return nil, errors.Wrap(err, "could not read deployment file")
+	}
+	return a.RunDeployment(&deployment)
+}
@@ -0,0 +1,103 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// Resource is a single resource being analyzed.
+type Resource struct {
+	URN          resource.URN         // the resource's URN; may be empty if it is not yet known.
+	Type         tokens.Type          // the resource's type token.
+	Properties   resource.PropertyMap // the resource's properties.
+	Parent       resource.URN         // the resource's parent, if any.
+	Dependencies []resource.URN       // the resources that this resource depends on, if known.
+	Provider     string               // the provider reference for this resource, if known.
+}
+
+// Get returns the value located by the given property path (e.g. `tags.Name` or `ingress[0].cidrBlocks`), or false if
+// there is no such value. Secret values, including any along the path, are unwrapped. It panics if the path is
+// malformed.
+func (r *Resource) Get(path string) (resource.PropertyValue, bool) {
+	p, err := resource.ParsePropertyPath(path)
+	contract.Assertf(err == nil, "invalid property path '%s': %v", path, err)
+
+	v := resource.NewObjectProperty(r.Properties)
+	for _, key := range p {
+		elem, ok := resource.PropertyPath([]interface{}{key}).Get(unwrapSecret(v))
+		if !ok {
+			return resource.PropertyValue{}, false
+		}
+		v = elem
+	}
+	return unwrapSecret(v), true
+}
+
+// unwrapSecret returns the underlying value of the given value if it is a secret, or the value itself otherwise.
+func unwrapSecret(v resource.PropertyValue) resource.PropertyValue {
+	for v.IsSecret() {
+		v = v.SecretValue().Element
+	}
+	return v
+}
+
+// Has returns true if the given property path locates a value that is neither null nor unknown.
+func (r *Resource) Has(path string) bool {
+	v, ok := r.Get(path)
+	return ok && v.HasValue() && !v.IsComputed()
+}
+
+// GetString returns the string located by the given property path, or false if there is no such string.
+func (r *Resource) GetString(path string) (string, bool) {
+	if v, ok := r.Get(path); ok && v.IsString() {
+		return v.StringValue(), true
+	}
+	return "", false
+}
+
+// GetBool returns the bool located by the given property path, or false if there is no such bool.
+func (r *Resource) GetBool(path string) (bool, bool) {
+	if v, ok := r.Get(path); ok && v.IsBool() {
+		return v.BoolValue(), true
+	}
+	return false, false
+}
+
+// GetNumber returns the number located by the given property path, or false if there is no such number.
+func (r *Resource) GetNumber(path string) (float64, bool) {
+	if v, ok := r.Get(path); ok && v.IsNumber() {
+		return v.NumberValue(), true
+	}
+	return 0, false
+}
+
+// GetArray returns the array located by the given property path, or false if there is no such array.
+func (r *Resource) GetArray(path string) ([]resource.PropertyValue, bool) {
+	if v, ok := r.Get(path); ok && v.IsArray() {
+		return v.ArrayValue(), true
+	}
+	return nil, false
+}
+
+// GetObject returns the object located by the given property path, or false if there is no such object.
+func (r *Resource) GetObject(path string) (resource.PropertyMap, bool) {
+	if v, ok := r.Get(path); ok && v.IsObject() {
+		return v.ObjectValue(), true
+	}
+	return nil, false
+}
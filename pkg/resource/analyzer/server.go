@@ -0,0 +1,133 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	pbstruct "github.com/golang/protobuf/ptypes/struct"
+	"golang.org/x/net/context"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/workspace"
+	pulumirpc "github.com/pulumi/pulumi/sdk/proto/go"
+)
+
+// server implements the analyzer gRPC interface on top of an Analyzer.
+type server struct {
+	analyzer *Analyzer
+}
+
+// NewServer returns a gRPC analyzer server that runs the given analyzer's rules.
+func NewServer(a *Analyzer) pulumirpc.AnalyzerServer {
+	return &server{analyzer: a}
+}
+
+// Analyze runs the resource rules that apply to a single resource.
+func (s *server) Analyze(ctx context.Context, req *pulumirpc.AnalyzeRequest) (*pulumirpc.AnalyzeResponse, error) {
+	props, err := unmarshalProperties(req.GetProperties())
+	if err != nil {
+		return nil, err
+	}
+	failures, err := s.analyzer.AnalyzeResource(&Resource{
+		Type:       tokens.Type(req.GetType()),
+		Properties: props,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.AnalyzeResponse{Failures: marshalFailures(failures)}, nil
+}
+
+// AnalyzeStack runs the stack rules against all of the resources in a stack.
+func (s *server) AnalyzeStack(ctx context.Context,
+	req *pulumirpc.AnalyzeStackRequest) (*pulumirpc.AnalyzeResponse, error) {
+
+	var resources []*Resource
+	for _, res := range req.GetResources() {
+		props, err := unmarshalProperties(res.GetProperties())
+		if err != nil {
+			return nil, err
+		}
+
+		var deps []resource.URN
+		for _, dep := range res.GetDependencies() {
+			deps = append(deps, resource.URN(dep))
+		}
+
+		resources = append(resources, &Resource{
+			URN:          resource.URN(res.GetUrn()),
+			Type:         tokens.Type(res.GetType()),
+			Properties:   props,
+			Parent:       resource.URN(res.GetParent()),
+			Dependencies: deps,
+			Provider:     res.GetProvider(),
+		})
+	}
+
+	failures, err := s.analyzer.AnalyzeStack(resources)
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.AnalyzeResponse{Failures: marshalFailures(failures)}, nil
+}
+
+// GetPluginInfo returns the analyzer's version.
+func (s *server) GetPluginInfo(context.Context, *pbempty.Empty) (*pulumirpc.PluginInfo, error) {
+	return &pulumirpc.PluginInfo{Version: s.analyzer.Version}, nil
+}
+
+// unmarshalProperties unmarshals the properties of a resource sent by the engine.
+func unmarshalProperties(props *pbstruct.Struct) (resource.PropertyMap, error) {
+	return plugin.UnmarshalProperties(props, plugin.MarshalOptions{
+		Label:        "analyzer.properties",
+		KeepUnknowns: true,
+		SkipNulls:    true,
+	})
+}
+
+// marshalFailures converts failures into their RPC representation.
+func marshalFailures(failures []plugin.AnalyzeFailure) []*pulumirpc.AnalyzeFailure {
+	var protoFailures []*pulumirpc.AnalyzeFailure
+	for _, failure := range failures {
+		protoFailures = append(protoFailures, &pulumirpc.AnalyzeFailure{
+			Urn:              string(failure.URN),
+			Property:         string(failure.Property),
+			Reason:           failure.Reason,
+			PolicyName:       failure.PolicyName,
+			Description:      failure.Description,
+			Severity:         failure.Severity,
+			EnforcementLevel: marshalEnforcementLevel(failure.EnforcementLevel),
+		})
+	}
+	return protoFailures
+}
+
+// marshalEnforcementLevel converts an enforcement level into its RPC representation.
+func marshalEnforcementLevel(level workspace.EnforcementLevel) pulumirpc.EnforcementLevel {
+	switch level {
+	case workspace.Mandatory, "":
+		return pulumirpc.EnforcementLevel_MANDATORY
+	case workspace.Advisory:
+		return pulumirpc.EnforcementLevel_ADVISORY
+	case workspace.Disabled:
+		return pulumirpc.EnforcementLevel_DISABLED
+	default:
+		contract.Failf("Unrecognized enforcement level: %v", level)
+		return pulumirpc.EnforcementLevel_MANDATORY
+	}
+}
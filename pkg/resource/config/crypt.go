@@ -53,6 +53,15 @@ func (nopDecrypter) DecryptValue(ciphertext string) (string, error) {
 	return ciphertext, nil
 }
 
+// A nopEncrypter simply returns the plaintext as-is.
+type nopEncrypter struct{}
+
+var NopEncrypter Encrypter = nopEncrypter{}
+
+func (nopEncrypter) EncryptValue(plaintext string) (string, error) {
+	return plaintext, nil
+}
+
 // NewBlindingDecrypter returns a Decrypter that instead of decrypting data, just returns "[secret]", it can
 // be used when you want to display configuration information to a user but don't want to prompt for a password
 // so secrets will not be decrypted.
@@ -16,13 +16,17 @@ package config
 
 import (
 	"encoding/json"
-	"errors"
+
+	"github.com/pkg/errors"
 )
 
-// Value is a single config value.
+// Value is a single config value.  A value is either a string or, if it is an object, the JSON representation of a
+// structured object or array.  Secrets inside structured values are represented by objects of the form
+// {"secure": "<ciphertext>"}.
 type Value struct {
 	value  string
 	secure bool
+	object bool
 }
 
 func NewSecureValue(v string) Value {
@@ -33,8 +37,20 @@ func NewValue(v string) Value {
 	return Value{value: v, secure: false}
 }
 
+// NewObjectValue creates a structured config value from its JSON representation.
+func NewObjectValue(v string) Value {
+	return Value{value: v, object: true}
+}
+
+// NewSecureObjectValue creates a structured config value, one or more of whose leaves are secrets, from its JSON
+// representation.
+func NewSecureObjectValue(v string) Value {
+	return Value{value: v, secure: true, object: true}
+}
+
 // Value fetches the value of this configuration entry, using decrypter to decrypt if necessary.  If the value
-// is a secret and decrypter is nil, or if decryption fails for any reason, a non-nil error is returned.
+// is a secret and decrypter is nil, or if decryption fails for any reason, a non-nil error is returned.  Structured
+// values are returned as JSON, with any secrets they contain replaced by their decrypted values.
 func (c Value) Value(decrypter Decrypter) (string, error) {
 	if !c.secure {
 		return c.value, nil
@@ -42,15 +58,125 @@ func (c Value) Value(decrypter Decrypter) (string, error) {
 	if decrypter == nil {
 		return "", errors.New("non-nil decrypter required for secret")
 	}
+	if !c.object {
+		return decrypter.DecryptValue(c.value)
+	}
 
-	return decrypter.DecryptValue(c.value)
+	obj, err := c.ToObject()
+	if err != nil {
+		return "", err
+	}
+	decrypted, err := mapSecureValues(obj, func(ciphertext string) (interface{}, error) {
+		return decrypter.DecryptValue(ciphertext)
+	})
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(decrypted)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// SecureValues returns the decrypted values of all of the secrets in this configuration entry.
+func (c Value) SecureValues(decrypter Decrypter) ([]string, error) {
+	if !c.secure {
+		return nil, nil
+	}
+	if !c.object {
+		v, err := c.Value(decrypter)
+		if err != nil {
+			return nil, err
+		}
+		return []string{v}, nil
+	}
+
+	obj, err := c.ToObject()
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	if _, err = mapSecureValues(obj, func(ciphertext string) (interface{}, error) {
+		v, derr := decrypter.DecryptValue(ciphertext)
+		values = append(values, v)
+		return v, derr
+	}); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Copy returns a copy of this configuration entry in which each secret has been decrypted using decrypter and then
+// re-encrypted using encrypter.
+func (c Value) Copy(decrypter Decrypter, encrypter Encrypter) (Value, error) {
+	if !c.secure {
+		return c, nil
+	}
+
+	recrypt := func(ciphertext string) (string, error) {
+		plaintext, err := decrypter.DecryptValue(ciphertext)
+		if err != nil {
+			return "", err
+		}
+		return encrypter.EncryptValue(plaintext)
+	}
+	if !c.object {
+		v, err := recrypt(c.value)
+		if err != nil {
+			return Value{}, err
+		}
+		return NewSecureValue(v), nil
+	}
+
+	obj, err := c.ToObject()
+	if err != nil {
+		return Value{}, err
+	}
+	recrypted, err := mapSecureValues(obj, func(ciphertext string) (interface{}, error) {
+		v, rerr := recrypt(ciphertext)
+		if rerr != nil {
+			return nil, rerr
+		}
+		return map[string]interface{}{"secure": v}, nil
+	})
+	if err != nil {
+		return Value{}, err
+	}
+	b, err := json.Marshal(recrypted)
+	if err != nil {
+		return Value{}, err
+	}
+	return NewSecureObjectValue(string(b)), nil
 }
 
 func (c Value) Secure() bool {
 	return c.secure
 }
 
+// Object returns true if this is a structured configuration value.
+func (c Value) Object() bool {
+	return c.object
+}
+
+// ToObject returns the structured form of this configuration entry: the decoded JSON value for structured values, or
+// the raw string otherwise.  Secrets are left in their encrypted {"secure": "<ciphertext>"} form.
+func (c Value) ToObject() (interface{}, error) {
+	if !c.object {
+		return c.value, nil
+	}
+
+	var obj interface{}
+	if err := json.Unmarshal([]byte(c.value), &obj); err != nil {
+		return nil, errors.Wrap(err, "malformed structured configuration value")
+	}
+	return obj, nil
+}
+
 func (c Value) MarshalJSON() ([]byte, error) {
+	if c.object {
+		return []byte(c.value), nil
+	}
 	if !c.secure {
 		return json.Marshal(c.value)
 	}
@@ -62,27 +188,17 @@ func (c Value) MarshalJSON() ([]byte, error) {
 }
 
 func (c *Value) UnmarshalJSON(b []byte) error {
-	var m map[string]string
-	err := json.Unmarshal(b, &m)
-	if err == nil {
-		if len(m) != 1 {
-			return errors.New("malformed secure data")
-		}
-
-		val, has := m["secure"]
-		if !has {
-			return errors.New("malformed secure data")
-		}
-
-		c.value = val
-		c.secure = true
-		return nil
+	var obj interface{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
 	}
-
-	return json.Unmarshal(b, &c.value)
+	return c.fromObject(obj)
 }
 
 func (c Value) MarshalYAML() (interface{}, error) {
+	if c.object {
+		return c.ToObject()
+	}
 	if !c.secure {
 		return c.value, nil
 	}
@@ -94,23 +210,152 @@ func (c Value) MarshalYAML() (interface{}, error) {
 }
 
 func (c *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	var m map[string]string
-	err := unmarshal(&m)
-	if err == nil {
-		if len(m) != 1 {
-			return errors.New("malformed secure data")
-		}
+	var obj interface{}
+	if err := unmarshal(&obj); err != nil {
+		return err
+	}
+	if _, isMap := obj.(map[interface{}]interface{}); isMap {
+		return c.fromObject(obj)
+	} else if _, isArray := obj.([]interface{}); isArray {
+		return c.fromObject(obj)
+	}
 
-		val, has := m["secure"]
-		if !has {
-			return errors.New("malformed secure data")
-		}
+	// Scalars of any type are kept in their string form.
+	c.secure, c.object = false, false
+	return unmarshal(&c.value)
+}
 
-		c.value = val
-		c.secure = true
+// fromObject sets this configuration entry from the decoded form of a JSON or YAML value.
+func (c *Value) fromObject(obj interface{}) error {
+	obj, err := toJSONObject(obj)
+	if err != nil {
+		return err
+	}
+
+	if s, ok := obj.(string); ok {
+		c.value, c.secure, c.object = s, false, false
+		return nil
+	}
+	if ciphertext, ok := secureValue(obj); ok {
+		c.value, c.secure, c.object = ciphertext, true, false
 		return nil
 	}
+	switch obj.(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		return errors.Errorf("unsupported configuration value %v; values must be strings, objects, or arrays", obj)
+	}
 
-	c.secure = false
-	return unmarshal(&c.value)
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	c.value, c.object = string(b), true
+	c.secure = containsSecureValues(obj)
+	return nil
+}
+
+// secureValue returns the ciphertext of the given decoded value if it is an object of the form
+// {"secure": "<ciphertext>"}.
+func secureValue(obj interface{}) (string, bool) {
+	m, ok := obj.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	ciphertext, ok := m["secure"].(string)
+	return ciphertext, ok
+}
+
+// containsSecureValues returns true if the given decoded value contains at least one secret.
+func containsSecureValues(obj interface{}) bool {
+	if _, ok := secureValue(obj); ok {
+		return true
+	}
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		for _, v := range obj {
+			if containsSecureValues(v) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, v := range obj {
+			if containsSecureValues(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mapSecureValues returns a copy of the given decoded value in which each secret has been replaced by the result of
+// calling f with its ciphertext.
+func mapSecureValues(obj interface{}, f func(ciphertext string) (interface{}, error)) (interface{}, error) {
+	if ciphertext, ok := secureValue(obj); ok {
+		return f(ciphertext)
+	}
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(obj))
+		for k, v := range obj {
+			mv, err := mapSecureValues(v, f)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = mv
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(obj))
+		for i, v := range obj {
+			mv, err := mapSecureValues(v, f)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = mv
+		}
+		return result, nil
+	}
+	return obj, nil
+}
+
+// toJSONObject converts a decoded YAML value, whose objects may have keys of any type, into a JSON-compatible form.
+func toJSONObject(obj interface{}) (interface{}, error) {
+	switch obj := obj.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(obj))
+		for k, v := range obj {
+			ks, ok := k.(string)
+			if !ok {
+				return nil, errors.Errorf("unsupported configuration object key %v; keys must be strings", k)
+			}
+			mv, err := toJSONObject(v)
+			if err != nil {
+				return nil, err
+			}
+			result[ks] = mv
+		}
+		return result, nil
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(obj))
+		for k, v := range obj {
+			mv, err := toJSONObject(v)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = mv
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(obj))
+		for i, v := range obj {
+			mv, err := toJSONObject(v)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = mv
+		}
+		return result, nil
+	}
+	return obj, nil
 }
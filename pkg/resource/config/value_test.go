@@ -70,6 +70,68 @@ func TestMarshallSecureValueJSON(t *testing.T) {
 	assert.Equal(t, v, newV)
 }
 
+func TestMarshallObjectValueYAML(t *testing.T) {
+	v := NewSecureObjectValue(`{"subnets":["10.0.0.0/24",{"secure":"value"}]}`)
+
+	b, err := yaml.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("subnets:\n- 10.0.0.0/24\n- secure: value\n"), b)
+
+	newV, err := roundtripValueYAML(v)
+	assert.NoError(t, err)
+	assert.Equal(t, v, newV)
+
+	// Objects without secrets are not secure.
+	var plain Value
+	assert.NoError(t, yaml.Unmarshal([]byte("tags:\n  Name: web\n"), &plain))
+	assert.Equal(t, NewObjectValue(`{"tags":{"Name":"web"}}`), plain)
+}
+
+func TestMarshallObjectValueJSON(t *testing.T) {
+	v := NewSecureObjectValue(`{"subnets":["10.0.0.0/24",{"secure":"value"}]}`)
+
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"subnets":["10.0.0.0/24",{"secure":"value"}]}`), b)
+
+	newV, err := roundtripValueJSON(v)
+	assert.NoError(t, err)
+	assert.Equal(t, v, newV)
+}
+
+func TestObjectValueSecrets(t *testing.T) {
+	crypter := NewSymmetricCrypterFromPassphrase("password", []byte("salt"))
+	ciphertext, err := crypter.EncryptValue("hunter2")
+	assert.NoError(t, err)
+
+	v := NewSecureObjectValue(`{"password":{"secure":"` + ciphertext + `"},"user":"admin"}`)
+	assert.True(t, v.Secure())
+	assert.True(t, v.Object())
+
+	decrypted, err := v.Value(crypter)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"password":"hunter2","user":"admin"}`, decrypted)
+
+	blinded, err := v.Value(NewBlindingDecrypter())
+	assert.NoError(t, err)
+	assert.Equal(t, `{"password":"[secret]","user":"admin"}`, blinded)
+
+	secrets, err := v.SecureValues(crypter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hunter2"}, secrets)
+
+	// Copying re-encrypts only the secrets.
+	other := NewSymmetricCrypterFromPassphrase("other", []byte("salt"))
+	copied, err := v.Copy(crypter, other)
+	assert.NoError(t, err)
+	assert.True(t, copied.Secure())
+	_, err = copied.Value(crypter)
+	assert.Error(t, err)
+	decrypted, err = copied.Value(other)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"password":"hunter2","user":"admin"}`, decrypted)
+}
+
 func roundtripValueYAML(v Value) (Value, error) {
 	return roundtripValue(v, yaml.Marshal, yaml.Unmarshal)
 }
@@ -0,0 +1,94 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+// AnalyzerResources converts the given resource states into the form in which they are presented to analyzers.
+// Resources that are pending deletion are no longer part of the stack, and are omitted. Each resource's properties are
+// its outputs, or its inputs if it has no outputs (e.g. because it has not yet been created).
+func AnalyzerResources(states []*resource.State) []plugin.AnalyzerResource {
+	var resources []plugin.AnalyzerResource
+	for _, state := range states {
+		if state.Delete {
+			continue
+		}
+
+		props := state.Outputs
+		if len(props) == 0 {
+			props = state.Inputs
+		}
+		resources = append(resources, plugin.AnalyzerResource{
+			URN:          state.URN,
+			Type:         state.Type,
+			Properties:   props,
+			Parent:       state.Parent,
+			Dependencies: state.Dependencies,
+			Provider:     state.Provider,
+		})
+	}
+	return resources
+}
+
+// AnalyzeSnapshot runs the given analyzers against the live resources in a snapshot, without the need for a program,
+// providers, or a plan. Each resource is first analyzed on its own, using its outputs (or its inputs if it has no
+// outputs), after which all of the resources are analyzed together, just as they would be over the course of an update.
+// The enforcement level of each failure is adjusted according to the given policy, if any, and failures of disabled
+// policies are dropped. The violations that remain are returned.
+func AnalyzeSnapshot(snap *Snapshot, analyzers []plugin.Analyzer,
+	policy *workspace.ProjectPolicy) ([]PolicyViolation, error) {
+
+	var resources []plugin.AnalyzerResource
+	if snap != nil {
+		resources = AnalyzerResources(snap.Resources)
+	}
+
+	var violations []PolicyViolation
+	record := func(a plugin.Analyzer, failure plugin.AnalyzeFailure) {
+		failure.EnforcementLevel = policy.EnforcementLevel(failure.PolicyName, failure.EnforcementLevel)
+		if failure.EnforcementLevel != workspace.Disabled {
+			violations = append(violations, PolicyViolation{Analyzer: a.Name(), AnalyzeFailure: failure})
+		}
+	}
+
+	for _, a := range analyzers {
+		for _, res := range resources {
+			failures, err := a.Analyze(res.Type, res.Properties)
+			if err != nil {
+				return nil, errors.Wrapf(err, "analyzer '%v' failed to analyze resource '%v'", a.Name(), res.URN)
+			}
+			for _, failure := range failures {
+				failure.URN = res.URN
+				record(a, failure)
+			}
+		}
+
+		failures, err := a.AnalyzeStack(resources)
+		if err != nil {
+			return nil, errors.Wrapf(err, "analyzer '%v' failed to analyze stack", a.Name())
+		}
+		for _, failure := range failures {
+			record(a, failure)
+		}
+	}
+
+	return violations, nil
+}
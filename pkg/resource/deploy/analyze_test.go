@@ -0,0 +1,122 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/workspace"
+)
+
+func TestAnalyzeSnapshot(t *testing.T) {
+	resType := tokens.Type("pkgA:m:typA")
+	urnA := resource.NewURN("test", "proj", "", resType, "resA")
+	urnB := resource.NewURN("test", "proj", "", resType, "resB")
+	snap := NewSnapshot(Manifest{}, []*resource.State{
+		{Type: resType, URN: urnA, Custom: true, ID: "a", Outputs: resource.PropertyMap{
+			"public": resource.NewBoolProperty(true),
+		}},
+		{Type: resType, URN: urnB, Custom: true, Inputs: resource.PropertyMap{
+			"public": resource.NewBoolProperty(true),
+		}, Dependencies: []resource.URN{urnA}},
+		// Resources that are pending deletion are not analyzed.
+		{Type: resType, URN: urnB, Custom: true, ID: "old", Delete: true, Outputs: resource.PropertyMap{
+			"public": resource.NewBoolProperty(true),
+		}},
+	}, nil)
+
+	analyzer := &deploytest.Analyzer{
+		Info: workspace.PluginInfo{Name: "policy"},
+		AnalyzeF: func(t tokens.Type, props resource.PropertyMap) ([]plugin.AnalyzeFailure, error) {
+			if props["public"].IsBool() && props["public"].BoolValue() {
+				return []plugin.AnalyzeFailure{{
+					Property:   "public",
+					Reason:     "resource is public",
+					PolicyName: "no-public",
+				}}, nil
+			}
+			return nil, nil
+		},
+		AnalyzeStackF: func(resources []plugin.AnalyzerResource) ([]plugin.AnalyzeFailure, error) {
+			var failures []plugin.AnalyzeFailure
+			for _, res := range resources {
+				if len(res.Dependencies) > 0 {
+					failures = append(failures, plugin.AnalyzeFailure{
+						URN:              res.URN,
+						Reason:           "resource has dependencies",
+						PolicyName:       "no-dependencies",
+						EnforcementLevel: workspace.Advisory,
+					})
+				}
+			}
+			return failures, nil
+		},
+	}
+
+	violations, err := AnalyzeSnapshot(snap, []plugin.Analyzer{analyzer}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []PolicyViolation{
+		{Analyzer: "policy", AnalyzeFailure: plugin.AnalyzeFailure{
+			URN: urnA, Property: "public", Reason: "resource is public", PolicyName: "no-public",
+			EnforcementLevel: workspace.Mandatory,
+		}},
+		{Analyzer: "policy", AnalyzeFailure: plugin.AnalyzeFailure{
+			URN: urnB, Property: "public", Reason: "resource is public", PolicyName: "no-public",
+			EnforcementLevel: workspace.Mandatory,
+		}},
+		{Analyzer: "policy", AnalyzeFailure: plugin.AnalyzeFailure{
+			URN: urnB, Reason: "resource has dependencies", PolicyName: "no-dependencies",
+			EnforcementLevel: workspace.Advisory,
+		}},
+	}, violations)
+
+	// Project policy overrides apply, and disabled policies are dropped.
+	violations, err = AnalyzeSnapshot(snap, []plugin.Analyzer{analyzer}, &workspace.ProjectPolicy{
+		Rules: map[string]workspace.EnforcementLevel{
+			"no-public":       workspace.Disabled,
+			"no-dependencies": workspace.Mandatory,
+		},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, "no-dependencies", violations[0].PolicyName)
+		assert.Equal(t, workspace.Mandatory, violations[0].EnforcementLevel)
+	}
+}
+
+func TestAnalyzerResources(t *testing.T) {
+	resType := tokens.Type("pkgA:m:typA")
+	urnA := resource.NewURN("test", "proj", "", resType, "resA")
+	urnB := resource.NewURN("test", "proj", "", resType, "resB")
+	outputs := resource.PropertyMap{"out": resource.NewStringProperty("a")}
+	inputs := resource.PropertyMap{"in": resource.NewStringProperty("b")}
+
+	resources := AnalyzerResources([]*resource.State{
+		{Type: resType, URN: urnA, Inputs: resource.PropertyMap{"in": resource.NewStringProperty("a")},
+			Outputs: outputs, Provider: "prov"},
+		{Type: resType, URN: urnB, Inputs: inputs, Parent: urnA, Dependencies: []resource.URN{urnA}},
+		{Type: resType, URN: urnB, Outputs: outputs, Delete: true},
+	})
+	assert.Equal(t, []plugin.AnalyzerResource{
+		{URN: urnA, Type: resType, Properties: outputs, Provider: "prov"},
+		{URN: urnB, Type: resType, Properties: inputs, Parent: urnA, Dependencies: []resource.URN{urnA}},
+	}, resources)
+}
@@ -31,8 +31,29 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 	dependencies []resource.URN, provider string,
 	inputs resource.PropertyMap) (resource.URN, resource.ID, resource.PropertyMap, error) {
 
+	return rm.registerResource(t, name, custom, parent, protect, dependencies, provider, "", nil, inputs)
+}
+
+func (rm *ResourceMonitor) RegisterResourceIgnoringChanges(t tokens.Type, name string, custom bool,
+	parent resource.URN, protect bool, dependencies []resource.URN, provider string, ignoreChanges []string,
+	inputs resource.PropertyMap) (resource.URN, resource.ID, resource.PropertyMap, error) {
+
+	return rm.registerResource(t, name, custom, parent, protect, dependencies, provider, "", ignoreChanges, inputs)
+}
+
+func (rm *ResourceMonitor) ImportResource(t tokens.Type, name string, id resource.ID, parent resource.URN,
+	dependencies []resource.URN, provider string,
+	inputs resource.PropertyMap) (resource.URN, resource.ID, resource.PropertyMap, error) {
+
+	return rm.registerResource(t, name, true, parent, false, dependencies, provider, id, nil, inputs)
+}
+
+func (rm *ResourceMonitor) registerResource(t tokens.Type, name string, custom bool, parent resource.URN,
+	protect bool, dependencies []resource.URN, provider string, importID resource.ID, ignoreChanges []string,
+	inputs resource.PropertyMap) (resource.URN, resource.ID, resource.PropertyMap, error) {
+
 	// marshal inputs
-	ins, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{KeepUnknowns: true})
+	ins, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
 	if err != nil {
 		return "", "", nil, err
 	}
@@ -45,14 +66,16 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 
 	// submit request
 	resp, err := rm.resmon.RegisterResource(context.Background(), &pulumirpc.RegisterResourceRequest{
-		Type:         string(t),
-		Name:         name,
-		Custom:       custom,
-		Parent:       string(parent),
-		Protect:      protect,
-		Dependencies: deps,
-		Provider:     provider,
-		Object:       ins,
+		Type:          string(t),
+		Name:          name,
+		Custom:        custom,
+		Parent:        string(parent),
+		Protect:       protect,
+		Dependencies:  deps,
+		Provider:      provider,
+		Object:        ins,
+		ImportId:      string(importID),
+		IgnoreChanges: ignoreChanges,
 	})
 	if err != nil {
 		return "", "", nil, err
@@ -71,7 +94,7 @@ func (rm *ResourceMonitor) ReadResource(t tokens.Type, name string, id resource.
 	inputs resource.PropertyMap, provider string) (resource.URN, resource.PropertyMap, error) {
 
 	// marshal inputs
-	ins, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{KeepUnknowns: true})
+	ins, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
 	if err != nil {
 		return "", nil, err
 	}
@@ -101,7 +124,7 @@ func (rm *ResourceMonitor) Invoke(tok tokens.ModuleMember,
 	inputs resource.PropertyMap, provider string) (resource.PropertyMap, []*pulumirpc.CheckFailure, error) {
 
 	// marshal inputs
-	ins, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{KeepUnknowns: true})
+	ins, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
 	if err != nil {
 		return nil, nil, err
 	}
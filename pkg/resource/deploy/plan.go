@@ -26,12 +26,31 @@ import (
 	"github.com/pulumi/pulumi/pkg/resource/plugin"
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/workspace"
 )
 
 // Options controls the planning and deployment process.
 type Options struct {
-	Events   Events // an optional events callback interface.
-	Parallel int    // the degree of parallelism for resource operations (<=1 for serial).
+	Events           Events       // an optional events callback interface.
+	Parallel         int          // the degree of parallelism for resource operations (<=1 for serial).
+	Targets          TargetFilter // an optional filter that restricts the plan to a subset of resources.
+	TargetDependents bool         // true if resources that depend on targeted resources should also be targeted.
+	ReplaceTargets   TargetFilter // an optional filter that selects resources that must be replaced.
+	Imports          []Import     // an optional list of existing resources to import.
+
+	Policy *workspace.ProjectPolicy // optional overrides for the enforcement levels of analyzers' policies.
+}
+
+// TargetFilter determines whether or not the resource with the given URN was explicitly targeted by the user. A nil
+// TargetFilter targets every resource.
+type TargetFilter func(urn resource.URN) bool
+
+// Import describes an existing resource that a plan should bring under management. The resource to import is identified
+// by the type and name with which it is registered by the program, and the existing resource by its provider ID.
+type Import struct {
+	Type tokens.Type  // the type of the resource to import.
+	Name tokens.QName // the name of the resource to import.
+	ID   resource.ID  // the ID of the existing resource.
 }
 
 // DegreeOfParallelism returns the degree of parallelism that should be used during the
@@ -58,6 +77,13 @@ type PlanSummary interface {
 	Replaces() map[resource.URN]bool
 	Deletes() map[resource.URN]bool
 	Sames() map[resource.URN]bool
+	PolicyViolations() []PolicyViolation
+}
+
+// PolicyViolation records a violation of a policy that was reported by one of a plan's analyzers and not disabled.
+type PolicyViolation struct {
+	Analyzer              tokens.QName // the analyzer that reported the violation.
+	plugin.AnalyzeFailure              // the violation, with the enforcement level that was applied to it.
 }
 
 // PlanPendingOperationsError is an error returned from `NewPlan` if there exist pending operations in the
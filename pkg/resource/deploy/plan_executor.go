@@ -125,14 +125,37 @@ outer:
 			if event.Event == nil {
 				logging.V(planExecutorLogLevel).Infof("PlanExecutor.Execute(...): saw nil event, beginning termination")
 
-				// TODO[pulumi/pulumi#1625] Today we lack the ability to parallelize deletions. We have all the
-				// information we need to do so (namely, a dependency graph). `GenerateDeletes` returns a single
-				// chain of every delete that needs to be executed.
-				deletes := pe.stepGen.GenerateDeletes()
-				pe.stepExec.Execute(deletes)
-
-				// Signal completion to the step executor. It'll exit once it's done retiring all of the steps in
-				// the chain that we just gave it.
+				// Before deleting anything, make sure that every resource we were asked to import was registered.
+				if err := pe.stepGen.CheckImports(); err != nil {
+					logging.V(planExecutorLogLevel).Infof("PlanExecutor.Execute(...): checking imports produced "+
+						"an error: %v", err)
+					pe.cancelDueToError()
+					break outer
+				}
+
+				deletes, err := pe.stepGen.GenerateDeletes()
+				if err != nil {
+					logging.V(planExecutorLogLevel).Infof("PlanExecutor.Execute(...): generating deletes produced "+
+						"an error: %v", err)
+					pe.cancelDueToError()
+					break outer
+				}
+
+				// Deletes are scheduled as a sequence of antichains: the deletes within each antichain run in
+				// parallel, but we wait for each antichain to finish before starting the next so that no resource
+				// is deleted before its dependents.
+				for _, antichain := range pe.stepGen.ScheduleDeletes(deletes) {
+					logging.V(planExecutorLogLevel).Infof("PlanExecutor.Execute(...): submitting %v deletes for "+
+						"execution", len(antichain))
+					pe.stepExec.ExecuteParallel(antichain).Wait(pe.ctx)
+					if pe.ctx.Err() != nil {
+						logging.V(planExecutorLogLevel).Infof("PlanExecutor.Execute(...): deletes canceled")
+						break
+					}
+				}
+
+				// Signal completion to the step executor. It'll exit once it's done retiring all of the steps that
+				// we just gave it.
 				pe.stepExec.SignalCompletion()
 				logging.V(planExecutorLogLevel).Infof("PlanExecutor.Execute(...): completed deletes, exiting loop")
 				break outer
@@ -157,6 +180,16 @@ outer:
 		return ErrCanceled
 	}
 
+	// Now that every resource has reached its final state, give the analyzers a chance to inspect the stack as a
+	// whole.
+	if !pe.errored() {
+		if err := pe.stepGen.AnalyzeStack(); err != nil {
+			logging.V(planExecutorLogLevel).Infof("PlanExecutor.Execute(...): analyzing the stack produced "+
+				"an error: %v", err)
+			pe.sawError.Store(true)
+		}
+	}
+
 	if pe.errored() {
 		logging.V(planExecutorLogLevel).Infof("PlanExecutor.Execute(...): observed that the plan errored")
 		if pe.preview {
@@ -213,6 +246,7 @@ func (pe *PlanExecutor) handleSingleEvent(event SourceEvent) {
 		}
 
 		logging.V(planExecutorLogLevel).Infof("PlanExecutor.handleSingleEvent(...): submitting chain for execution")
+		pe.stepGen.RecordGoals(step)
 		pe.stepExec.Execute(step)
 	case ReadResourceEvent:
 		step, steperr := pe.stepGen.GenerateReadSteps(e)
@@ -224,6 +258,7 @@ func (pe *PlanExecutor) handleSingleEvent(event SourceEvent) {
 		}
 
 		logging.V(planExecutorLogLevel).Infof("PlanExecutor.handleSingleEvent(...): submitting reads for execution")
+		pe.stepGen.RecordGoals(step)
 		pe.stepExec.Execute(step)
 	case RegisterResourceOutputsEvent:
 		logging.V(planExecutorLogLevel).Infof("PlanExecutor.handleSingleEvent(...): received register resource outputs")
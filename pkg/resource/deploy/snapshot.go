@@ -23,6 +23,7 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/workspace"
 )
 
@@ -140,3 +141,68 @@ func (snap *Snapshot) VerifyIntegrity() error {
 
 	return nil
 }
+
+// RenameStack rewrites the snapshot so that its resources belong to the stack with the given name. Because URNs embed
+// the name of their stack, this updates the URN of every resource along with every parent, dependency, and provider
+// reference that refers to a resource by its URN. The root stack resource is named after its project and stack, so it
+// is renamed as well, along with every reference to it.
+func (snap *Snapshot) RenameStack(name tokens.QName) error {
+	if snap == nil {
+		return nil
+	}
+
+	renameURN := func(urn resource.URN) resource.URN {
+		if urn == "" {
+			return ""
+		}
+		resName := urn.Name()
+		if urn.Type() == resource.RootStackType && resName == rootStackName(urn.Project(), urn.Stack()) {
+			resName = rootStackName(urn.Project(), name)
+		}
+		return resource.NewURN(name, urn.Project(), "", urn.QualifiedType(), resName)
+	}
+
+	renamed := make(map[*resource.State]bool)
+	renameState := func(state *resource.State) error {
+		if renamed[state] {
+			return nil
+		}
+		renamed[state] = true
+
+		state.URN = renameURN(state.URN)
+		state.Parent = renameURN(state.Parent)
+		for i, dep := range state.Dependencies {
+			state.Dependencies[i] = renameURN(dep)
+		}
+		if state.Provider != "" {
+			ref, err := providers.ParseReference(state.Provider)
+			if err != nil {
+				return errors.Wrapf(err, "parsing provider reference for resource %s", state.URN)
+			}
+			newRef, err := providers.NewReference(renameURN(ref.URN()), ref.ID())
+			if err != nil {
+				return errors.Wrapf(err, "renaming provider reference for resource %s", state.URN)
+			}
+			state.Provider = newRef.String()
+		}
+		return nil
+	}
+
+	for _, state := range snap.Resources {
+		if err := renameState(state); err != nil {
+			return err
+		}
+	}
+	for _, op := range snap.PendingOperations {
+		if err := renameState(op.Resource); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rootStackName returns the name given by the language SDKs to the root stack resource of the given project and stack.
+func rootStackName(project tokens.PackageName, stack tokens.QName) tokens.QName {
+	return tokens.QName(fmt.Sprintf("%s-%s", project, stack))
+}
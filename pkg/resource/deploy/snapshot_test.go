@@ -0,0 +1,88 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/tokens"
+)
+
+func TestRenameStack(t *testing.T) {
+	provType := providers.MakeProviderType("pkgA")
+	prov := &resource.State{
+		Type:   provType,
+		URN:    resource.NewURN("old", "proj", "", provType, "default"),
+		ID:     "provider-id",
+		Custom: true,
+	}
+	provRef, err := providers.NewReference(prov.URN, prov.ID)
+	assert.NoError(t, err)
+
+	root := &resource.State{
+		Type: resource.RootStackType,
+		URN:  resource.NewURN("old", "proj", "", resource.RootStackType, "proj-old"),
+	}
+	parentType, childType := tokens.Type("pkgA:m:parent"), tokens.Type("pkgA:m:child")
+	parent := &resource.State{
+		Type:   parentType,
+		URN:    resource.NewURN("old", "proj", resource.RootStackType, parentType, "parent"),
+		Parent: root.URN,
+	}
+	child := &resource.State{
+		Type:         childType,
+		URN:          resource.NewURN("old", "proj", resource.RootStackType+"$"+parentType, childType, "child"),
+		Custom:       true,
+		ID:           "child-id",
+		Parent:       parent.URN,
+		Dependencies: []resource.URN{parent.URN},
+		Provider:     provRef.String(),
+	}
+	pending := &resource.State{
+		Type:     childType,
+		URN:      resource.NewURN("old", "proj", "", childType, "pending"),
+		Custom:   true,
+		Provider: provRef.String(),
+	}
+
+	snap := newSnapshot([]*resource.State{root, prov, parent, child}, []resource.Operation{
+		{Type: resource.OperationTypeCreating, Resource: pending},
+	})
+	snap.Manifest.Magic = snap.Manifest.NewMagic()
+	assert.NoError(t, snap.RenameStack("new"))
+
+	newRootURN := resource.NewURN("new", "proj", "", resource.RootStackType, "proj-new")
+	newProvURN := resource.NewURN("new", "proj", "", provType, "default")
+	newParentURN := resource.NewURN("new", "proj", resource.RootStackType, parentType, "parent")
+	newProvRef, err := providers.NewReference(newProvURN, "provider-id")
+	assert.NoError(t, err)
+
+	assert.Equal(t, newRootURN, root.URN)
+	assert.Equal(t, newProvURN, prov.URN)
+	assert.Equal(t, newParentURN, parent.URN)
+	assert.Equal(t, newRootURN, parent.Parent)
+	assert.Equal(t, resource.NewURN("new", "proj", resource.RootStackType+"$"+parentType, childType, "child"),
+		child.URN)
+	assert.Equal(t, newParentURN, child.Parent)
+	assert.Equal(t, []resource.URN{newParentURN}, child.Dependencies)
+	assert.Equal(t, newProvRef.String(), child.Provider)
+	assert.Equal(t, resource.NewURN("new", "proj", "", childType, "pending"), pending.URN)
+	assert.Equal(t, newProvRef.String(), pending.Provider)
+	assert.NoError(t, snap.VerifyIntegrity())
+}
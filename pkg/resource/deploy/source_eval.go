@@ -257,7 +257,8 @@ func (d *defaultProviders) newRegisterDefaultProviderEvent(
 	// Create the result channel and the event.
 	done := make(chan *RegisterResult)
 	event := &registerResourceEvent{
-		goal: resource.NewGoal(providers.MakeProviderType(pkg), "default", true, inputs, "", false, nil, "", nil),
+		goal: resource.NewGoal(providers.MakeProviderType(pkg), "default", true, inputs, "", false, nil, "", nil, "",
+			nil),
 		done: done,
 	}
 	return event, done, nil
@@ -516,6 +517,7 @@ func (rm *resmon) ReadResource(ctx context.Context,
 	props, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
 		Label:        label,
 		KeepUnknowns: true,
+		KeepSecrets:  true,
 	})
 	if err != nil {
 		return nil, err
@@ -573,6 +575,8 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 	custom := req.GetCustom()
 	parent := resource.URN(req.GetParent())
 	protect := req.GetProtect()
+	importID := resource.ID(req.GetImportId())
+	ignoreChanges := req.GetIgnoreChanges()
 
 	provider := req.GetProvider()
 	if custom && !providers.IsProviderType(t) && provider == "" {
@@ -588,20 +592,28 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 		dependencies = append(dependencies, resource.URN(dependingURN))
 	}
 
+	for _, path := range ignoreChanges {
+		if _, err := resource.ParsePropertyPath(path); err != nil {
+			return nil, errors.Wrapf(err, "invalid ignoreChanges path '%s'", path)
+		}
+	}
+
 	props, err := plugin.UnmarshalProperties(
-		req.GetObject(), plugin.MarshalOptions{Label: label, KeepUnknowns: true, ComputeAssetHashes: true})
+		req.GetObject(), plugin.MarshalOptions{Label: label, KeepUnknowns: true, ComputeAssetHashes: true,
+			KeepSecrets: true})
 	if err != nil {
 		return nil, err
 	}
 
 	logging.V(5).Infof(
 		"ResourceMonitor.RegisterResource received: t=%v, name=%v, custom=%v, #props=%v, parent=%v, protect=%v, "+
-			"provider=%v, deps=%v",
-		t, name, custom, len(props), parent, protect, provider, dependencies)
+			"provider=%v, deps=%v, importID=%v, ignoreChanges=%v",
+		t, name, custom, len(props), parent, protect, provider, dependencies, importID, ignoreChanges)
 
 	// Send the goal state to the engine.
 	step := &registerResourceEvent{
-		goal: resource.NewGoal(t, name, custom, props, parent, protect, dependencies, provider, nil),
+		goal: resource.NewGoal(t, name, custom, props, parent, protect, dependencies, provider, nil, importID,
+			ignoreChanges),
 		done: make(chan *RegisterResult),
 	}
 
@@ -659,7 +671,8 @@ func (rm *resmon) RegisterResourceOutputs(ctx context.Context,
 	}
 	label := fmt.Sprintf("ResourceMonitor.RegisterResourceOutputs(%s)", urn)
 	outs, err := plugin.UnmarshalProperties(
-		req.GetOutputs(), plugin.MarshalOptions{Label: label, KeepUnknowns: true, ComputeAssetHashes: true})
+		req.GetOutputs(), plugin.MarshalOptions{Label: label, KeepUnknowns: true, ComputeAssetHashes: true,
+			KeepSecrets: true})
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot unmarshal output properties")
 	}
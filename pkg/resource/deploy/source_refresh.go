@@ -168,12 +168,12 @@ func (iter *refreshSourceIterator) newRefreshGoal(s *resource.State) (*resource.
 		}
 		s = resource.NewState(
 			s.Type, s.URN, s.Custom, s.Delete, s.ID, s.Inputs, refreshed,
-			s.Parent, s.Protect, s.External, s.Dependencies, initErrorReasons, s.Provider)
+			s.Parent, s.Protect, s.External, s.Dependencies, initErrorReasons, s.Provider, s.IgnoreChanges)
 	}
 
 	// Now just return the actual state as the goal state.
 	return resource.NewGoal(s.Type, s.URN.Name(), s.Custom, s.Outputs, s.Parent, s.Protect,
-		s.Dependencies, s.Provider, s.InitErrors), nil
+		s.Dependencies, s.Provider, s.InitErrors, "", s.IgnoreChanges), nil
 }
 
 type refreshSourceEvent struct {
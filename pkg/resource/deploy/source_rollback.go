@@ -0,0 +1,257 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+	"github.com/pulumi/pulumi/pkg/util/logging"
+)
+
+// NewRollbackSource returns a planning source that takes the place of a program by replaying the resources in a
+// previous snapshot of a stack. Planning against it computes the steps needed to return the stack to that snapshot.
+func NewRollbackSource(project tokens.PackageName, snap *Snapshot) Source {
+	return &rollbackSource{project: project, snap: snap}
+}
+
+// A rollbackSource registers the resources in a snapshot with the same inputs, providers, and options they had.
+type rollbackSource struct {
+	project tokens.PackageName
+	snap    *Snapshot
+}
+
+func (src *rollbackSource) Close() error                { return nil }
+func (src *rollbackSource) Project() tokens.PackageName { return src.project }
+func (src *rollbackSource) Info() interface{}           { return nil }
+func (src *rollbackSource) IsRefresh() bool             { return false }
+
+func (src *rollbackSource) Iterate(ctx context.Context, opts Options, providers ProviderSource) (SourceIterator, error) {
+	contract.Require(ctx != nil, "ctx != nil")
+
+	// Resources that were pending deletion in the snapshot were already on their way out, so they are not replayed.
+	var states []*resource.State
+	if src.snap != nil {
+		for _, s := range src.snap.Resources {
+			if !s.Delete {
+				states = append(states, s)
+			}
+		}
+	}
+
+	return &rollbackSourceIterator{
+		ctx:          ctx,
+		states:       states,
+		current:      -1,
+		providerRefs: make(map[string]string),
+	}, nil
+}
+
+// rollbackSourceIterator replays the resources in a snapshot, one at a time.
+type rollbackSourceIterator struct {
+	ctx          context.Context // cancellation context for this source.
+	states       []*resource.State
+	current      int
+	providerRefs map[string]string // maps the snapshot's provider references to those registered by this plan.
+
+	lastEvent     *resource.State       // the state whose event was last sent, or nil if none was sent yet
+	lastEventDone chan *RegisterResult  // completion channel for the last registration that we sent, if any
+	outputsDone   chan struct{}         // completion channel for the last outputs registration that we sent, if any
+	pendingOutput *rollbackOutputsEvent // an outputs registration to send once the last registration retires
+	readDone      chan struct{}         // completion channel for the last read that we sent, if any
+}
+
+func (iter *rollbackSourceIterator) Close() error {
+	return nil // nothing to do.
+}
+
+func (iter *rollbackSourceIterator) Next() (SourceEvent, error) {
+	// As with refreshes, the simplest way to ensure that every event is ready to execute when it is returned (i.e.
+	// that everything it depends on has been registered) is to wait for each event to retire before sending the
+	// next. This also lets us learn the references of the providers that the plan registers, which the resources
+	// that follow must use in place of the ones recorded in the snapshot.
+	if err := iter.waitForLastEvent(); err != nil {
+		return nil, err
+	}
+	if iter.ctx.Err() != nil {
+		logging.V(7).Infof("rollbackSourceIterator.Next(): cancelled, exiting")
+		return nil, nil
+	}
+
+	// If the last resource was a component with outputs, register those before moving on.
+	if outputs := iter.pendingOutput; outputs != nil {
+		iter.pendingOutput = nil
+		iter.outputsDone = outputs.done
+		return outputs, nil
+	}
+
+	iter.current++
+	if iter.current >= len(iter.states) {
+		logging.V(7).Infof("rollbackSourceIterator.Next(): no more goal states")
+		return nil, nil
+	}
+	current := iter.states[iter.current]
+	iter.lastEvent = current
+
+	provider := current.Provider
+	if provider != "" {
+		ref, has := iter.providerRefs[provider]
+		if !has {
+			return nil, errors.Errorf("unknown provider '%v' for resource '%v'", provider, current.URN)
+		}
+		provider = ref
+	} else if current.Custom && !providers.IsProviderType(current.Type) {
+		return nil, errors.Errorf("resource '%v' has no provider and cannot be rolled back", current.URN)
+	}
+
+	if current.External {
+		event := &rollbackReadEvent{
+			id:           current.ID,
+			name:         current.URN.Name(),
+			baseType:     current.Type,
+			provider:     provider,
+			parent:       current.Parent,
+			props:        current.Inputs,
+			dependencies: current.Dependencies,
+			done:         make(chan struct{}, 1),
+		}
+		iter.readDone = event.done
+		return event, nil
+	}
+
+	// The target checkpoint's inputs are restored as they are: ignoring changes to any of them would keep the values
+	// that the rollback is meant to undo.
+	goal := resource.NewGoal(current.Type, current.URN.Name(), current.Custom, current.Inputs, current.Parent,
+		current.Protect, current.Dependencies, provider, nil, "", nil)
+	event := &rollbackSourceEvent{goal: goal, done: make(chan *RegisterResult, 1)}
+	iter.lastEventDone = event.done
+	if !current.Custom && len(current.Outputs) > 0 {
+		iter.pendingOutput = &rollbackOutputsEvent{
+			urn:     current.URN,
+			outputs: current.Outputs,
+			done:    make(chan struct{}, 1),
+		}
+	}
+	return event, nil
+}
+
+// waitForLastEvent waits for the last event that was sent to retire. If it registered a provider, the provider's
+// new reference is recorded.
+func (iter *rollbackSourceIterator) waitForLastEvent() error {
+	switch {
+	case iter.lastEventDone != nil:
+		logging.V(7).Infof("rollbackSourceIterator.Next(): waiting for previous registration to retire")
+		done := iter.lastEventDone
+		iter.lastEventDone = nil
+		select {
+		case result := <-done:
+			if providers.IsProviderType(iter.lastEvent.Type) {
+				if result == nil || result.State == nil {
+					return errors.Errorf("provider '%v' was not registered", iter.lastEvent.URN)
+				}
+				return iter.recordProvider(iter.lastEvent, result.State)
+			}
+		case <-iter.ctx.Done():
+		}
+	case iter.readDone != nil:
+		logging.V(7).Infof("rollbackSourceIterator.Next(): waiting for previous read to retire")
+		done := iter.readDone
+		iter.readDone = nil
+		select {
+		case <-done:
+		case <-iter.ctx.Done():
+		}
+	case iter.outputsDone != nil:
+		logging.V(7).Infof("rollbackSourceIterator.Next(): waiting for previous outputs to retire")
+		done := iter.outputsDone
+		iter.outputsDone = nil
+		select {
+		case <-done:
+		case <-iter.ctx.Done():
+		}
+	}
+	return nil
+}
+
+// recordProvider maps the reference to a provider in the snapshot to the reference of the provider registered in its
+// place.
+func (iter *rollbackSourceIterator) recordProvider(oldState, newState *resource.State) error {
+	oldRef, err := providers.NewReference(oldState.URN, oldState.ID)
+	if err != nil {
+		return err
+	}
+	newRef, err := providers.NewReference(newState.URN, newState.ID)
+	if err != nil {
+		return err
+	}
+	iter.providerRefs[oldRef.String()] = newRef.String()
+	return nil
+}
+
+type rollbackSourceEvent struct {
+	goal *resource.Goal
+	done chan *RegisterResult
+}
+
+func (rse *rollbackSourceEvent) event()               {}
+func (rse *rollbackSourceEvent) Goal() *resource.Goal { return rse.goal }
+func (rse *rollbackSourceEvent) Done(result *RegisterResult) {
+	rse.done <- result
+}
+
+type rollbackOutputsEvent struct {
+	urn     resource.URN
+	outputs resource.PropertyMap
+	done    chan struct{}
+}
+
+var _ RegisterResourceOutputsEvent = (*rollbackOutputsEvent)(nil)
+
+func (roe *rollbackOutputsEvent) event()                        {}
+func (roe *rollbackOutputsEvent) URN() resource.URN             { return roe.urn }
+func (roe *rollbackOutputsEvent) Outputs() resource.PropertyMap { return roe.outputs }
+func (roe *rollbackOutputsEvent) Done() {
+	roe.done <- struct{}{}
+}
+
+type rollbackReadEvent struct {
+	id           resource.ID
+	name         tokens.QName
+	baseType     tokens.Type
+	provider     string
+	parent       resource.URN
+	props        resource.PropertyMap
+	dependencies []resource.URN
+	done         chan struct{}
+}
+
+var _ ReadResourceEvent = (*rollbackReadEvent)(nil)
+
+func (g *rollbackReadEvent) event()                           {}
+func (g *rollbackReadEvent) ID() resource.ID                  { return g.id }
+func (g *rollbackReadEvent) Name() tokens.QName               { return g.name }
+func (g *rollbackReadEvent) Type() tokens.Type                { return g.baseType }
+func (g *rollbackReadEvent) Provider() string                 { return g.provider }
+func (g *rollbackReadEvent) Parent() resource.URN             { return g.parent }
+func (g *rollbackReadEvent) Properties() resource.PropertyMap { return g.props }
+func (g *rollbackReadEvent) Dependencies() []resource.URN     { return g.dependencies }
+func (g *rollbackReadEvent) Done(_ *ReadResult) {
+	g.done <- struct{}{}
+}
@@ -52,10 +52,11 @@ type Step interface {
 
 // SameStep is a mutating step that does nothing.
 type SameStep struct {
-	plan *Plan                 // the current plan.
-	reg  RegisterResourceEvent // the registration intent to convey a URN back to.
-	old  *resource.State       // the state of the resource before this step.
-	new  *resource.State       // the state of the resource after this step.
+	plan          *Plan                 // the current plan.
+	reg           RegisterResourceEvent // the registration intent to convey a URN back to.
+	old           *resource.State       // the state of the resource before this step.
+	new           *resource.State       // the state of the resource after this step.
+	skippedCreate bool                  // true if this is a create that was skipped because it was not targeted.
 }
 
 var _ Step = (*SameStep)(nil)
@@ -78,21 +79,43 @@ func NewSameStep(plan *Plan, reg RegisterResourceEvent, old *resource.State, new
 	}
 }
 
+// NewSkippedCreateStep produces a SameStep for a resource that does not yet exist but was not targeted by the current
+// update. No resource is created: the step merely completes the resource's registration so that the program can
+// proceed. The resulting state is never recorded in the snapshot.
+func NewSkippedCreateStep(plan *Plan, reg RegisterResourceEvent, new *resource.State) Step {
+	contract.Assert(reg != nil)
+	contract.Assert(new != nil)
+	contract.Assert(new.URN != "")
+	contract.Assert(new.ID == "")
+	contract.Assert(!new.Delete)
+	return &SameStep{
+		plan:          plan,
+		reg:           reg,
+		new:           new,
+		skippedCreate: true,
+	}
+}
+
 func (s *SameStep) Op() StepOp           { return OpSame }
 func (s *SameStep) Plan() *Plan          { return s.plan }
-func (s *SameStep) Type() tokens.Type    { return s.old.Type }
-func (s *SameStep) Provider() string     { return s.old.Provider }
-func (s *SameStep) URN() resource.URN    { return s.old.URN }
+func (s *SameStep) Type() tokens.Type    { return s.new.Type }
+func (s *SameStep) Provider() string     { return s.new.Provider }
+func (s *SameStep) URN() resource.URN    { return s.new.URN }
 func (s *SameStep) Old() *resource.State { return s.old }
 func (s *SameStep) New() *resource.State { return s.new }
 func (s *SameStep) Res() *resource.State { return s.new }
 func (s *SameStep) Logical() bool        { return true }
 
+// IsSkippedCreate returns true if this step stands in for the creation of an untargeted resource.
+func (s *SameStep) IsSkippedCreate() bool { return s.skippedCreate }
+
 func (s *SameStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
-	// Retain the URN, ID, and outputs:
-	s.new.URN = s.old.URN
-	s.new.ID = s.old.ID
-	s.new.Outputs = s.old.Outputs
+	// Retain the URN, ID, and outputs (unless we skipped creating the resource, in which case there are none):
+	if !s.skippedCreate {
+		s.new.URN = s.old.URN
+		s.new.ID = s.old.ID
+		s.new.Outputs = s.old.Outputs
+	}
 	complete := func() { s.reg.Done(&RegisterResult{State: s.new, Stable: true}) }
 	return resource.StatusOK, complete, nil
 }
@@ -104,6 +127,7 @@ type CreateStep struct {
 	old           *resource.State        // the state of the existing resource (only for replacements).
 	new           *resource.State        // the state of the resource after this step.
 	keys          []resource.PropertyKey // the keys causing replacement (only for replacements).
+	forced        bool                   // true if the replacement was forced by the user (only for replacements).
 	replacing     bool                   // true if this is a create due to a replacement.
 	pendingDelete bool                   // true if this replacement should create a pending delete.
 }
@@ -126,7 +150,7 @@ func NewCreateStep(plan *Plan, reg RegisterResourceEvent, new *resource.State) S
 }
 
 func NewCreateReplacementStep(plan *Plan, reg RegisterResourceEvent,
-	old *resource.State, new *resource.State, keys []resource.PropertyKey, pendingDelete bool) Step {
+	old *resource.State, new *resource.State, keys []resource.PropertyKey, forced bool, pendingDelete bool) Step {
 	contract.Assert(reg != nil)
 	contract.Assert(old != nil)
 	contract.Assert(old.URN != "")
@@ -145,6 +169,7 @@ func NewCreateReplacementStep(plan *Plan, reg RegisterResourceEvent,
 		old:           old,
 		new:           new,
 		keys:          keys,
+		forced:        forced,
 		replacing:     true,
 		pendingDelete: pendingDelete,
 	}
@@ -164,6 +189,7 @@ func (s *CreateStep) Old() *resource.State         { return s.old }
 func (s *CreateStep) New() *resource.State         { return s.new }
 func (s *CreateStep) Res() *resource.State         { return s.new }
 func (s *CreateStep) Keys() []resource.PropertyKey { return s.keys }
+func (s *CreateStep) Forced() bool                 { return s.forced }
 func (s *CreateStep) Logical() bool                { return !s.replacing }
 
 func (s *CreateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
@@ -379,13 +405,14 @@ type ReplaceStep struct {
 	old           *resource.State        // the state of the existing resource.
 	new           *resource.State        // the new state snapshot.
 	keys          []resource.PropertyKey // the keys causing replacement.
+	forced        bool                   // true if the replacement was forced by the user.
 	pendingDelete bool                   // true if a pending deletion should happen.
 }
 
 var _ Step = (*ReplaceStep)(nil)
 
 func NewReplaceStep(plan *Plan, old *resource.State, new *resource.State,
-	keys []resource.PropertyKey, pendingDelete bool) Step {
+	keys []resource.PropertyKey, forced bool, pendingDelete bool) Step {
 	contract.Assert(old != nil)
 	contract.Assert(old.URN != "")
 	contract.Assert(old.ID != "" || !old.Custom)
@@ -399,6 +426,7 @@ func NewReplaceStep(plan *Plan, old *resource.State, new *resource.State,
 		old:           old,
 		new:           new,
 		keys:          keys,
+		forced:        forced,
 		pendingDelete: pendingDelete,
 	}
 }
@@ -412,6 +440,7 @@ func (s *ReplaceStep) Old() *resource.State         { return s.old }
 func (s *ReplaceStep) New() *resource.State         { return s.new }
 func (s *ReplaceStep) Res() *resource.State         { return s.new }
 func (s *ReplaceStep) Keys() []resource.PropertyKey { return s.keys }
+func (s *ReplaceStep) Forced() bool                 { return s.forced }
 func (s *ReplaceStep) Logical() bool                { return true }
 
 func (s *ReplaceStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
@@ -538,6 +567,129 @@ func (s *ReadStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error
 	return resourceStatus, complete, resourceError
 }
 
+// ImportStep is a mutating step that imports an existing resource into the stack. Unlike a ReadStep, the resulting
+// resource is owned by Pulumi: it is not marked "External", and it will be updated and deleted like any other resource
+// that the program manages. Before the resource is recorded, its live state is checked against the inputs supplied by
+// the program; if they differ, the import fails rather than adopting a resource that the next update would change.
+//
+// Like a ReadStep, an ImportStep may replace an existing resource with the same URN. This happens if the program
+// imports a resource in place of one that was previously read.
+type ImportStep struct {
+	plan      *Plan                 // the current plan.
+	reg       RegisterResourceEvent // the registration intent to convey a URN back to.
+	old       *resource.State       // the state of the existing resource (only for replacements).
+	new       *resource.State       // the state of the resource after this step.
+	replacing bool                  // true if this is an import due to a replacement.
+}
+
+var _ Step = (*ImportStep)(nil)
+
+func NewImportStep(plan *Plan, reg RegisterResourceEvent, new *resource.State) Step {
+	contract.Assert(reg != nil)
+	contract.Assert(new != nil)
+	contract.Assert(new.URN != "")
+	contract.Assert(new.ID != "")
+	contract.Assertf(new.Custom, "target of Import step must be Custom")
+	contract.Assert(new.Provider != "")
+	contract.Assert(!new.Delete)
+	contract.Assert(!new.External)
+	return &ImportStep{
+		plan: plan,
+		reg:  reg,
+		new:  new,
+	}
+}
+
+func NewImportReplacementStep(plan *Plan, reg RegisterResourceEvent, old *resource.State, new *resource.State) Step {
+	contract.Assert(reg != nil)
+	contract.Assert(old != nil)
+	contract.Assert(old.URN != "")
+	contract.Assert(old.ID != "")
+	contract.Assert(!old.Delete)
+	contract.Assert(new != nil)
+	contract.Assert(new.URN != "")
+	contract.Assert(new.ID != "")
+	contract.Assertf(new.Custom, "target of ImportReplacement step must be Custom")
+	contract.Assert(new.Provider != "")
+	contract.Assert(!new.Delete)
+	contract.Assert(!new.External)
+	contract.Assert(old.Type == new.Type)
+	return &ImportStep{
+		plan:      plan,
+		reg:       reg,
+		old:       old,
+		new:       new,
+		replacing: true,
+	}
+}
+
+func (s *ImportStep) Op() StepOp {
+	if s.replacing {
+		return OpImportReplacement
+	}
+	return OpImport
+}
+func (s *ImportStep) Plan() *Plan          { return s.plan }
+func (s *ImportStep) Type() tokens.Type    { return s.new.Type }
+func (s *ImportStep) Provider() string     { return s.new.Provider }
+func (s *ImportStep) URN() resource.URN    { return s.new.URN }
+func (s *ImportStep) Old() *resource.State { return s.old }
+func (s *ImportStep) New() *resource.State { return s.new }
+func (s *ImportStep) Res() *resource.State { return s.new }
+func (s *ImportStep) Logical() bool        { return !s.replacing }
+
+func (s *ImportStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
+	// Like reads, imports do not modify the resource, so the live state is read even during previews. This gives the
+	// user an early warning if the resource does not exist or does not match the program.
+	prov, err := getProvider(s)
+	if err != nil {
+		return resource.StatusOK, nil, err
+	}
+	outputs, rst, err := prov.Read(s.new.URN, s.new.ID, nil)
+	if err != nil {
+		return rst, nil, err
+	}
+	if outputs == nil {
+		return resource.StatusOK, nil, errors.Errorf("resource '%v' does not exist", s.new.ID)
+	}
+
+	// Ensure that the program's inputs describe the resource as it exists today.
+	diff, err := prov.Diff(s.new.URN, s.new.ID, outputs, s.new.Inputs, preview)
+	if err != nil {
+		return resource.StatusOK, nil, err
+	}
+	mismatched := importMismatches(s.new.Inputs, outputs)
+	if diff.Changes == plugin.DiffSome || (diff.Changes == plugin.DiffUnknown && len(mismatched) > 0) {
+		return resource.StatusOK, nil, errors.Errorf(
+			"inputs to import do not match the existing resource; importing it would change %v", mismatched)
+	}
+	s.new.Outputs = outputs
+
+	// If we were asked to replace an existing resource, pend its deletion here.
+	if s.replacing {
+		s.old.Delete = true
+	}
+
+	complete := func() { s.reg.Done(&RegisterResult{State: s.new}) }
+	return resource.StatusOK, complete, nil
+}
+
+// importMismatches returns the sorted list of input keys whose values differ from the live state of a resource that
+// is being imported. Unknown inputs are never considered mismatched.
+func importMismatches(inputs, outputs resource.PropertyMap) []resource.PropertyKey {
+	var keys []resource.PropertyKey
+	for _, k := range inputs.StableKeys() {
+		v := inputs[k]
+		if v.ContainsUnknowns() {
+			continue
+		}
+		if o, has := outputs[k]; !has || !v.DeepEquals(o) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
 // StepOp represents the kind of operation performed by a step.  It evaluates to its string label.
 type StepOp string
 
@@ -551,6 +703,8 @@ const (
 	OpDeleteReplaced    StepOp = "delete-replaced"    // deleting an existing resource after replacement.
 	OpRead              StepOp = "read"               // reading an existing resource.
 	OpReadReplacement   StepOp = "read-replacement"   // reading an existing resource for a replacement.
+	OpImport            StepOp = "import"             // importing an existing resource.
+	OpImportReplacement StepOp = "import-replacement" // importing an existing resource for a replacement.
 )
 
 // StepOps contains the full set of step operation types.
@@ -564,6 +718,8 @@ var StepOps = []StepOp{
 	OpDeleteReplaced,
 	OpRead,
 	OpReadReplacement,
+	OpImport,
+	OpImportReplacement,
 }
 
 // Color returns a suggested color for lines of this op type.
@@ -587,6 +743,10 @@ func (op StepOp) Color() string {
 		return colors.SpecCreate
 	case OpReadReplacement:
 		return colors.SpecReplace
+	case OpImport:
+		return colors.SpecCreate
+	case OpImportReplacement:
+		return colors.SpecReplace
 	default:
 		contract.Failf("Unrecognized resource step op: '%v'", op)
 		return ""
@@ -619,6 +779,10 @@ func (op StepOp) RawPrefix() string {
 		return ">-"
 	case OpReadReplacement:
 		return ">~"
+	case OpImport:
+		return "= "
+	case OpImportReplacement:
+		return "=>"
 	default:
 		contract.Failf("Unrecognized resource step op: %v", op)
 		return ""
@@ -631,6 +795,10 @@ func (op StepOp) PastTense() string {
 		return string(op) + "d"
 	case OpRead:
 		return "read"
+	case OpImport:
+		return "imported"
+	case OpImportReplacement:
+		return "import-replaced"
 	default:
 		contract.Failf("Unexpected resource step op: %v", op)
 		return ""
@@ -639,7 +807,8 @@ func (op StepOp) PastTense() string {
 
 // Suffix returns a suggested suffix for lines of this op type.
 func (op StepOp) Suffix() string {
-	if op == OpCreateReplacement || op == OpUpdate || op == OpReplace || op == OpReadReplacement {
+	if op == OpCreateReplacement || op == OpUpdate || op == OpReplace || op == OpReadReplacement ||
+		op == OpImportReplacement {
 		return colors.Reset // updates and replacements colorize individual lines; get has none
 	}
 	return ""
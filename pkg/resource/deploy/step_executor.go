@@ -44,6 +44,30 @@ var (
 // A Chain is a sequence of Steps that must be executed in the given order.
 type Chain = []Step
 
+// An Antichain is a set of Steps that are independent of one another and may be executed in any order, including in
+// parallel.
+type Antichain = []Step
+
+// A completionToken is returned when a Chain or Antichain is submitted for execution; it can be used to wait until
+// every step that was submitted has finished executing.
+type completionToken struct {
+	done chan bool
+}
+
+// Wait blocks until the steps associated with this token have finished executing or the given context is canceled.
+func (c completionToken) Wait(ctx context.Context) {
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+	}
+}
+
+// An incomingChain is a Chain submitted for execution, along with a channel to close once it has finished executing.
+type incomingChain struct {
+	Chain Chain
+	Done  chan bool
+}
+
 // stepExecutor is the component of the engine responsible for taking steps and executing
 // them, possibly in parallel if requested. The step generator operates on the granularity
 // of "chains", which are sequences of steps that must be executed exactly in the given order.
@@ -57,8 +81,8 @@ type stepExecutor struct {
 	preview     bool     // Whether or not we are doing a preview.
 	pendingNews sync.Map // Resources that have been created but are pending a RegisterResourceOutputs.
 
-	workers        sync.WaitGroup // WaitGroup tracking the worker goroutines that are owned by this step executor.
-	incomingChains chan Chain     // Incoming chains that we are to execute
+	workers        sync.WaitGroup     // WaitGroup tracking the worker goroutines that are owned by this step executor.
+	incomingChains chan incomingChain // Incoming chains that we are to execute
 
 	ctx      context.Context    // cancellation context for the current plan.
 	cancel   context.CancelFunc // CancelFunc that cancels the above context.
@@ -72,14 +96,40 @@ type stepExecutor struct {
 //
 
 // Execute submits a Chain for asynchronous execution. The execution of the chain will begin as soon as there
-// is a worker available to execute it.
-func (se *stepExecutor) Execute(chain Chain) {
+// is a worker available to execute it. The returned token may be used to wait for the chain to finish executing.
+func (se *stepExecutor) Execute(chain Chain) completionToken {
+	done := make(chan bool)
+
 	// The select here is to avoid blocking on a send to se.incomingChains if a cancellation is pending.
 	// If one is pending, we should exit early - we will shortly be tearing down the engine and exiting.
 	select {
-	case se.incomingChains <- chain:
+	case se.incomingChains <- incomingChain{Chain: chain, Done: done}:
 	case <-se.ctx.Done():
 	}
+
+	return completionToken{done: done}
+}
+
+// ExecuteParallel submits an Antichain for asynchronous execution. Each step in the antichain is executed as its own
+// chain, so up to the step executor's degree of parallelism may execute at once. The returned token may be used to
+// wait for every step in the antichain to finish executing.
+func (se *stepExecutor) ExecuteParallel(antichain Antichain) completionToken {
+	var wg sync.WaitGroup
+	for _, step := range antichain {
+		token := se.Execute(Chain{step})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token.Wait(se.ctx)
+		}()
+	}
+
+	done := make(chan bool)
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return completionToken{done: done}
 }
 
 // ExecuteRegisterResourceOutputs services a RegisterResourceOutputsEvent synchronously on the calling goroutine.
@@ -269,14 +319,15 @@ func (se *stepExecutor) worker(workerID int) {
 	for {
 		se.log(workerID, "worker waiting for incoming chains")
 		select {
-		case chain := <-se.incomingChains:
-			if chain == nil {
-				se.log(workerID, "worker received nil chain, exiting")
+		case chain, ok := <-se.incomingChains:
+			if !ok {
+				se.log(workerID, "worker observed closed chain channel, exiting")
 				return
 			}
 
 			se.log(workerID, "worker received chain for execution")
-			se.executeChain(workerID, chain)
+			se.executeChain(workerID, chain.Chain)
+			close(chain.Done)
 		case <-se.ctx.Done():
 			se.log(workerID, "worker exiting due to cancellation")
 			return
@@ -290,7 +341,7 @@ func newStepExecutor(ctx context.Context, cancel context.CancelFunc, plan *Plan,
 		plan:           plan,
 		opts:           opts,
 		preview:        preview,
-		incomingChains: make(chan Chain),
+		incomingChains: make(chan incomingChain),
 		ctx:            ctx,
 		cancel:         cancel,
 	}
@@ -15,14 +15,18 @@
 package deploy
 
 import (
+	"fmt"
+
 	"github.com/pkg/errors"
 
 	"github.com/pulumi/pulumi/pkg/diag"
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/resource/plugin"
+	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/util/logging"
+	"github.com/pulumi/pulumi/pkg/workspace"
 )
 
 // stepGenerator is responsible for turning resource events into steps that
@@ -40,6 +44,17 @@ type stepGenerator struct {
 	updates  map[resource.URN]bool // set of URNs updated in this plan
 	creates  map[resource.URN]bool // set of URNs created in this plan
 	sames    map[resource.URN]bool // set of URNs that were not changed in this plan
+	imports  map[resource.URN]bool // set of URNs imported in this plan
+
+	targets        map[resource.URN]bool // set of registered URNs targeted by this plan
+	skippedChanges map[resource.URN]bool // set of untargeted URNs whose pending changes were not applied
+
+	requestedImports map[int]resource.URN // the URN registered for each of the plan's imports, by index
+
+	goals     map[resource.URN]*resource.State // the new state produced for each resource registered or read
+	goalOrder []resource.URN                   // the URNs in goals, in the order in which they were produced
+
+	policyViolations []PolicyViolation // the policy violations reported by analyzers during this plan
 }
 
 // GenerateReadSteps is responsible for producing one or more steps required to service
@@ -58,7 +73,8 @@ func (sg *stepGenerator) GenerateReadSteps(event ReadResourceEvent) ([]Step, err
 		true,  /*external*/
 		event.Dependencies(),
 		nil, /* initErrors */
-		event.Provider())
+		event.Provider(),
+		nil /* ignoreChanges */)
 	old, hasOld := sg.plan.Olds()[urn]
 
 	// If the snapshot has an old resource for this URN and it's not external, we're going
@@ -80,7 +96,7 @@ func (sg *stepGenerator) GenerateReadSteps(event ReadResourceEvent) ([]Step, err
 		sg.replaces[urn] = true
 		return []Step{
 			NewReadReplacementStep(sg.plan, event, old, newState),
-			NewReplaceStep(sg.plan, old, newState, nil, true),
+			NewReplaceStep(sg.plan, old, newState, nil, false, true),
 		}, nil
 	}
 
@@ -126,6 +142,17 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 	// get serialized into the checkpoint file.  Normally there are no outputs, unless this is a refresh.
 	props, inputs, outputs, new := sg.getResourcePropertyStates(urn, goal)
 
+	// If the program asked us to ignore changes to some of this resource's properties, carry their old values over
+	// into the new inputs before the provider checks and diffs them.
+	if hasOld && !old.External && !sg.plan.IsRefresh() && len(goal.IgnoreChanges) > 0 {
+		ignoredInputs, ignoreErr := processIgnoreChanges(inputs, oldInputs, goal.IgnoreChanges)
+		if ignoreErr != nil {
+			sg.plan.Diag().Errorf(diag.GetResourceInvalidError(urn), goal.Type, urn.Name(), ignoreErr)
+			return nil, errors.New("One or more resources have invalid ignoreChanges; refusing to proceed")
+		}
+		props, inputs, new.Inputs = ignoredInputs, ignoredInputs, ignoredInputs
+	}
+
 	// Fetch the provider for this resource type, assuming it isn't just a logical one.
 	var prov plugin.Provider
 	var err error
@@ -161,6 +188,19 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 	// We may be creating this resource if it previously existed in the snapshot as an External resource
 	wasExternal := hasOld && old.External
 
+	// We may be importing this resource if the program or the plan asked us to adopt an existing resource that this
+	// stack does not already manage. A resource that is already managed under a different ID must be removed from
+	// the stack's state before another resource can be imported in its place.
+	importID, err := sg.getImportID(urn, goal)
+	if err != nil {
+		return nil, err
+	}
+	importing := importID != "" && (!hasOld || wasExternal)
+	if importID != "" && hasOld && !wasExternal && old.ID != importID {
+		sg.plan.Diag().Errorf(diag.GetImportAlreadyManagedError(urn), urn, old.ID, importID)
+		return nil, errors.New("One or more resources are already managed under a different ID; refusing to proceed")
+	}
+
 	// If this isn't a refresh, ensure the provider is okay with this resource and fetch the inputs to pass to
 	// subsequent methods.  If these are not inputs, we are just going to blindly store the outputs, so skip this.
 	// Note that we must always run `Check` for resource providers: the provider registry uses `Check` to load the
@@ -171,7 +211,7 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 		// If we are re-creating this resource because it was deleted earlier, the old inputs are now
 		// invalid (they got deleted) so don't consider them. Similarly, if the old resource was External,
 		// don't consider those inputs since Pulumi does not own them.
-		if recreating || wasExternal {
+		if recreating || wasExternal || importing {
 			inputs, failures, err = prov.Check(urn, nil, goal.Properties, allowUnknowns)
 		} else {
 			inputs, failures, err = prov.Check(urn, oldInputs, inputs, allowUnknowns)
@@ -189,11 +229,8 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 	// Next, give each analyzer -- if any -- a chance to inspect the resource too.
 	for _, a := range sg.plan.analyzers {
 		var analyzer plugin.Analyzer
-		analyzer, err = sg.plan.ctx.Host.Analyzer(a)
-		if err != nil {
+		if analyzer, err = sg.loadAnalyzer(a); err != nil {
 			return nil, err
-		} else if analyzer == nil {
-			return nil, errors.Errorf("analyzer '%v' could not be loaded from your $PATH", a)
 		}
 		var failures []plugin.AnalyzeFailure
 		failures, err = analyzer.Analyze(new.Type, props)
@@ -201,9 +238,9 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 			return nil, err
 		}
 		for _, failure := range failures {
-			invalid = true
-			sg.plan.Diag().Errorf(
-				diag.GetAnalyzeResourceFailureError(urn), a, urn, failure.Property, failure.Reason)
+			if sg.reportAnalyzeFailure(a, urn, failure, diag.GetAnalyzeResourceFailureError) {
+				invalid = true
+			}
 		}
 	}
 
@@ -212,6 +249,32 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 		return nil, errors.New("One or more resource validation errors occurred; refusing to proceed")
 	}
 
+	// If this plan is restricted to a subset of resources and this resource is not one of them, leave it as it is.
+	// Otherwise, make sure that it does not depend on any untargeted changes.
+	dependencies := resourceDependencies(goal.Parent, goal.Dependencies, goal.Provider)
+	if !sg.isTargeted(urn, dependencies) {
+		return sg.generateUntargetedSteps(event, old, new, prov, allowUnknowns)
+	}
+	if err = sg.checkTargetedDependencies(urn, dependencies); err != nil {
+		return nil, err
+	}
+
+	// If we are importing this resource, read its live state rather than creating it. If the resource already exists
+	// in the snapshot because it was read, the import replaces it, and the external resource is dropped from the
+	// snapshot at the end of the plan.
+	if importing {
+		logging.V(7).Infof("Planner decided to import '%v' (id=%v, inputs=%v)", urn, importID, new.Inputs)
+		sg.imports[urn] = true
+		new.ID = importID
+		if hasOld {
+			return []Step{
+				NewImportReplacementStep(sg.plan, event, old, new),
+				NewReplaceStep(sg.plan, old, new, nil, false, true),
+			}, nil
+		}
+		return []Step{NewImportStep(sg.plan, event, new)}, nil
+	}
+
 	// There are four cases we need to consider when figuring out what to do with this resource.
 	//
 	// Case 1: recreating
@@ -235,8 +298,8 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 		delete(sg.deletes, urn)
 		sg.replaces[urn] = true
 		return []Step{
-			NewReplaceStep(sg.plan, old, new, nil, false),
-			NewCreateReplacementStep(sg.plan, event, old, new, nil, false),
+			NewReplaceStep(sg.plan, old, new, nil, false, false),
+			NewCreateReplacementStep(sg.plan, event, old, new, nil, false, false),
 		}, nil
 	}
 
@@ -256,8 +319,8 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 		}
 
 		return []Step{
-			NewCreateReplacementStep(sg.plan, event, old, new, nil, true),
-			NewReplaceStep(sg.plan, old, new, nil, true),
+			NewCreateReplacementStep(sg.plan, event, old, new, nil, false, true),
+			NewReplaceStep(sg.plan, old, new, nil, false, true),
 		}, nil
 	}
 
@@ -292,9 +355,24 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 				"unrecognized diff state for %s: %d", urn, diff.Changes)
 		}
 
+		// If the user asked for this resource to be replaced, do so regardless of what the diff says. The diff may have
+		// been computed without consulting the resource's provider (e.g. if its inputs did not change), so ask the
+		// provider for its own diff in that case in order to honor its preference for delete-before-replace.
+		forced := !refresh && sg.opts.ReplaceTargets != nil && sg.opts.ReplaceTargets(urn)
+		if forced {
+			logging.V(7).Infof("Planner forcing replacement of '%v'", urn)
+			if diff.Changes == plugin.DiffNone && prov != nil {
+				d, diffErr := prov.Diff(urn, old.ID, oldOutputs, props, allowUnknowns)
+				if diffErr != nil {
+					return nil, diffErr
+				}
+				diff.DeleteBeforeReplace = d.DeleteBeforeReplace
+			}
+		}
+
 		// If there were changes, check for a replacement vs. an in-place update.
-		if diff.Changes == plugin.DiffSome {
-			if diff.Replace() {
+		if diff.Changes == plugin.DiffSome || forced {
+			if diff.Replace() || forced {
 				sg.replaces[urn] = true
 
 				// If we are going to perform a replacement, we need to recompute the default values.  The above logic
@@ -327,7 +405,8 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 				//       until pulumi/pulumi#624 is resolved, we cannot safely perform this operation on resources
 				//       that have dependent resources (we try to delete the resource while they refer to it).
 				//
-				// The provider is responsible for requesting which of these two modes to use.
+				// The provider is responsible for requesting which of these two modes to use, including for
+				// replacements that were forced by the user.
 
 				if diff.DeleteBeforeReplace {
 					logging.V(7).Infof("Planner decided to delete-before-replacement for resource '%v'", urn)
@@ -352,6 +431,15 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 							continue
 						}
 
+						// If this plan is restricted to a subset of resources, we may only delete dependents that are
+						// themselves targeted.
+						if sg.opts.Targets != nil && !sg.opts.TargetDependents && !sg.opts.Targets(dependentResource.URN) {
+							sg.plan.Diag().Errorf(diag.GetTargetedResourceHasUntargetedDependentError(urn),
+								urn, dependentResource.URN)
+							return nil, errors.New("One or more targeted resources have untargeted dependents; " +
+								"refusing to proceed")
+						}
+
 						logging.V(7).Infof("Planner decided to delete '%v' due to dependence on condemned resource '%v'",
 							dependentResource.URN, urn)
 
@@ -363,14 +451,14 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 
 					return append(steps,
 						NewDeleteReplacementStep(sg.plan, old, false),
-						NewReplaceStep(sg.plan, old, new, diff.ReplaceKeys, false),
-						NewCreateReplacementStep(sg.plan, event, old, new, diff.ReplaceKeys, false),
+						NewReplaceStep(sg.plan, old, new, diff.ReplaceKeys, forced, false),
+						NewCreateReplacementStep(sg.plan, event, old, new, diff.ReplaceKeys, forced, false),
 					), nil
 				}
 
 				return []Step{
-					NewCreateReplacementStep(sg.plan, event, old, new, diff.ReplaceKeys, true),
-					NewReplaceStep(sg.plan, old, new, diff.ReplaceKeys, true),
+					NewCreateReplacementStep(sg.plan, event, old, new, diff.ReplaceKeys, forced, true),
+					NewReplaceStep(sg.plan, old, new, diff.ReplaceKeys, forced, true),
 					// note that the delete step is generated "later" on, after all creates/updates finish.
 				}, nil
 			}
@@ -399,49 +487,345 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 	return []Step{NewCreateStep(sg.plan, event, new)}, nil
 }
 
-func (sg *stepGenerator) GenerateDeletes() []Step {
+// generateUntargetedSteps produces the steps for a resource that is not targeted by the current plan. An existing
+// resource is left exactly as it was in the old snapshot, and a new resource is not created at all. If the resource
+// has pending changes, it is recorded so that any targeted resources that depend upon it can refuse to proceed.
+func (sg *stepGenerator) generateUntargetedSteps(event RegisterResourceEvent, old, new *resource.State,
+	prov plugin.Provider, allowUnknowns bool) ([]Step, error) {
+
+	urn := new.URN
+	sg.sames[urn] = true
+
+	if old == nil {
+		logging.V(7).Infof("Planner skipped creating untargeted resource '%v'", urn)
+		sg.skippedChanges[urn] = true
+		return []Step{NewSkippedCreateStep(sg.plan, event, new)}, nil
+	}
+
+	// Determine whether or not we are skipping any changes to this resource.
+	hasChanges := old.External || old.Provider != new.Provider
+	if !hasChanges {
+		diff, err := sg.diff(urn, old.ID, old.Inputs, old.Outputs, new.Inputs, nil, new.Inputs, prov, false,
+			allowUnknowns)
+		if err != nil {
+			return nil, err
+		}
+		hasChanges = diff.Changes == plugin.DiffSome
+	}
+	if hasChanges {
+		logging.V(7).Infof("Planner skipped changes to untargeted resource '%v'", urn)
+		sg.skippedChanges[urn] = true
+	}
+
+	// Carry the old state over verbatim.
+	same := resource.NewState(old.Type, urn, old.Custom, false, "", old.Inputs, nil, old.Parent, old.Protect,
+		old.External, old.Dependencies, old.InitErrors, old.Provider, old.IgnoreChanges)
+	return []Step{NewSameStep(sg.plan, event, old, same)}, nil
+}
+
+// isTargeted returns true if the resource with the given URN and dependencies is targeted by the current plan. Every
+// resource is targeted if the plan is not restricted to a subset of resources. Otherwise, a resource is targeted if
+// it matches the plan's target filter, if it must be recreated, or--if dependents are targeted--if any of its
+// dependencies is targeted. Default providers are implicitly owned by the resources that use them, so they are always
+// targeted, but they do not cause their dependents to be targeted.
+func (sg *stepGenerator) isTargeted(urn resource.URN, dependencies []resource.URN) bool {
+	if sg.opts.Targets != nil && isDefaultProvider(urn) {
+		return true
+	}
+
+	targeted := sg.opts.Targets == nil || sg.opts.Targets(urn) || sg.deletes[urn]
+	if !targeted && sg.opts.TargetDependents {
+		for _, dep := range dependencies {
+			if sg.targets[dep] {
+				targeted = true
+				break
+			}
+		}
+	}
+	if targeted {
+		sg.targets[urn] = true
+	}
+	return targeted
+}
+
+// checkTargetedDependencies ensures that none of the given dependencies of a targeted resource were left untouched
+// despite having pending changes. Proceeding in that case would leave the targeted resource depending upon state that
+// this plan has refused to apply.
+func (sg *stepGenerator) checkTargetedDependencies(urn resource.URN, dependencies []resource.URN) error {
+	for _, dep := range dependencies {
+		if sg.skippedChanges[dep] {
+			sg.plan.Diag().Errorf(diag.GetTargetedResourceDependsOnUntargetedChangeError(urn), urn, dep)
+			return errors.New("One or more targeted resources depend on untargeted changes; refusing to proceed")
+		}
+	}
+	return nil
+}
+
+// getImportID returns the ID of the existing resource that the given registration should import, if any. An ID
+// supplied by the program takes precedence over any import requested for the plan as a whole.
+func (sg *stepGenerator) getImportID(urn resource.URN, goal *resource.Goal) (resource.ID, error) {
+	id := goal.ID
+	for i, imp := range sg.opts.Imports {
+		if imp.Type != goal.Type || imp.Name != goal.Name {
+			continue
+		}
+		if other, has := sg.requestedImports[i]; has {
+			sg.plan.Diag().Errorf(diag.GetAmbiguousImportError(urn), other, urn, imp.Type, imp.Name)
+			return "", errors.New("One or more imports are ambiguous; refusing to proceed")
+		}
+		sg.requestedImports[i] = urn
+		if id == "" {
+			id = imp.ID
+		}
+	}
+	if id != "" && (!goal.Custom || providers.IsProviderType(goal.Type)) {
+		sg.plan.Diag().Errorf(diag.GetResourceCannotBeImportedError(urn), urn)
+		return "", errors.New("One or more resources cannot be imported; refusing to proceed")
+	}
+	return id, nil
+}
+
+// CheckImports ensures that every import requested for this plan matched a resource registered by the program. It
+// must be called once all resource registrations have been processed.
+func (sg *stepGenerator) CheckImports() error {
+	var missing bool
+	for i, imp := range sg.opts.Imports {
+		if _, has := sg.requestedImports[i]; !has {
+			sg.plan.Diag().Errorf(diag.GetImportedResourceNotRegisteredError(""), imp.Type, imp.Name)
+			missing = true
+		}
+	}
+	if missing {
+		return errors.New("One or more resources to import were not registered; refusing to proceed")
+	}
+	return nil
+}
+
+// RecordGoals remembers the new states produced by a chain of steps, so that the final state of every resource
+// registered or read by this plan can be analyzed once the plan has finished.
+func (sg *stepGenerator) RecordGoals(steps []Step) {
+	for _, step := range steps {
+		// Skipped creates stand in for resources that will not exist once the plan has finished.
+		if same, ok := step.(*SameStep); ok && same.IsSkippedCreate() {
+			continue
+		}
+		if new := step.New(); new != nil {
+			if _, has := sg.goals[new.URN]; !has {
+				sg.goalOrder = append(sg.goalOrder, new.URN)
+			}
+			sg.goals[new.URN] = new
+		}
+	}
+}
+
+// AnalyzeStack gives each analyzer -- if any -- a chance to inspect the final states of all of the resources
+// registered or read by this plan. It must be called once all of the plan's steps have completed, and returns an
+// error if any analyzer reported a failure.
+func (sg *stepGenerator) AnalyzeStack() error {
+	if len(sg.plan.analyzers) == 0 {
+		return nil
+	}
+
+	states := make([]*resource.State, len(sg.goalOrder))
+	for i, urn := range sg.goalOrder {
+		states[i] = sg.goals[urn]
+	}
+	resources := AnalyzerResources(states)
+
+	var invalid bool
+	for _, a := range sg.plan.analyzers {
+		analyzer, err := sg.loadAnalyzer(a)
+		if err != nil {
+			return err
+		}
+		failures, err := analyzer.AnalyzeStack(resources)
+		if err != nil {
+			return err
+		}
+		for _, failure := range failures {
+			if sg.reportAnalyzeFailure(a, failure.URN, failure, diag.GetAnalyzeStackFailureError) {
+				invalid = true
+			}
+		}
+	}
+	if invalid {
+		return errors.New("One or more stack validation errors occurred")
+	}
+	return nil
+}
+
+// reportAnalyzeFailure reports a failure returned by an analyzer according to the enforcement level of the policy that
+// it violates, taking any overrides from the plan's options into account. Mandatory failures are reported using the
+// given diagnostic and advisory failures as warnings; failures of disabled policies are ignored. It returns true if the
+// failure is mandatory, and must therefore fail the plan.
+func (sg *stepGenerator) reportAnalyzeFailure(analyzer tokens.QName, urn resource.URN, failure plugin.AnalyzeFailure,
+	mandatory func(urn resource.URN) *diag.Diag) bool {
+
+	failure.URN = urn
+	failure.EnforcementLevel = sg.opts.Policy.EnforcementLevel(failure.PolicyName, failure.EnforcementLevel)
+	if failure.EnforcementLevel == workspace.Disabled {
+		return false
+	}
+	sg.policyViolations = append(sg.policyViolations, PolicyViolation{Analyzer: analyzer, AnalyzeFailure: failure})
+
+	// Prefix the reason with the policy (and its severity) that was violated, if the analyzer told us which it was.
+	reason := failure.Reason
+	if failure.PolicyName != "" {
+		policy := failure.PolicyName
+		if failure.Severity != "" {
+			policy = fmt.Sprintf("%s (%s severity)", policy, failure.Severity)
+		}
+		reason = fmt.Sprintf("%s: %s", policy, reason)
+	}
+
+	if failure.EnforcementLevel == workspace.Advisory {
+		sg.plan.Diag().Warningf(
+			diag.GetAnalyzeAdvisoryViolationWarning(urn), analyzer, urn, failure.Property, reason)
+		return false
+	}
+	sg.plan.Diag().Errorf(mandatory(urn), analyzer, urn, failure.Property, reason)
+	return true
+}
+
+// loadAnalyzer loads the analyzer plugin with the given name.
+func (sg *stepGenerator) loadAnalyzer(name tokens.QName) (plugin.Analyzer, error) {
+	analyzer, err := sg.plan.ctx.Host.Analyzer(name)
+	if err != nil {
+		return nil, err
+	} else if analyzer == nil {
+		return nil, errors.Errorf("analyzer '%v' could not be loaded from your $PATH", name)
+	}
+	return analyzer, nil
+}
+
+// GenerateDeletes produces the steps required to delete any old resources that were not registered by this plan. If
+// the plan is restricted to a subset of resources, only targeted resources are deleted; an error is returned if this
+// would delete a resource upon which an untargeted resource still depends.
+func (sg *stepGenerator) GenerateDeletes() ([]Step, error) {
+	prev := sg.plan.prev
+	if prev == nil {
+		return nil, nil
+	}
+
+	// If this plan is restricted to a subset of resources, compute the set of old resources that are targeted. The
+	// old resources are stored in dependency order, so a single pass suffices to find any targeted dependents.
+	var targets map[resource.URN]bool
+	if sg.opts.Targets != nil {
+		targets = make(map[resource.URN]bool)
+		for _, res := range prev.Resources {
+			if sg.targets[res.URN] || sg.opts.Targets(res.URN) {
+				targets[res.URN] = true
+			} else if sg.opts.TargetDependents {
+				for _, dep := range resourceDependencies(res.Parent, res.Dependencies, res.Provider) {
+					if targets[dep] {
+						targets[res.URN] = true
+						break
+					}
+				}
+			}
+		}
+	}
+
 	// To compute the deletion list, we must walk the list of old resources *backwards*.  This is because the list is
 	// stored in dependency order, and earlier elements are possibly leaf nodes for later elements.  We must not delete
 	// dependencies prior to their dependent nodes.
 	var dels []Step
-	if prev := sg.plan.prev; prev != nil {
-		for i := len(prev.Resources) - 1; i >= 0; i-- {
-			// If this resource is explicitly marked for deletion or wasn't seen at all, delete it.
-			res := prev.Resources[i]
-			if res.Delete {
-				logging.V(7).Infof("Planner decided to delete '%v' due to replacement", res.URN)
-				// The below assert is commented-out because it's believed to be wrong.
-				//
-				// The original justification for this assert is that the author (swgillespie) believed that
-				// it was impossible for a single URN to be deleted multiple times in the same program.
-				// This has empirically been proven to be false - it is possible using today engine to construct
-				// a series of actions that puts arbitrarily many pending delete resources with the same URN in
-				// the snapshot.
-				//
-				// It is not clear whether or not this is OK. I (swgillespie), the author of this comment, have
-				// seen no evidence that it is *not* OK. However, concerns were raised about what this means for
-				// structural resources, and so until that question is answered, I am leaving this comment and
-				// assert in the code.
-				//
-				// Regardless, it is better to admit strange behavior in corner cases than it is to crash the CLI
-				// whenever we see multiple deletes for the same URN.
-				// contract.Assert(!sg.deletes[res.URN])
-				if sg.deletes[res.URN] {
-					logging.V(7).Infof(
-						"Planner is deleting pending-delete urn '%v' that has already been deleted", res.URN)
+	for i := len(prev.Resources) - 1; i >= 0; i-- {
+		// If this resource is explicitly marked for deletion or wasn't seen at all, delete it.
+		res := prev.Resources[i]
+		if targets != nil && !targets[res.URN] {
+			logging.V(7).Infof("Planner skipped deleting untargeted resource '%v'", res.URN)
+			continue
+		}
+		if res.Delete {
+			logging.V(7).Infof("Planner decided to delete '%v' due to replacement", res.URN)
+			// The below assert is commented-out because it's believed to be wrong.
+			//
+			// The original justification for this assert is that the author (swgillespie) believed that
+			// it was impossible for a single URN to be deleted multiple times in the same program.
+			// This has empirically been proven to be false - it is possible using today engine to construct
+			// a series of actions that puts arbitrarily many pending delete resources with the same URN in
+			// the snapshot.
+			//
+			// It is not clear whether or not this is OK. I (swgillespie), the author of this comment, have
+			// seen no evidence that it is *not* OK. However, concerns were raised about what this means for
+			// structural resources, and so until that question is answered, I am leaving this comment and
+			// assert in the code.
+			//
+			// Regardless, it is better to admit strange behavior in corner cases than it is to crash the CLI
+			// whenever we see multiple deletes for the same URN.
+			// contract.Assert(!sg.deletes[res.URN])
+			if sg.deletes[res.URN] {
+				logging.V(7).Infof(
+					"Planner is deleting pending-delete urn '%v' that has already been deleted", res.URN)
+			}
+			sg.deletes[res.URN] = true
+			dels = append(dels, NewDeleteReplacementStep(sg.plan, res, true))
+		} else if !sg.sames[res.URN] && !sg.updates[res.URN] && !sg.replaces[res.URN] && !sg.reads[res.URN] {
+			// NOTE: we deliberately do not check sg.deletes here, as it is possible for us to issue multiple
+			// delete steps for the same URN if the old checkpoint contained pending deletes.
+			logging.V(7).Infof("Planner decided to delete '%v'", res.URN)
+			sg.deletes[res.URN] = true
+			dels = append(dels, NewDeleteStep(sg.plan, res))
+		}
+	}
+
+	// If this plan is restricted to a subset of resources, ensure that no untargeted resource that survives the plan
+	// depends upon a resource that we are about to delete.
+	if targets != nil {
+		for _, res := range prev.Resources {
+			if res.Delete || sg.deletes[res.URN] || sg.targets[res.URN] {
+				continue
+			}
+			for _, dep := range resourceDependencies(res.Parent, res.Dependencies, res.Provider) {
+				if sg.deletes[dep] {
+					sg.plan.Diag().Errorf(diag.GetTargetedResourceHasUntargetedDependentError(dep), dep, res.URN)
+					return nil, errors.New("One or more targeted resources have untargeted dependents; " +
+						"refusing to proceed")
 				}
-				sg.deletes[res.URN] = true
-				dels = append(dels, NewDeleteReplacementStep(sg.plan, res, true))
-			} else if !sg.sames[res.URN] && !sg.updates[res.URN] && !sg.replaces[res.URN] && !sg.reads[res.URN] {
-				// NOTE: we deliberately do not check sg.deletes here, as it is possible for us to issue multiple
-				// delete steps for the same URN if the old checkpoint contained pending deletes.
-				logging.V(7).Infof("Planner decided to delete '%v'", res.URN)
-				sg.deletes[res.URN] = true
-				dels = append(dels, NewDeleteStep(sg.plan, res))
 			}
 		}
 	}
-	return dels
+
+	return dels, nil
+}
+
+// ScheduleDeletes divides the given delete steps, as produced by GenerateDeletes, into a sequence of antichains. The
+// steps within each antichain are independent of one another and may execute in parallel; each antichain must not
+// begin to execute until every antichain that precedes it has finished. This ensures that no resource is deleted
+// before every resource that depends upon it is gone.
+func (sg *stepGenerator) ScheduleDeletes(deleteSteps []Step) []Antichain {
+	if len(deleteSteps) == 0 {
+		return nil
+	}
+	contract.Assert(sg.plan.depGraph != nil)
+
+	// Delete steps are produced in reverse dependency order, so by the time we visit a step, every step that deletes
+	// one of its dependents has already been visited. Each step is placed into the antichain that follows the latest
+	// antichain of any of its dependents.
+	condemned := make(map[*resource.State]bool)
+	for _, step := range deleteSteps {
+		condemned[step.Old()] = true
+	}
+
+	levels := make(map[*resource.State]int)
+	var antichains []Antichain
+	for _, step := range deleteSteps {
+		old := step.Old()
+		level := levels[old]
+		for _, dep := range sg.plan.depGraph.DependenciesOf(old) {
+			if condemned[dep] && levels[dep] < level+1 {
+				levels[dep] = level + 1
+			}
+		}
+
+		for len(antichains) <= level {
+			antichains = append(antichains, nil)
+		}
+		antichains[level] = append(antichains[level], step)
+	}
+
+	return antichains
 }
 
 // diff returns a DiffResult for the given resource.
@@ -502,7 +886,56 @@ func (sg *stepGenerator) getResourcePropertyStates(urn resource.URN, goal *resou
 	}
 	return props, inputs, outputs,
 		resource.NewState(goal.Type, urn, goal.Custom, false, "",
-			inputs, outputs, goal.Parent, goal.Protect, false, goal.Dependencies, goal.InitErrors, goal.Provider)
+			inputs, outputs, goal.Parent, goal.Protect, false, goal.Dependencies, goal.InitErrors, goal.Provider,
+			goal.IgnoreChanges)
+}
+
+// processIgnoreChanges returns a copy of the given inputs in which the value of each property named by ignoreChanges
+// has been replaced by its value in the old inputs. Properties that are absent from the old inputs are removed.
+func processIgnoreChanges(inputs, oldInputs resource.PropertyMap,
+	ignoreChanges []string) (resource.PropertyMap, error) {
+
+	ignoredInputs := resource.NewObjectProperty(inputs.DeepCopy())
+	olds := resource.NewObjectProperty(oldInputs)
+	for _, ignoreChange := range ignoreChanges {
+		path, err := resource.ParsePropertyPath(ignoreChange)
+		if err != nil {
+			return nil, err
+		}
+
+		// Restore the old value if there was one, and otherwise drop the new value if there is one. A path that is
+		// absent from both the old and the new inputs has no changes to ignore.
+		if old, hasOld := path.Get(olds); hasOld {
+			if !path.Set(ignoredInputs, old.DeepCopy()) {
+				return nil, errors.Errorf("cannot ignore changes to '%v': the new inputs have no such property",
+					ignoreChange)
+			}
+		} else if _, hasNew := path.Get(ignoredInputs); hasNew {
+			path.Delete(ignoredInputs)
+		}
+	}
+	return ignoredInputs.ObjectValue(), nil
+}
+
+// resourceDependencies returns the URNs of every resource upon which a resource with the given parent, dependencies,
+// and provider reference depends.
+func resourceDependencies(parent resource.URN, dependencies []resource.URN, provider string) []resource.URN {
+	var deps []resource.URN
+	if parent != "" {
+		deps = append(deps, parent)
+	}
+	deps = append(deps, dependencies...)
+	if provider != "" {
+		if ref, err := providers.ParseReference(provider); err == nil {
+			deps = append(deps, ref.URN())
+		}
+	}
+	return deps
+}
+
+// isDefaultProvider returns true if the given URN refers to a default provider.
+func isDefaultProvider(urn resource.URN) bool {
+	return providers.IsProviderType(urn.Type()) && urn.Name() == "default"
 }
 
 // issueCheckErrors prints any check errors to the diagnostics sink.
@@ -525,7 +958,7 @@ func (sg *stepGenerator) issueCheckErrors(new *resource.State, urn resource.URN,
 }
 
 func (sg *stepGenerator) Steps() int {
-	return len(sg.Creates()) + len(sg.Updates()) + len(sg.Replaces()) + len(sg.Deletes())
+	return len(sg.Creates()) + len(sg.Updates()) + len(sg.Replaces()) + len(sg.Deletes()) + len(sg.Imports())
 }
 
 func (sg *stepGenerator) Creates() map[resource.URN]bool  { return sg.creates }
@@ -533,6 +966,9 @@ func (sg *stepGenerator) Sames() map[resource.URN]bool    { return sg.sames }
 func (sg *stepGenerator) Updates() map[resource.URN]bool  { return sg.updates }
 func (sg *stepGenerator) Replaces() map[resource.URN]bool { return sg.replaces }
 func (sg *stepGenerator) Deletes() map[resource.URN]bool  { return sg.deletes }
+func (sg *stepGenerator) Imports() map[resource.URN]bool  { return sg.imports }
+
+func (sg *stepGenerator) PolicyViolations() []PolicyViolation { return sg.policyViolations }
 
 // newStepGenerator creates a new step generator that operates on the given plan.
 func newStepGenerator(plan *Plan, opts Options) *stepGenerator {
@@ -546,5 +982,13 @@ func newStepGenerator(plan *Plan, opts Options) *stepGenerator {
 		replaces: make(map[resource.URN]bool),
 		updates:  make(map[resource.URN]bool),
 		deletes:  make(map[resource.URN]bool),
+		imports:  make(map[resource.URN]bool),
+
+		targets:        make(map[resource.URN]bool),
+		skippedChanges: make(map[resource.URN]bool),
+
+		requestedImports: make(map[int]resource.URN),
+
+		goals: make(map[resource.URN]*resource.State),
 	}
 }
@@ -0,0 +1,186 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package edit contains operations that directly modify the resources recorded in a snapshot, for use when a stack's
+// state must be repaired by hand (e.g. because a resource was deleted out-of-band).
+package edit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/resource/graph"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// ResourceHasDependenciesError is returned by DeleteResource if a resource cannot be deleted because other resources
+// depend upon it.
+type ResourceHasDependenciesError struct {
+	URN        resource.URN      // the URN of the resource that was to be deleted.
+	Dependents []*resource.State // the resources that depend upon it.
+}
+
+func (e ResourceHasDependenciesError) Error() string {
+	urns := make([]string, len(e.Dependents))
+	for i, dep := range e.Dependents {
+		urns[i] = string(dep.URN)
+	}
+	return fmt.Sprintf("resource '%v' cannot be deleted because the following resources depend on it: %v",
+		e.URN, strings.Join(urns, ", "))
+}
+
+// ResourceProtectedError is returned by DeleteResource if a resource cannot be deleted because it is protected.
+type ResourceProtectedError struct {
+	URN resource.URN // the URN of the resource that was to be deleted.
+}
+
+func (e ResourceProtectedError) Error() string {
+	return fmt.Sprintf("resource '%v' cannot be deleted because it is protected", e.URN)
+}
+
+// LocateResource returns every resource in the snapshot with the given URN. More than one resource may share a URN
+// if some of them are pending deletion.
+func LocateResource(snap *deploy.Snapshot, urn resource.URN) []*resource.State {
+	if snap == nil {
+		return nil
+	}
+
+	var resources []*resource.State
+	for _, res := range snap.Resources {
+		if res.URN == urn {
+			resources = append(resources, res)
+		}
+	}
+	return resources
+}
+
+// DeleteResource removes every resource with the given URN from the snapshot. Resources that are protected or upon
+// which other resources depend are not deleted unless force is true; when forced, dependents are rewritten so that
+// they no longer refer to the deleted resource, and children are reparented to the deleted resource's parent. A
+// provider that is still in use by other resources may never be deleted.
+func DeleteResource(snap *deploy.Snapshot, urn resource.URN, force bool) error {
+	contract.Require(snap != nil, "snap")
+
+	condemned := LocateResource(snap, urn)
+	if len(condemned) == 0 {
+		return errors.Errorf("no resource named '%v' found", urn)
+	}
+
+	// Find the resources that refer to the condemned resource. These are the resources that depend upon it directly,
+	// that use it as their provider, or that are its children.
+	dg := graph.NewDependencyGraph(snap.Resources)
+	isCondemned := make(map[*resource.State]bool)
+	for _, res := range condemned {
+		isCondemned[res] = true
+	}
+	var dependents []*resource.State
+	seen := make(map[*resource.State]bool)
+	for _, res := range condemned {
+		for _, candidate := range snap.Resources {
+			if isCondemned[candidate] || seen[candidate] {
+				continue
+			}
+			for _, dep := range dg.DependenciesOf(candidate) {
+				if dep == res {
+					dependents = append(dependents, candidate)
+					seen[candidate] = true
+					break
+				}
+			}
+		}
+	}
+
+	if !force {
+		for _, res := range condemned {
+			if res.Protect {
+				return ResourceProtectedError{URN: urn}
+			}
+		}
+		if len(dependents) > 0 {
+			return ResourceHasDependenciesError{URN: urn, Dependents: dependents}
+		}
+	}
+
+	// A resource cannot be rewritten to stop using its provider, so a provider that is in use may never be deleted.
+	for _, dependent := range dependents {
+		if dependent.Provider != "" {
+			ref, err := providers.ParseReference(dependent.Provider)
+			contract.Assert(err == nil)
+			if ref.URN() == urn {
+				return errors.Errorf("provider '%v' cannot be deleted because resource '%v' uses it",
+					urn, dependent.URN)
+			}
+		}
+	}
+
+	// Rewrite any dependents so that they no longer refer to the condemned resource.
+	parent := condemned[0].Parent
+	for _, dependent := range dependents {
+		if dependent.Parent == urn {
+			dependent.Parent = parent
+		}
+		var deps []resource.URN
+		for _, dep := range dependent.Dependencies {
+			if dep != urn {
+				deps = append(deps, dep)
+			}
+		}
+		dependent.Dependencies = deps
+	}
+
+	// Finally, remove the condemned resources and any pending operations that refer to them.
+	var resources []*resource.State
+	for _, res := range snap.Resources {
+		if !isCondemned[res] {
+			resources = append(resources, res)
+		}
+	}
+	snap.Resources = resources
+
+	var ops []resource.Operation
+	for _, op := range snap.PendingOperations {
+		if op.Resource.URN != urn {
+			ops = append(ops, op)
+		}
+	}
+	snap.PendingOperations = ops
+
+	return nil
+}
+
+// UnprotectResource clears the protect flag of every resource with the given URN.
+func UnprotectResource(snap *deploy.Snapshot, urn resource.URN) error {
+	contract.Require(snap != nil, "snap")
+
+	resources := LocateResource(snap, urn)
+	if len(resources) == 0 {
+		return errors.Errorf("no resource named '%v' found", urn)
+	}
+	for _, res := range resources {
+		res.Protect = false
+	}
+	return nil
+}
+
+// ClearPendingOperations removes every pending operation from the snapshot. Any resource that was being created,
+// updated, or deleted when the operation was interrupted must then be reconciled by hand (e.g. with a refresh).
+func ClearPendingOperations(snap *deploy.Snapshot) {
+	contract.Require(snap != nil, "snap")
+	snap.PendingOperations = nil
+}
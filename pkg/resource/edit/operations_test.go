@@ -0,0 +1,203 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/version"
+)
+
+func newResource(name string, provider *resource.State, deps ...resource.URN) *resource.State {
+	prov := ""
+	if provider != nil {
+		p, err := providers.NewReference(provider.URN, provider.ID)
+		if err != nil {
+			panic(err)
+		}
+		prov = p.String()
+	}
+
+	t := tokens.Type("a:b:c")
+	return &resource.State{
+		Type:         t,
+		URN:          resource.NewURN("test", "test", "", t, tokens.QName(name)),
+		Inputs:       resource.PropertyMap{},
+		Outputs:      resource.PropertyMap{},
+		Dependencies: deps,
+		Provider:     prov,
+	}
+}
+
+func newProviderResource(pkg, name, id string, deps ...resource.URN) *resource.State {
+	t := providers.MakeProviderType(tokens.Package(pkg))
+	return &resource.State{
+		Type:         t,
+		URN:          resource.NewURN("test", "test", "", t, tokens.QName(name)),
+		ID:           resource.ID(id),
+		Inputs:       resource.PropertyMap{},
+		Outputs:      resource.PropertyMap{},
+		Dependencies: deps,
+		Custom:       true,
+	}
+}
+
+func newSnapshot(resources []*resource.State, ops []resource.Operation) *deploy.Snapshot {
+	snap := deploy.NewSnapshot(deploy.Manifest{
+		Time:    time.Now(),
+		Version: version.Version,
+		Plugins: nil,
+	}, resources, ops)
+	snap.Manifest.Magic = snap.Manifest.NewMagic()
+	return snap
+}
+
+func TestDeletion(t *testing.T) {
+	pA := newProviderResource("a", "p1", "0")
+	a := newResource("a", pA)
+	b := newResource("b", pA)
+	c := newResource("c", pA)
+	snap := newSnapshot([]*resource.State{pA, a, b, c}, nil)
+
+	err := DeleteResource(snap, b.URN, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []*resource.State{pA, a, c}, snap.Resources)
+	assert.NoError(t, snap.VerifyIntegrity())
+}
+
+func TestFailedDeletionProviderDependency(t *testing.T) {
+	pA := newProviderResource("a", "p1", "0")
+	a := newResource("a", pA)
+	snap := newSnapshot([]*resource.State{pA, a}, nil)
+
+	err := DeleteResource(snap, pA.URN, false)
+	assert.Error(t, err)
+	depErr, ok := err.(ResourceHasDependenciesError)
+	if assert.True(t, ok) {
+		assert.Equal(t, []*resource.State{a}, depErr.Dependents)
+	}
+
+	// A provider that is in use may not be deleted even when forced.
+	err = DeleteResource(snap, pA.URN, true)
+	assert.Error(t, err)
+	assert.Equal(t, []*resource.State{pA, a}, snap.Resources)
+}
+
+func TestFailedDeletionRegularDependency(t *testing.T) {
+	pA := newProviderResource("a", "p1", "0")
+	a := newResource("a", pA)
+	b := newResource("b", pA, a.URN)
+	c := newResource("c", pA)
+	snap := newSnapshot([]*resource.State{pA, a, b, c}, nil)
+
+	err := DeleteResource(snap, a.URN, false)
+	assert.Error(t, err)
+	depErr, ok := err.(ResourceHasDependenciesError)
+	if assert.True(t, ok) {
+		assert.Equal(t, []*resource.State{b}, depErr.Dependents)
+	}
+	assert.Equal(t, []*resource.State{pA, a, b, c}, snap.Resources)
+}
+
+func TestForcedDeletionRegularDependency(t *testing.T) {
+	pA := newProviderResource("a", "p1", "0")
+	a := newResource("a", pA)
+	b := newResource("b", pA, a.URN)
+	c := newResource("c", pA, a.URN)
+	c.Parent = a.URN
+	snap := newSnapshot([]*resource.State{pA, a, b, c}, nil)
+
+	err := DeleteResource(snap, a.URN, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*resource.State{pA, b, c}, snap.Resources)
+	assert.Empty(t, b.Dependencies)
+	assert.Empty(t, c.Dependencies)
+	assert.Equal(t, resource.URN(""), c.Parent)
+	assert.NoError(t, snap.VerifyIntegrity())
+}
+
+func TestFailedDeletionProtected(t *testing.T) {
+	pA := newProviderResource("a", "p1", "0")
+	a := newResource("a", pA)
+	a.Protect = true
+	snap := newSnapshot([]*resource.State{pA, a}, nil)
+
+	err := DeleteResource(snap, a.URN, false)
+	assert.Error(t, err)
+	_, ok := err.(ResourceProtectedError)
+	assert.True(t, ok)
+
+	err = DeleteResource(snap, a.URN, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*resource.State{pA}, snap.Resources)
+}
+
+func TestDeletionPendingOperations(t *testing.T) {
+	pA := newProviderResource("a", "p1", "0")
+	a := newResource("a", pA)
+	b := newResource("b", pA)
+	snap := newSnapshot([]*resource.State{pA, a, b}, []resource.Operation{
+		resource.NewOperation(a, resource.OperationTypeUpdating),
+		resource.NewOperation(b, resource.OperationTypeUpdating),
+	})
+
+	err := DeleteResource(snap, a.URN, false)
+	assert.NoError(t, err)
+	if assert.Len(t, snap.PendingOperations, 1) {
+		assert.Equal(t, b, snap.PendingOperations[0].Resource)
+	}
+}
+
+func TestDeletionMissingResource(t *testing.T) {
+	pA := newProviderResource("a", "p1", "0")
+	snap := newSnapshot([]*resource.State{pA}, nil)
+
+	err := DeleteResource(snap, resource.URN("urn:pulumi:test::test::a:b:c::missing"), false)
+	assert.Error(t, err)
+}
+
+func TestUnprotectResource(t *testing.T) {
+	pA := newProviderResource("a", "p1", "0")
+	a := newResource("a", pA)
+	a.Protect = true
+	b := newResource("b", pA)
+	b.Protect = true
+	snap := newSnapshot([]*resource.State{pA, a, b}, nil)
+
+	err := UnprotectResource(snap, a.URN)
+	assert.NoError(t, err)
+	assert.False(t, a.Protect)
+	assert.True(t, b.Protect)
+	assert.NoError(t, snap.VerifyIntegrity())
+}
+
+func TestClearPendingOperations(t *testing.T) {
+	pA := newProviderResource("a", "p1", "0")
+	a := newResource("a", pA)
+	snap := newSnapshot([]*resource.State{pA, a}, []resource.Operation{
+		resource.NewOperation(a, resource.OperationTypeDeleting),
+	})
+
+	ClearPendingOperations(snap)
+	assert.Empty(t, snap.PendingOperations)
+	assert.Equal(t, []*resource.State{pA, a}, snap.Resources)
+}
@@ -69,6 +69,40 @@ func (dg *DependencyGraph) DependingOn(res *resource.State) []*resource.State {
 	return dependents
 }
 
+// DependenciesOf returns a slice containing all resources upon which the given resource directly depends: its
+// parent, its provider, and its explicit dependencies. If more than one resource in the graph shares one of these
+// URNs (e.g. because some of them are pending deletion), all of them are returned. The returned slice is in
+// topological order with respect to the snapshot dependency graph.
+//
+// The time complexity of DependenciesOf is linear with respect to the number of resources.
+func (dg *DependencyGraph) DependenciesOf(res *resource.State) []*resource.State {
+	cursorIndex, ok := dg.index[res]
+	contract.Assert(ok)
+
+	dependencySet := make(map[resource.URN]bool)
+	if res.Parent != "" {
+		dependencySet[res.Parent] = true
+	}
+	for _, dependency := range res.Dependencies {
+		dependencySet[dependency] = true
+	}
+	if res.Provider != "" {
+		ref, err := providers.ParseReference(res.Provider)
+		contract.Assert(err == nil)
+		dependencySet[ref.URN()] = true
+	}
+
+	// Because snapshots are stored in a valid topological order, all of a resource's dependencies must precede it.
+	var dependencies []*resource.State
+	for i := 0; i < cursorIndex; i++ {
+		if candidate := dg.resources[i]; dependencySet[candidate.URN] {
+			dependencies = append(dependencies, candidate)
+		}
+	}
+
+	return dependencies
+}
+
 // NewDependencyGraph creates a new DependencyGraph from a list of resources.
 // The resources should be in topological order with respect to their dependencies.
 func NewDependencyGraph(resources []*resource.State) *DependencyGraph {
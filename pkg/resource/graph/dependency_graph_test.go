@@ -99,3 +99,29 @@ func TestGraphNoDuplicates(t *testing.T) {
 		b, c, d,
 	}, dg.DependingOn(a))
 }
+
+func TestDependenciesOf(t *testing.T) {
+	pA := NewProviderResource("test", "pA", "0")
+	a := NewResource("a", pA)
+	b := NewResource("b", pA, a.URN)
+	c := NewResource("c", nil, a.URN, b.URN)
+	c.Parent = b.URN
+	aPending := NewResource("a", pA)
+	aPending.Delete = true
+	d := NewResource("d", nil, a.URN)
+
+	dg := NewDependencyGraph([]*resource.State{
+		pA,
+		a,
+		b,
+		c,
+		aPending,
+		d,
+	})
+
+	assert.Nil(t, dg.DependenciesOf(pA))
+	assert.Equal(t, []*resource.State{pA}, dg.DependenciesOf(a))
+	assert.Equal(t, []*resource.State{pA, a}, dg.DependenciesOf(b))
+	assert.Equal(t, []*resource.State{a, b}, dg.DependenciesOf(c))
+	assert.Equal(t, []*resource.State{a, aPending}, dg.DependenciesOf(d))
+}
@@ -32,12 +32,31 @@ type Analyzer interface {
 	Name() tokens.QName
 	// Analyze analyzes a single resource object, and returns any errors that it finds.
 	Analyze(t tokens.Type, props resource.PropertyMap) ([]AnalyzeFailure, error)
+	// AnalyzeStack analyzes all resources within a stack, once the plan has produced all of their final states, and
+	// returns any errors that it finds.  The resources are given in dependency order.
+	AnalyzeStack(resources []AnalyzerResource) ([]AnalyzeFailure, error)
 	// GetPluginInfo returns this plugin's information.
 	GetPluginInfo() (workspace.PluginInfo, error)
 }
 
-// AnalyzeFailure indicates that resource analysis failed; it contains the property and reason for the failure.
+// AnalyzerResource describes a single resource in a stack that is being analyzed with AnalyzeStack.
+type AnalyzerResource struct {
+	URN          resource.URN         // the resource's URN.
+	Type         tokens.Type          // the resource's type token.
+	Properties   resource.PropertyMap // the resource's properties (its outputs, if they are known).
+	Parent       resource.URN         // the resource's optional parent URN.
+	Dependencies []resource.URN       // the URNs of the resources this resource depends on.
+	Provider     string               // the resource's optional provider reference.
+}
+
+// AnalyzeFailure indicates that resource analysis failed; it contains the property and reason for the failure, along
+// with the policy that was violated and how that violation should be enforced.
 type AnalyzeFailure struct {
-	Property resource.PropertyKey // the property that failed the analysis.
-	Reason   string               // the reason the property failed the analysis.
+	URN              resource.URN               // the resource that failed the analysis (or "" if general or not known).
+	Property         resource.PropertyKey       // the property that failed the analysis.
+	Reason           string                     // the reason the property failed the analysis.
+	PolicyName       string                     // the name of the policy that was violated, if any.
+	Description      string                     // a description of the policy that was violated, if any.
+	Severity         string                     // the severity of the violation (e.g. "low" or "high"), if any.
+	EnforcementLevel workspace.EnforcementLevel // how the violation should be enforced (mandatory if empty).
 }
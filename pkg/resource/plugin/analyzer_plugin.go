@@ -20,6 +20,7 @@ import (
 
 	"github.com/blang/semver"
 	pbempty "github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
 
 	"github.com/pulumi/pulumi/pkg/resource"
 	"github.com/pulumi/pulumi/pkg/tokens"
@@ -93,17 +94,90 @@ func (a *analyzer) Analyze(t tokens.Type, props resource.PropertyMap) ([]Analyze
 		return nil, rpcError
 	}
 
-	var failures []AnalyzeFailure
-	for _, failure := range resp.GetFailures() {
-		failures = append(failures, AnalyzeFailure{
-			Property: resource.PropertyKey(failure.Property),
-			Reason:   failure.Reason,
+	failures := convertFailures(resp.GetFailures())
+	logging.V(7).Infof("%s success: failures=#%d", label, len(failures))
+	return failures, nil
+}
+
+// AnalyzeStack analyzes all resources within a stack, and returns any errors that it finds.
+func (a *analyzer) AnalyzeStack(resources []AnalyzerResource) ([]AnalyzeFailure, error) {
+	label := fmt.Sprintf("%s.AnalyzeStack()", a.label())
+	logging.V(7).Infof("%s executing (#resources=%d)", label, len(resources))
+
+	var protoResources []*pulumirpc.AnalyzerResource
+	for _, res := range resources {
+		mprops, err := MarshalProperties(res.Properties, MarshalOptions{
+			Label: fmt.Sprintf("%s.resource(%s)", label, res.URN),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var deps []string
+		for _, dep := range res.Dependencies {
+			deps = append(deps, string(dep))
+		}
+
+		protoResources = append(protoResources, &pulumirpc.AnalyzerResource{
+			Urn:          string(res.URN),
+			Type:         string(res.Type),
+			Properties:   mprops,
+			Parent:       string(res.Parent),
+			Dependencies: deps,
+			Provider:     res.Provider,
 		})
 	}
+
+	resp, err := a.client.AnalyzeStack(a.ctx.Request(), &pulumirpc.AnalyzeStackRequest{
+		Resources: protoResources,
+	})
+	if err != nil {
+		rpcError := rpcerror.Convert(err)
+		logging.V(7).Infof("%s failed: err=%v", label, rpcError)
+
+		// Older analyzers predate the AnalyzeStack method and have no stack-wide checks to run, so there is nothing
+		// for them to report.
+		if rpcError.Code() == codes.Unimplemented {
+			return nil, nil
+		}
+
+		return nil, rpcError
+	}
+
+	failures := convertFailures(resp.GetFailures())
 	logging.V(7).Infof("%s success: failures=#%d", label, len(failures))
 	return failures, nil
 }
 
+// convertFailures converts the failures in an analyzer RPC response into their engine representation.
+func convertFailures(protoFailures []*pulumirpc.AnalyzeFailure) []AnalyzeFailure {
+	var failures []AnalyzeFailure
+	for _, failure := range protoFailures {
+		failures = append(failures, AnalyzeFailure{
+			URN:              resource.URN(failure.Urn),
+			Property:         resource.PropertyKey(failure.Property),
+			Reason:           failure.Reason,
+			PolicyName:       failure.PolicyName,
+			Description:      failure.Description,
+			Severity:         failure.Severity,
+			EnforcementLevel: convertEnforcementLevel(failure.EnforcementLevel),
+		})
+	}
+	return failures
+}
+
+// convertEnforcementLevel converts an enforcement level in an analyzer RPC response into its engine representation.
+func convertEnforcementLevel(level pulumirpc.EnforcementLevel) workspace.EnforcementLevel {
+	switch level {
+	case pulumirpc.EnforcementLevel_ADVISORY:
+		return workspace.Advisory
+	case pulumirpc.EnforcementLevel_DISABLED:
+		return workspace.Disabled
+	default:
+		return workspace.Mandatory
+	}
+}
+
 // GetPluginInfo returns this plugin's information.
 func (a *analyzer) GetPluginInfo() (workspace.PluginInfo, error) {
 	label := fmt.Sprintf("%s.GetPluginInfo()", a.label())
@@ -84,9 +84,12 @@ func (p *provider) label() string {
 
 // CheckConfig validates the configuration for this resource provider.
 func (p *provider) CheckConfig(olds, news resource.PropertyMap) (resource.PropertyMap, []CheckFailure, error) {
-	// Ensure that all config values are strings or unknowns.
+	// Ensure that all config values are strings or unknowns, which may be secret.
 	var failures []CheckFailure
 	for k, v := range news {
+		if v.IsSecret() {
+			v = v.SecretValue().Element
+		}
 		if !v.IsString() && !v.IsComputed() {
 			failures = append(failures, CheckFailure{
 				Property: k,
@@ -131,6 +134,10 @@ func (p *provider) DiffConfig(olds, news resource.PropertyMap) (DiffResult, erro
 
 	var replaceKeys []resource.PropertyKey
 	for k, v := range news {
+		if v.IsSecret() {
+			v = v.SecretValue().Element
+		}
+
 		// These are ensured during Check().
 		contract.Assert(v.IsString() || v.IsComputed())
 
@@ -172,6 +179,9 @@ func (p *provider) Configure(inputs resource.PropertyMap) error {
 		if k == "version" {
 			continue
 		}
+		if v.IsSecret() {
+			v = v.SecretValue().Element
+		}
 		switch {
 		case v.IsComputed():
 			p.cfgknown = false
@@ -249,10 +259,12 @@ func (p *provider) Check(urn resource.URN,
 	var inputs resource.PropertyMap
 	if ins := resp.GetInputs(); ins != nil {
 		inputs, err = UnmarshalProperties(ins, MarshalOptions{
-			Label: fmt.Sprintf("%s.inputs", label), KeepUnknowns: allowUnknowns, RejectUnknowns: !allowUnknowns})
+			Label: fmt.Sprintf("%s.inputs", label), KeepUnknowns: allowUnknowns, RejectUnknowns: !allowUnknowns,
+			KeepSecrets: true})
 		if err != nil {
 			return nil, nil, err
 		}
+		annotateSecrets(inputs, news)
 	}
 
 	// And now any properties that failed verification.
@@ -380,10 +392,11 @@ func (p *provider) Create(urn resource.URN, props resource.PropertyMap) (resourc
 	}
 
 	outs, err := UnmarshalProperties(liveObject, MarshalOptions{
-		Label: fmt.Sprintf("%s.outputs", label), RejectUnknowns: true})
+		Label: fmt.Sprintf("%s.outputs", label), RejectUnknowns: true, KeepSecrets: true})
 	if err != nil {
 		return "", nil, resourceStatus, err
 	}
+	annotateSecrets(outs, props)
 
 	logging.V(7).Infof("%s success: id=%s; #outs=%d", label, id, len(outs))
 	if resourceError == nil {
@@ -453,10 +466,11 @@ func (p *provider) Read(
 
 	// Finally, unmarshal the resulting state properties and return them.
 	results, err := UnmarshalProperties(liveObject, MarshalOptions{
-		Label: fmt.Sprintf("%s.outputs", label), RejectUnknowns: true})
+		Label: fmt.Sprintf("%s.outputs", label), RejectUnknowns: true, KeepSecrets: true})
 	if err != nil {
 		return nil, resourceStatus, err
 	}
+	annotateSecrets(results, props)
 
 	logging.V(7).Infof("%s success; #outs=%d", label, len(results))
 	return results, resourceStatus, resourceError
@@ -514,10 +528,11 @@ func (p *provider) Update(urn resource.URN, id resource.ID,
 	}
 
 	outs, err := UnmarshalProperties(liveObject, MarshalOptions{
-		Label: fmt.Sprintf("%s.outputs", label), RejectUnknowns: true})
+		Label: fmt.Sprintf("%s.outputs", label), RejectUnknowns: true, KeepSecrets: true})
 	if err != nil {
 		return nil, resourceStatus, err
 	}
+	annotateSecrets(outs, news)
 
 	logging.V(7).Infof("%s success; #outs=%d", label, len(outs))
 	if resourceError == nil {
@@ -756,3 +771,25 @@ func (ie *InitError) Error() string {
 	}
 	return err.Error()
 }
+
+// annotateSecrets copies the secretness of the given inputs to the given outputs, as providers receive the underlying
+// values of secrets and so cannot be relied upon to return them as secrets. Where an input and an output of the same
+// name are both objects, their properties are annotated recursively; otherwise, if the input contains a secret, the
+// entire output is marked as secret.
+func annotateSecrets(outs, ins resource.PropertyMap) {
+	if outs == nil || ins == nil {
+		return
+	}
+
+	for key, in := range ins {
+		out, has := outs[key]
+		if !has || out.IsSecret() || !in.ContainsSecrets() {
+			continue
+		}
+		if in.IsObject() && out.IsObject() {
+			annotateSecrets(out.ObjectValue(), in.ObjectValue())
+		} else {
+			outs[key] = resource.MakeSecret(out)
+		}
+	}
+}
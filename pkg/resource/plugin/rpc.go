@@ -34,6 +34,7 @@ type MarshalOptions struct {
 	RejectUnknowns     bool   // true if we should return errors on unknown values. Takes precedence over KeepUnknowns.
 	ElideAssetContents bool   // true if we are eliding the contents of assets.
 	ComputeAssetHashes bool   // true if we are computing missing asset hashes on the fly.
+	KeepSecrets        bool   // true if we are keeping secrets (otherwise we replace them with their underlying value).
 }
 
 const (
@@ -140,6 +141,23 @@ func MarshalPropertyValue(v resource.PropertyValue, opts MarshalOptions) (*struc
 			return marshalUnknownProperty(v.OutputValue().Element, opts), nil
 		}
 		return nil, nil // return nil and the caller will ignore it.
+	} else if v.IsSecret() {
+		// Secrets are sent as objects carrying the secret signature, so the other end can recover their secretness.
+		// Peers that do not understand secrets simply receive the underlying value.
+		if !opts.KeepSecrets {
+			logging.V(5).Infof("marshalling secret value as raw value as opts.KeepSecrets is false")
+			return MarshalPropertyValue(v.SecretValue().Element, opts)
+		}
+		elem, err := MarshalPropertyValue(v.SecretValue().Element, opts)
+		if err != nil || elem == nil {
+			return elem, err
+		}
+		return MarshalStruct(&structpb.Struct{
+			Fields: map[string]*structpb.Value{
+				string(resource.SigKey): MarshalString(resource.SecretSig, opts),
+				"value":                 elem,
+			},
+		}, opts), nil
 	}
 
 	contract.Failf("Unrecognized property value in RPC[%s]: %v (type=%v)", opts.Label, v.V, reflect.TypeOf(v.V))
@@ -170,6 +188,8 @@ func marshalUnknownProperty(elem resource.PropertyValue, opts MarshalOptions) *s
 		return marshalUnknownProperty(elem.Input().Element, opts)
 	} else if elem.IsOutput() {
 		return marshalUnknownProperty(elem.OutputValue().Element, opts)
+	} else if elem.IsSecret() {
+		return marshalUnknownProperty(elem.SecretValue().Element, opts)
 	}
 
 	// Finally, if a null, we can guess its value!  (the one and only...)
@@ -266,6 +286,18 @@ func UnmarshalPropertyValue(v *structpb.Value, opts MarshalOptions) (*resource.P
 		}
 
 		// Before returning it as an object, check to see if it's a known recoverable type.
+		if resource.HasSig(obj, resource.SecretSig) {
+			value, has := obj["value"]
+			if !has {
+				return nil, nil // the secret's value was an unknown that was skipped, so skip the secret as well.
+			}
+			if !opts.KeepSecrets {
+				logging.V(5).Infof("unmarshalling secret as raw value, as opts.KeepSecrets is false")
+				return &value, nil
+			}
+			m := resource.MakeSecret(value)
+			return &m, nil
+		}
 		objmap := obj.Mappable()
 		asset, isasset, err := resource.DeserializeAsset(objmap)
 		if err != nil {
@@ -121,3 +121,32 @@ func TestComputedReject(t *testing.T) {
 		assert.Nil(t, cpropU)
 	}
 }
+
+func TestSecretSerialize(t *testing.T) {
+	// Ensure that secrets survive round trips when KeepSecrets == true.
+	opts := MarshalOptions{KeepSecrets: true}
+	secret := resource.MakeSecret(resource.NewObjectProperty(resource.PropertyMap{
+		"password": resource.NewStringProperty("hunter2"),
+	}))
+	sprop, err := MarshalPropertyValue(secret, opts)
+	assert.Nil(t, err)
+	spropU, err := UnmarshalPropertyValue(sprop, opts)
+	assert.Nil(t, err)
+	assert.True(t, spropU.IsSecret())
+	assert.True(t, secret.DeepEquals(*spropU))
+
+	// And that they are replaced by their underlying values otherwise.
+	sprop, err = MarshalPropertyValue(secret, MarshalOptions{})
+	assert.Nil(t, err)
+	spropU, err = UnmarshalPropertyValue(sprop, opts)
+	assert.Nil(t, err)
+	assert.False(t, spropU.IsSecret())
+	assert.True(t, secret.SecretValue().Element.DeepEquals(*spropU))
+
+	sprop, err = MarshalPropertyValue(secret, opts)
+	assert.Nil(t, err)
+	spropU, err = UnmarshalPropertyValue(sprop, MarshalOptions{})
+	assert.Nil(t, err)
+	assert.False(t, spropU.IsSecret())
+	assert.True(t, secret.SecretValue().Element.DeepEquals(*spropU))
+}
@@ -0,0 +1,276 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PropertyPath represents a path to a nested property. The path may be composed of strings (which access properties
+// in ObjectProperty values) and integers (which access elements of ArrayProperty values).
+type PropertyPath []interface{}
+
+// ParsePropertyPath parses a property path into a PropertyPath value.
+//
+// A property path string is essentially a Javascript property access expression in which all elements are literals.
+// Valid property paths obey the following EBNF-ish grammar:
+//
+//	propertyName := [a-zA-Z_$] { [a-zA-Z0-9_$] }
+//	quotedPropertyName := '"' ( '\' '"' | [^"] ) { ( '\' '"' | [^"] ) } '"'
+//	arrayIndex := { [0-9] }
+//
+//	propertyIndex := '[' ( quotedPropertyName | arrayIndex ) ']'
+//	rootProperty := ( propertyName | propertyIndex )
+//	propertyAccessor := ( ( '.' propertyName ) |  propertyIndex )
+//	path := rootProperty { propertyAccessor }
+//
+// Examples of valid paths:
+// - root
+// - root.nested
+// - root["nested"]
+// - root.double.nest
+// - root["double"].nest
+// - root["double"]["nest"]
+// - root.array[0]
+// - root.array[100]
+// - root.array[0].nested
+// - root.array[0][1].nested
+// - root.nested.array[0].double[1]
+// - root["key with \"escaped\" quotes"]
+// - root["key with a ."]
+// - ["root key with \"escaped\" quotes"].nested
+// - ["root key with a ."][100]
+func ParsePropertyPath(path string) (PropertyPath, error) {
+	// We interpret the grammar above a little loosely in order to keep things simple. Specifically, we will accept
+	// something close to the following:
+	// pathElement := { '.' } ( '[' ( [0-9]+ | '"' ('\' '"' | [^"] )+ '"' ']' | [a-zA-Z_$][a-zA-Z0-9_$] )
+	// path := { pathElement }
+	var elements []interface{}
+	for len(path) > 0 {
+		switch path[0] {
+		case '.':
+			if len(path) == 1 || path[1] == '.' || path[1] == '[' {
+				return nil, errors.New("missing property name")
+			}
+			path = path[1:]
+		case '[':
+			// If the character following the '[' is a '"', parse a string key.
+			var pathElement interface{}
+			if len(path) > 1 && path[1] == '"' {
+				var propertyKey []byte
+				var i int
+				for i = 2; ; {
+					if i >= len(path) {
+						return nil, errors.New("missing closing quote in property name")
+					} else if path[i] == '"' {
+						i++
+						break
+					} else if path[i] == '\\' && i+1 < len(path) && path[i+1] == '"' {
+						propertyKey = append(propertyKey, '"')
+						i += 2
+					} else {
+						propertyKey = append(propertyKey, path[i])
+						i++
+					}
+				}
+				if i >= len(path) || path[i] != ']' {
+					return nil, errors.New("missing closing bracket in property access")
+				}
+				pathElement, path = string(propertyKey), path[i:]
+			} else {
+				// Look for a closing ']'
+				rbracket := strings.IndexRune(path, ']')
+				if rbracket == -1 {
+					return nil, errors.New("missing closing bracket in array index")
+				}
+
+				index, err := strconv.ParseInt(path[1:rbracket], 10, 0)
+				if err != nil || index < 0 {
+					return nil, errors.Errorf("invalid array index '%s'", path[1:rbracket])
+				}
+				pathElement, path = int(index), path[rbracket:]
+			}
+			elements, path = append(elements, pathElement), path[1:]
+		default:
+			for i := 0; ; i++ {
+				if i == len(path) || path[i] == '.' || path[i] == '[' {
+					if i == 0 {
+						return nil, errors.New("missing property name")
+					}
+					elements, path = append(elements, path[:i]), path[i:]
+					break
+				}
+			}
+		}
+	}
+	if len(elements) == 0 {
+		return nil, errors.New("property path is empty")
+	}
+	return PropertyPath(elements), nil
+}
+
+// Get attempts to get the value located by the PropertyPath inside the given PropertyValue. If any component of the
+// path does not exist, this function will return (NullPropertyValue, false).
+func (p PropertyPath) Get(v PropertyValue) (PropertyValue, bool) {
+	for _, key := range p {
+		switch {
+		case v.IsArray():
+			index, ok := key.(int)
+			if !ok || index < 0 || index >= len(v.ArrayValue()) {
+				return PropertyValue{}, false
+			}
+			v = v.ArrayValue()[index]
+		case v.IsObject():
+			k, ok := key.(string)
+			if !ok {
+				return PropertyValue{}, false
+			}
+			v, ok = v.ObjectValue()[PropertyKey(k)]
+			if !ok {
+				return PropertyValue{}, false
+			}
+		default:
+			return PropertyValue{}, false
+		}
+	}
+	return v, true
+}
+
+// Set attempts to set the location inside a PropertyValue indicated by the PropertyPath to the given value. Any
+// intermediate objects that do not yet exist are created. If any component of the path other than the last exists
+// but is not an object or array of the appropriate kind, or if an array index is out of range, this function returns
+// false.
+func (p PropertyPath) Set(dest, v PropertyValue) bool {
+	if len(p) == 0 {
+		return false
+	}
+
+	for _, key := range p[:len(p)-1] {
+		var next PropertyValue
+		switch {
+		case dest.IsArray():
+			index, ok := key.(int)
+			if !ok || index < 0 || index >= len(dest.ArrayValue()) {
+				return false
+			}
+			next = dest.ArrayValue()[index]
+		case dest.IsObject():
+			k, ok := key.(string)
+			if !ok {
+				return false
+			}
+			obj := dest.ObjectValue()
+			if next, ok = obj[PropertyKey(k)]; !ok || next.IsNull() {
+				next = NewObjectProperty(PropertyMap{})
+				obj[PropertyKey(k)] = next
+			}
+		default:
+			return false
+		}
+		dest = next
+	}
+
+	switch key := p[len(p)-1]; {
+	case dest.IsArray():
+		index, ok := key.(int)
+		if !ok || index < 0 || index >= len(dest.ArrayValue()) {
+			return false
+		}
+		dest.ArrayValue()[index] = v
+	case dest.IsObject():
+		k, ok := key.(string)
+		if !ok {
+			return false
+		}
+		dest.ObjectValue()[PropertyKey(k)] = v
+	default:
+		return false
+	}
+	return true
+}
+
+// Delete attempts to delete the value located by the PropertyPath inside the given PropertyValue. Object properties
+// are removed from their containing object; array elements are set to null so that the indices of subsequent elements
+// do not change. If any component of the path does not exist, this function returns false.
+func (p PropertyPath) Delete(dest PropertyValue) bool {
+	if len(p) == 0 {
+		return false
+	}
+
+	dest, ok := p[:len(p)-1].Get(dest)
+	if !ok {
+		return false
+	}
+
+	switch key := p[len(p)-1]; {
+	case dest.IsArray():
+		index, ok := key.(int)
+		if !ok || index < 0 || index >= len(dest.ArrayValue()) {
+			return false
+		}
+		dest.ArrayValue()[index] = NewNullProperty()
+	case dest.IsObject():
+		k, ok := key.(string)
+		if !ok {
+			return false
+		}
+		if _, ok = dest.ObjectValue()[PropertyKey(k)]; !ok {
+			return false
+		}
+		delete(dest.ObjectValue(), PropertyKey(k))
+	default:
+		return false
+	}
+	return true
+}
+
+// String returns the canonical string form of the path.
+func (p PropertyPath) String() string {
+	var buf bytes.Buffer
+	for i, key := range p {
+		switch key := key.(type) {
+		case int:
+			buf.WriteString("[" + strconv.Itoa(key) + "]")
+		case string:
+			if isSimplePropertyName(key) {
+				if i > 0 {
+					buf.WriteByte('.')
+				}
+				buf.WriteString(key)
+			} else {
+				buf.WriteString(`["` + strings.Replace(key, `"`, `\"`, -1) + `"]`)
+			}
+		}
+	}
+	return buf.String()
+}
+
+// isSimplePropertyName returns true if the given property name may be written without brackets in a property path.
+func isSimplePropertyName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		isAlpha := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$'
+		if !isAlpha && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePropertyPath(t *testing.T) {
+	cases := []struct {
+		path     string
+		parsed   PropertyPath
+		rendered string
+	}{
+		{"root", PropertyPath{"root"}, "root"},
+		{"root.nested", PropertyPath{"root", "nested"}, "root.nested"},
+		{`root["nested"]`, PropertyPath{"root", "nested"}, "root.nested"},
+		{"root.double.nest", PropertyPath{"root", "double", "nest"}, "root.double.nest"},
+		{"root.array[0]", PropertyPath{"root", "array", 0}, "root.array[0]"},
+		{"root.array[100]", PropertyPath{"root", "array", 100}, "root.array[100]"},
+		{"root.array[0][1].nested", PropertyPath{"root", "array", 0, 1, "nested"}, "root.array[0][1].nested"},
+		{`root["key with \"escaped\" quotes"]`, PropertyPath{"root", `key with "escaped" quotes`},
+			`root["key with \"escaped\" quotes"]`},
+		{`root["key with a ."]`, PropertyPath{"root", "key with a ."}, `root["key with a ."]`},
+		{`["root key with a ."][100]`, PropertyPath{"root key with a .", 100}, `["root key with a ."][100]`},
+	}
+	for _, c := range cases {
+		parsed, err := ParsePropertyPath(c.path)
+		if assert.NoError(t, err, c.path) {
+			assert.Equal(t, c.parsed, parsed)
+			assert.Equal(t, c.rendered, parsed.String())
+		}
+	}
+
+	for _, invalid := range []string{"", "root[", `root["nested]`, `root["nested"`, "root[-1]", "root[a]", "root..a"} {
+		_, err := ParsePropertyPath(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPropertyPathGetSetDelete(t *testing.T) {
+	value := NewObjectProperty(NewPropertyMapFromMap(map[string]interface{}{
+		"root": map[string]interface{}{
+			"nested": "foo",
+			"array":  []interface{}{"a", map[string]interface{}{"b": "c"}},
+		},
+	}))
+
+	get := func(path string) (PropertyValue, bool) {
+		p, err := ParsePropertyPath(path)
+		assert.NoError(t, err)
+		return p.Get(value)
+	}
+	set := func(path string, v PropertyValue) bool {
+		p, err := ParsePropertyPath(path)
+		assert.NoError(t, err)
+		return p.Set(value, v)
+	}
+	del := func(path string) bool {
+		p, err := ParsePropertyPath(path)
+		assert.NoError(t, err)
+		return p.Delete(value)
+	}
+
+	v, ok := get("root.nested")
+	assert.True(t, ok)
+	assert.Equal(t, NewStringProperty("foo"), v)
+	v, ok = get("root.array[1].b")
+	assert.True(t, ok)
+	assert.Equal(t, NewStringProperty("c"), v)
+	_, ok = get("root.array[2]")
+	assert.False(t, ok)
+	_, ok = get("root.nested.deeper")
+	assert.False(t, ok)
+
+	// Set replaces existing values and creates missing intermediate objects, but does not extend arrays.
+	assert.True(t, set("root.array[0]", NewStringProperty("z")))
+	assert.True(t, set("root.missing.value", NewNumberProperty(42)))
+	assert.False(t, set("root.array[2]", NewStringProperty("z")))
+	assert.False(t, set("root.nested.deeper", NewStringProperty("z")))
+	v, _ = get("root.array[0]")
+	assert.Equal(t, NewStringProperty("z"), v)
+	v, _ = get("root.missing.value")
+	assert.Equal(t, NewNumberProperty(42), v)
+
+	// Delete removes object properties and nulls out array elements.
+	assert.True(t, del("root.nested"))
+	assert.True(t, del("root.array[1]"))
+	assert.False(t, del("root.nested"))
+	_, ok = get("root.nested")
+	assert.False(t, ok)
+	v, _ = get("root.array[1]")
+	assert.True(t, v.IsNull())
+}
@@ -21,29 +21,34 @@ import (
 // Goal is a desired state for a resource object.  Normally it represents a subset of the resource's state expressed by
 // a program, however if Output is true, it represents a more complete, post-deployment view of the state.
 type Goal struct {
-	Type         tokens.Type  // the type of resource.
-	Name         tokens.QName // the name for the resource's URN.
-	Custom       bool         // true if this resource is custom, managed by a plugin.
-	Properties   PropertyMap  // the resource's property state.
-	Parent       URN          // an optional parent URN for this resource.
-	Protect      bool         // true to protect this resource from deletion.
-	Dependencies []URN        // dependencies of this resource object.
-	Provider     string       // the provider to use for this resource.
-	InitErrors   []string     // errors encountered as we attempted to initialize the resource.
+	Type          tokens.Type  // the type of resource.
+	Name          tokens.QName // the name for the resource's URN.
+	Custom        bool         // true if this resource is custom, managed by a plugin.
+	Properties    PropertyMap  // the resource's property state.
+	Parent        URN          // an optional parent URN for this resource.
+	Protect       bool         // true to protect this resource from deletion.
+	Dependencies  []URN        // dependencies of this resource object.
+	Provider      string       // the provider to use for this resource.
+	InitErrors    []string     // errors encountered as we attempted to initialize the resource.
+	ID            ID           // the ID of an existing resource to import, if any.
+	IgnoreChanges []string     // the property paths whose changes are ignored when this resource is updated.
 }
 
 // NewGoal allocates a new resource goal state.
 func NewGoal(t tokens.Type, name tokens.QName, custom bool, props PropertyMap,
-	parent URN, protect bool, dependencies []URN, provider string, initErrors []string) *Goal {
+	parent URN, protect bool, dependencies []URN, provider string, initErrors []string, id ID,
+	ignoreChanges []string) *Goal {
 	return &Goal{
-		Type:         t,
-		Name:         name,
-		Custom:       custom,
-		Properties:   props,
-		Parent:       parent,
-		Protect:      protect,
-		Dependencies: dependencies,
-		Provider:     provider,
-		InitErrors:   initErrors,
+		Type:          t,
+		Name:          name,
+		Custom:        custom,
+		Properties:    props,
+		Parent:        parent,
+		Protect:       protect,
+		Dependencies:  dependencies,
+		Provider:      provider,
+		InitErrors:    initErrors,
+		ID:            id,
+		IgnoreChanges: ignoreChanges,
 	}
 }
@@ -17,7 +17,10 @@
 package stack
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"io/ioutil"
 
 	"github.com/pkg/errors"
 
@@ -30,7 +33,43 @@ import (
 	"github.com/pulumi/pulumi/pkg/util/contract"
 )
 
+// CompressCheckpoint gzip-compresses a serialized checkpoint. Compressed checkpoints are recognized and decompressed
+// automatically by UnmarshalVersionedCheckpointToLatestCheckpoint.
+func CompressCheckpoint(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressCheckpoint decompresses the given checkpoint if it begins with the gzip magic number, and otherwise
+// returns it unchanged.
+func decompressCheckpoint(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		return data, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "decompressing checkpoint")
+	}
+	data, err = ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "decompressing checkpoint")
+	}
+	return data, nil
+}
+
 func UnmarshalVersionedCheckpointToLatestCheckpoint(bytes []byte) (*apitype.CheckpointV2, error) {
+	bytes, err := decompressCheckpoint(bytes)
+	if err != nil {
+		return nil, err
+	}
+
 	var versionedCheckpoint apitype.VersionedCheckpoint
 	if err := json.Unmarshal(bytes, &versionedCheckpoint); err != nil {
 		return nil, err
@@ -69,12 +108,18 @@ func UnmarshalVersionedCheckpointToLatestCheckpoint(bytes []byte) (*apitype.Chec
 	}
 }
 
-// SerializeCheckpoint turns a snapshot into a data structure suitable for serialization.
-func SerializeCheckpoint(stack tokens.QName, config config.Map, snap *deploy.Snapshot) *apitype.VersionedCheckpoint {
+// SerializeCheckpoint turns a snapshot into a data structure suitable for serialization. Secret values are encrypted
+// with the given encrypter.
+func SerializeCheckpoint(stack tokens.QName, config config.Map, snap *deploy.Snapshot,
+	enc config.Encrypter) (*apitype.VersionedCheckpoint, error) {
 	// If snap is nil, that's okay, we will just create an empty deployment; otherwise, serialize the whole snapshot.
 	var latest *apitype.DeploymentV2
 	if snap != nil {
-		latest = SerializeDeployment(snap)
+		dep, err := SerializeDeployment(snap, enc)
+		if err != nil {
+			return nil, err
+		}
+		latest = dep
 	}
 
 	b, err := json.Marshal(apitype.CheckpointV2{
@@ -87,27 +132,38 @@ func SerializeCheckpoint(stack tokens.QName, config config.Map, snap *deploy.Sna
 	return &apitype.VersionedCheckpoint{
 		Version:    apitype.DeploymentSchemaVersionCurrent,
 		Checkpoint: json.RawMessage(b),
-	}
+	}, nil
 }
 
-// DeserializeCheckpoint takes a serialized deployment record and returns its associated snapshot. Returns nil
-// if there have been no deployments performed on this checkpoint.
-func DeserializeCheckpoint(chkpoint *apitype.CheckpointV2) (*deploy.Snapshot, error) {
+// DeserializeCheckpoint takes a serialized deployment record and returns its associated snapshot, replaying any
+// journal entries recorded since the latest deployment was written. Returns nil if there have been no deployments
+// performed on this checkpoint. Secret values are decrypted with the given decrypter; if it is nil, they are masked.
+func DeserializeCheckpoint(chkpoint *apitype.CheckpointV2, dec config.Decrypter) (*deploy.Snapshot, error) {
 	contract.Require(chkpoint != nil, "chkpoint")
+	var snap *deploy.Snapshot
 	if chkpoint.Latest != nil {
-		return DeserializeDeploymentV2(*chkpoint.Latest)
+		latest, err := DeserializeDeploymentV2(*chkpoint.Latest, dec)
+		if err != nil {
+			return nil, err
+		}
+		snap = latest
 	}
 
-	return nil, nil
+	if len(chkpoint.Journal) > 0 {
+		return ReplayJournal(snap, chkpoint.Journal, dec)
+	}
+	return snap, nil
 }
 
 // GetRootStackResource returns the root stack resource from a given snapshot, or nil if not found.  If the stack
-// exists, its output properties, if any, are also returned in the resulting map.
+// exists, its output properties, if any, are also returned in the resulting map, with any secrets masked.
 func GetRootStackResource(snap *deploy.Snapshot) (*resource.State, map[string]interface{}) {
 	if snap != nil {
 		for _, res := range snap.Resources {
 			if res.Type == resource.RootStackType {
-				return res, SerializeResource(res).Outputs
+				sres, err := SerializeResource(res, nil)
+				contract.AssertNoError(err) // masking secrets cannot fail.
+				return res, sres.Outputs
 			}
 		}
 	}
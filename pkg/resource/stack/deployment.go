@@ -20,9 +20,11 @@ import (
 	"reflect"
 
 	"github.com/blang/semver"
+	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/apitype/migrate"
 	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/util/contract"
 	"github.com/pulumi/pulumi/pkg/workspace"
@@ -46,8 +48,12 @@ var (
 	ErrDeploymentSchemaVersionTooNew = fmt.Errorf("this stack's deployment version is too new")
 )
 
-// SerializeDeployment serializes an entire snapshot as a deploy record.
-func SerializeDeployment(snap *deploy.Snapshot) *apitype.DeploymentV2 {
+// secretMask is the value displayed in place of a secret that has not been decrypted.
+const secretMask = "[secret]"
+
+// SerializeDeployment serializes an entire snapshot as a deploy record. Secret values are encrypted with the given
+// encrypter; if it is nil, they are masked instead.
+func SerializeDeployment(snap *deploy.Snapshot, enc config.Encrypter) (*apitype.DeploymentV2, error) {
 	contract.Require(snap != nil, "snap")
 
 	// Capture the version information into a manifest.
@@ -57,40 +63,41 @@ func SerializeDeployment(snap *deploy.Snapshot) *apitype.DeploymentV2 {
 		Version: snap.Manifest.Version,
 	}
 	for _, plug := range snap.Manifest.Plugins {
-		var version string
-		if plug.Version != nil {
-			version = plug.Version.String()
-		}
-		manifest.Plugins = append(manifest.Plugins, apitype.PluginInfoV1{
-			Name:    plug.Name,
-			Path:    plug.Path,
-			Type:    plug.Kind,
-			Version: version,
-		})
+		manifest.Plugins = append(manifest.Plugins, SerializePlugin(plug))
 	}
 
 	// Serialize all vertices and only include a vertex section if non-empty.
 	var resources []apitype.ResourceV2
 	for _, res := range snap.Resources {
-		resources = append(resources, SerializeResource(res))
+		sres, err := SerializeResource(res, enc)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, sres)
 	}
 
 	var operations []apitype.OperationV1
 	for _, op := range snap.PendingOperations {
-		operations = append(operations, SerializeOperation(op))
+		sop, err := SerializeOperation(op, enc)
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, sop)
 	}
 
 	return &apitype.DeploymentV2{
 		Manifest:          manifest,
 		Resources:         resources,
 		PendingOperations: operations,
-	}
+	}, nil
 }
 
 // DeserializeUntypedDeployment deserializes an untyped deployment and produces a `deploy.Snapshot`
 // from it. DeserializeDeployment will return an error if the untyped deployment's version is
-// not within the range `DeploymentSchemaVersionCurrent` and `DeploymentSchemaVersionOldestSupported`.
-func DeserializeUntypedDeployment(deployment *apitype.UntypedDeployment) (*deploy.Snapshot, error) {
+// not within the range `DeploymentSchemaVersionCurrent` and `DeploymentSchemaVersionOldestSupported`. Secret values are
+// decrypted with the given decrypter; if it is nil, they are masked instead.
+func DeserializeUntypedDeployment(deployment *apitype.UntypedDeployment,
+	dec config.Decrypter) (*deploy.Snapshot, error) {
 	contract.Require(deployment != nil, "deployment")
 	switch {
 	case deployment.Version > apitype.DeploymentSchemaVersionCurrent:
@@ -116,11 +123,12 @@ func DeserializeUntypedDeployment(deployment *apitype.UntypedDeployment) (*deplo
 		contract.Failf("unrecognized version: %d", deployment.Version)
 	}
 
-	return DeserializeDeploymentV2(v2deployment)
+	return DeserializeDeploymentV2(v2deployment, dec)
 }
 
-// DeserializeDeploymentV2 deserializes a typed DeploymentV2 into a `deploy.Snapshot`.
-func DeserializeDeploymentV2(deployment apitype.DeploymentV2) (*deploy.Snapshot, error) {
+// DeserializeDeploymentV2 deserializes a typed DeploymentV2 into a `deploy.Snapshot`. Secret values are decrypted with
+// the given decrypter; if it is nil, they are masked instead.
+func DeserializeDeploymentV2(deployment apitype.DeploymentV2, dec config.Decrypter) (*deploy.Snapshot, error) {
 	// Unpack the versions.
 	manifest := deploy.Manifest{
 		Time:    deployment.Manifest.Time,
@@ -128,25 +136,17 @@ func DeserializeDeploymentV2(deployment apitype.DeploymentV2) (*deploy.Snapshot,
 		Version: deployment.Manifest.Version,
 	}
 	for _, plug := range deployment.Manifest.Plugins {
-		var version *semver.Version
-		if v := plug.Version; v != "" {
-			sv, err := semver.ParseTolerant(v)
-			if err != nil {
-				return nil, err
-			}
-			version = &sv
+		desplug, err := DeserializePlugin(plug)
+		if err != nil {
+			return nil, err
 		}
-		manifest.Plugins = append(manifest.Plugins, workspace.PluginInfo{
-			Name:    plug.Name,
-			Kind:    plug.Type,
-			Version: version,
-		})
+		manifest.Plugins = append(manifest.Plugins, desplug)
 	}
 
 	// For every serialized resource vertex, create a ResourceDeployment out of it.
 	var resources []*resource.State
 	for _, res := range deployment.Resources {
-		desres, err := DeserializeResource(res)
+		desres, err := DeserializeResource(res, dec)
 		if err != nil {
 			return nil, err
 		}
@@ -155,7 +155,7 @@ func DeserializeDeploymentV2(deployment apitype.DeploymentV2) (*deploy.Snapshot,
 
 	var ops []resource.Operation
 	for _, op := range deployment.PendingOperations {
-		desop, err := DeserializeOperation(op)
+		desop, err := DeserializeOperation(op, dec)
 		if err != nil {
 			return nil, err
 		}
@@ -165,63 +165,95 @@ func DeserializeDeploymentV2(deployment apitype.DeploymentV2) (*deploy.Snapshot,
 	return deploy.NewSnapshot(manifest, resources, ops), nil
 }
 
-// SerializeResource turns a resource into a structure suitable for serialization.
-func SerializeResource(res *resource.State) apitype.ResourceV2 {
+// SerializePlugin turns a plugin description into a structure suitable for serialization.
+func SerializePlugin(plug workspace.PluginInfo) apitype.PluginInfoV1 {
+	var version string
+	if plug.Version != nil {
+		version = plug.Version.String()
+	}
+	return apitype.PluginInfoV1{
+		Name:    plug.Name,
+		Path:    plug.Path,
+		Type:    plug.Kind,
+		Version: version,
+	}
+}
+
+// SerializeResource turns a resource into a structure suitable for serialization. Secret values are encrypted with
+// the given encrypter; if it is nil, they are masked instead.
+func SerializeResource(res *resource.State, enc config.Encrypter) (apitype.ResourceV2, error) {
 	contract.Assert(res != nil)
 	contract.Assertf(string(res.URN) != "", "Unexpected empty resource resource.URN")
 
 	// Serialize all input and output properties recursively, and add them if non-empty.
 	var inputs map[string]interface{}
 	if inp := res.Inputs; inp != nil {
-		inputs = SerializeProperties(inp)
+		sinp, err := SerializeProperties(inp, enc)
+		if err != nil {
+			return apitype.ResourceV2{}, err
+		}
+		inputs = sinp
 	}
 	var outputs map[string]interface{}
 	if outp := res.Outputs; outp != nil {
-		outputs = SerializeProperties(outp)
+		soutp, err := SerializeProperties(outp, enc)
+		if err != nil {
+			return apitype.ResourceV2{}, err
+		}
+		outputs = soutp
 	}
 
 	return apitype.ResourceV2{
-		URN:          res.URN,
-		Custom:       res.Custom,
-		Delete:       res.Delete,
-		ID:           res.ID,
-		Type:         res.Type,
-		Parent:       res.Parent,
-		Inputs:       inputs,
-		Outputs:      outputs,
-		Protect:      res.Protect,
-		External:     res.External,
-		Dependencies: res.Dependencies,
-		InitErrors:   res.InitErrors,
-		Provider:     res.Provider,
-	}
+		URN:           res.URN,
+		Custom:        res.Custom,
+		Delete:        res.Delete,
+		ID:            res.ID,
+		Type:          res.Type,
+		Parent:        res.Parent,
+		Inputs:        inputs,
+		Outputs:       outputs,
+		Protect:       res.Protect,
+		External:      res.External,
+		Dependencies:  res.Dependencies,
+		InitErrors:    res.InitErrors,
+		Provider:      res.Provider,
+		IgnoreChanges: res.IgnoreChanges,
+	}, nil
 }
 
-func SerializeOperation(op resource.Operation) apitype.OperationV1 {
-	res := SerializeResource(op.Resource)
+func SerializeOperation(op resource.Operation, enc config.Encrypter) (apitype.OperationV1, error) {
+	res, err := SerializeResource(op.Resource, enc)
+	if err != nil {
+		return apitype.OperationV1{}, err
+	}
 	return apitype.OperationV1{
 		Resource: res,
 		Type:     apitype.OperationType(op.Type),
-	}
+	}, nil
 }
 
-// SerializeProperties serializes a resource property bag so that it's suitable for serialization.
-func SerializeProperties(props resource.PropertyMap) map[string]interface{} {
+// SerializeProperties serializes a resource property bag so that it's suitable for serialization. Secret values are
+// encrypted with the given encrypter; if it is nil, they are masked instead.
+func SerializeProperties(props resource.PropertyMap, enc config.Encrypter) (map[string]interface{}, error) {
 	dst := make(map[string]interface{})
 	for _, k := range props.StableKeys() {
-		if v := SerializePropertyValue(props[k]); v != nil {
+		v, err := SerializePropertyValue(props[k], enc)
+		if err != nil {
+			return nil, err
+		} else if v != nil {
 			dst[string(k)] = v
 		}
 	}
-	return dst
+	return dst, nil
 }
 
-// SerializePropertyValue serializes a resource property value so that it's suitable for serialization.
-func SerializePropertyValue(prop resource.PropertyValue) interface{} {
+// SerializePropertyValue serializes a resource property value so that it's suitable for serialization. Secret values
+// are encrypted with the given encrypter; if it is nil, they are masked instead.
+func SerializePropertyValue(prop resource.PropertyValue, enc config.Encrypter) (interface{}, error) {
 	// Skip nulls and "outputs"; the former needn't be serialized, and the latter happens if there is an output
 	// that hasn't materialized (either because we're serializing inputs or the provider didn't give us the value).
 	if prop.IsComputed() || !prop.HasValue() {
-		return nil
+		return nil, nil
 	}
 
 	// For arrays, make sure to recurse.
@@ -229,57 +261,105 @@ func SerializePropertyValue(prop resource.PropertyValue) interface{} {
 		srcarr := prop.ArrayValue()
 		dstarr := make([]interface{}, len(srcarr))
 		for i, elem := range prop.ArrayValue() {
-			dstarr[i] = SerializePropertyValue(elem)
+			selem, err := SerializePropertyValue(elem, enc)
+			if err != nil {
+				return nil, err
+			}
+			dstarr[i] = selem
 		}
-		return dstarr
+		return dstarr, nil
 	}
 
 	// Also for objects, recurse and use naked properties.
 	if prop.IsObject() {
-		return SerializeProperties(prop.ObjectValue())
+		return SerializeProperties(prop.ObjectValue(), enc)
 	}
 
 	// For assets, we need to serialize them a little carefully, so we can recover them afterwards.
 	if prop.IsAsset() {
-		return prop.AssetValue().Serialize()
+		return prop.AssetValue().Serialize(), nil
 	} else if prop.IsArchive() {
-		return prop.ArchiveValue().Serialize()
+		return prop.ArchiveValue().Serialize(), nil
+	}
+
+	// Secrets are serialized as an object carrying the secret signature and the encrypted JSON of the underlying
+	// value, so that they can be recognized and decrypted afterwards.
+	if prop.IsSecret() {
+		if enc == nil {
+			return secretMask, nil
+		}
+		elem, err := SerializePropertyValue(prop.SecretValue().Element, enc)
+		if err != nil {
+			return nil, err
+		}
+		plaintext, err := json.Marshal(elem)
+		if err != nil {
+			return nil, err
+		}
+		ciphertext, err := enc.EncryptValue(string(plaintext))
+		if err != nil {
+			return nil, errors.Wrap(err, "encrypting secret value")
+		}
+		return map[string]interface{}{
+			string(resource.SigKey): resource.SecretSig,
+			"ciphertext":            ciphertext,
+		}, nil
 	}
 
 	// All others are returned as-is.
-	return prop.V
+	return prop.V, nil
 }
 
-// DeserializeResource turns a serialized resource back into its usual form.
-func DeserializeResource(res apitype.ResourceV2) (*resource.State, error) {
+// DeserializePlugin turns a serialized plugin description back into its usual form.
+func DeserializePlugin(plug apitype.PluginInfoV1) (workspace.PluginInfo, error) {
+	var version *semver.Version
+	if v := plug.Version; v != "" {
+		sv, err := semver.ParseTolerant(v)
+		if err != nil {
+			return workspace.PluginInfo{}, err
+		}
+		version = &sv
+	}
+	return workspace.PluginInfo{
+		Name:    plug.Name,
+		Kind:    plug.Type,
+		Version: version,
+	}, nil
+}
+
+// DeserializeResource turns a serialized resource back into its usual form. Secret values are decrypted with the given
+// decrypter; if it is nil, they are masked instead.
+func DeserializeResource(res apitype.ResourceV2, dec config.Decrypter) (*resource.State, error) {
 	// Deserialize the resource properties, if they exist.
-	inputs, err := DeserializeProperties(res.Inputs)
+	inputs, err := DeserializeProperties(res.Inputs, dec)
 	if err != nil {
 		return nil, err
 	}
-	outputs, err := DeserializeProperties(res.Outputs)
+	outputs, err := DeserializeProperties(res.Outputs, dec)
 	if err != nil {
 		return nil, err
 	}
 
 	return resource.NewState(
 		res.Type, res.URN, res.Custom, res.Delete, res.ID,
-		inputs, outputs, res.Parent, res.Protect, res.External, res.Dependencies, res.InitErrors, res.Provider), nil
+		inputs, outputs, res.Parent, res.Protect, res.External, res.Dependencies, res.InitErrors, res.Provider,
+		res.IgnoreChanges), nil
 }
 
-func DeserializeOperation(op apitype.OperationV1) (resource.Operation, error) {
-	res, err := DeserializeResource(op.Resource)
+func DeserializeOperation(op apitype.OperationV1, dec config.Decrypter) (resource.Operation, error) {
+	res, err := DeserializeResource(op.Resource, dec)
 	if err != nil {
 		return resource.Operation{}, err
 	}
 	return resource.NewOperation(res, resource.OperationType(op.Type)), nil
 }
 
-// DeserializeProperties deserializes an entire map of deploy properties into a resource property map.
-func DeserializeProperties(props map[string]interface{}) (resource.PropertyMap, error) {
+// DeserializeProperties deserializes an entire map of deploy properties into a resource property map. Secret values are
+// decrypted with the given decrypter; if it is nil, they are masked instead.
+func DeserializeProperties(props map[string]interface{}, dec config.Decrypter) (resource.PropertyMap, error) {
 	result := make(resource.PropertyMap)
 	for k, prop := range props {
-		desprop, err := DeserializePropertyValue(prop)
+		desprop, err := DeserializePropertyValue(prop, dec)
 		if err != nil {
 			return nil, err
 		}
@@ -288,8 +368,9 @@ func DeserializeProperties(props map[string]interface{}) (resource.PropertyMap,
 	return result, nil
 }
 
-// DeserializePropertyValue deserializes a single deploy property into a resource property value.
-func DeserializePropertyValue(v interface{}) (resource.PropertyValue, error) {
+// DeserializePropertyValue deserializes a single deploy property into a resource property value. Secret values are
+// decrypted with the given decrypter; if it is nil, they are masked instead.
+func DeserializePropertyValue(v interface{}, dec config.Decrypter) (resource.PropertyValue, error) {
 	if v != nil {
 		switch w := v.(type) {
 		case bool:
@@ -301,7 +382,7 @@ func DeserializePropertyValue(v interface{}) (resource.PropertyValue, error) {
 		case []interface{}:
 			var arr []resource.PropertyValue
 			for _, elem := range w {
-				ev, err := DeserializePropertyValue(elem)
+				ev, err := DeserializePropertyValue(elem, dec)
 				if err != nil {
 					return resource.PropertyValue{}, err
 				}
@@ -309,7 +390,11 @@ func DeserializePropertyValue(v interface{}) (resource.PropertyValue, error) {
 			}
 			return resource.NewArrayProperty(arr), nil
 		case map[string]interface{}:
-			obj, err := DeserializeProperties(w)
+			// This could be a secret; if so, decrypt its underlying value.
+			if w[string(resource.SigKey)] == resource.SecretSig {
+				return deserializeSecret(w, dec)
+			}
+			obj, err := DeserializeProperties(w, dec)
 			if err != nil {
 				return resource.PropertyValue{}, err
 			}
@@ -336,3 +421,29 @@ func DeserializePropertyValue(v interface{}) (resource.PropertyValue, error) {
 
 	return resource.NewNullProperty(), nil
 }
+
+// deserializeSecret deserializes a serialized secret, decrypting its underlying value with the given decrypter. If the
+// decrypter is nil, the secret's value is masked.
+func deserializeSecret(obj map[string]interface{}, dec config.Decrypter) (resource.PropertyValue, error) {
+	ciphertext, ok := obj["ciphertext"].(string)
+	if !ok {
+		return resource.PropertyValue{}, errors.New("malformed secret value: missing ciphertext")
+	}
+	if dec == nil {
+		return resource.MakeSecret(resource.NewStringProperty(secretMask)), nil
+	}
+
+	plaintext, err := dec.DecryptValue(ciphertext)
+	if err != nil {
+		return resource.PropertyValue{}, errors.Wrap(err, "decrypting secret value")
+	}
+	var elem interface{}
+	if err = json.Unmarshal([]byte(plaintext), &elem); err != nil {
+		return resource.PropertyValue{}, errors.Wrap(err, "malformed secret value")
+	}
+	v, err := DeserializePropertyValue(elem, dec)
+	if err != nil {
+		return resource.PropertyValue{}, err
+	}
+	return resource.MakeSecret(v), nil
+}
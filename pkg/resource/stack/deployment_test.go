@@ -21,6 +21,7 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/tokens"
 )
 
@@ -76,9 +77,11 @@ func TestDeploymentSerialization(t *testing.T) {
 		},
 		[]string{},
 		"",
+		[]string{"in-map.a"},
 	)
 
-	dep := SerializeResource(res)
+	dep, err := SerializeResource(res, config.NewPanicCrypter())
+	assert.NoError(t, err)
 
 	// assert some things about the deployment record:
 	assert.NotNil(t, dep)
@@ -88,6 +91,7 @@ func TestDeploymentSerialization(t *testing.T) {
 	assert.Equal(t, 2, len(dep.Dependencies))
 	assert.Equal(t, resource.URN("foo:bar:baz"), dep.Dependencies[0])
 	assert.Equal(t, resource.URN("foo:bar:boo"), dep.Dependencies[1])
+	assert.Equal(t, []string{"in-map.a"}, dep.IgnoreChanges)
 
 	// assert some things about the inputs:
 	assert.NotNil(t, dep.Inputs)
@@ -152,7 +156,7 @@ func TestLoadTooNewDeployment(t *testing.T) {
 		Version: apitype.DeploymentSchemaVersionCurrent + 1,
 	}
 
-	deployment, err := DeserializeUntypedDeployment(untypedDeployment)
+	deployment, err := DeserializeUntypedDeployment(untypedDeployment, nil)
 	assert.Nil(t, deployment)
 	assert.Error(t, err)
 	assert.Equal(t, ErrDeploymentSchemaVersionTooNew, err)
@@ -163,8 +167,50 @@ func TestLoadTooOldDeployment(t *testing.T) {
 		Version: DeploymentSchemaVersionOldestSupported - 1,
 	}
 
-	deployment, err := DeserializeUntypedDeployment(untypedDeployment)
+	deployment, err := DeserializeUntypedDeployment(untypedDeployment, nil)
 	assert.Nil(t, deployment)
 	assert.Error(t, err)
 	assert.Equal(t, ErrDeploymentSchemaVersionTooOld, err)
 }
+
+func TestSecretSerialization(t *testing.T) {
+	crypter := config.NewSymmetricCrypterFromPassphrase("password", []byte("salt"))
+	props := resource.PropertyMap{
+		"plain": resource.NewStringProperty("visible"),
+		"secret": resource.MakeSecret(resource.NewObjectProperty(resource.PropertyMap{
+			"password": resource.NewStringProperty("hunter2"),
+			"ports":    resource.NewArrayProperty([]resource.PropertyValue{resource.NewNumberProperty(80)}),
+		})),
+	}
+
+	// Secrets are encrypted when serialized, and nothing of their underlying value remains in plaintext.
+	serialized, err := SerializeProperties(props, crypter)
+	assert.NoError(t, err)
+	assert.Equal(t, "visible", serialized["plain"])
+	secret, ok := serialized["secret"].(map[string]interface{})
+	if assert.True(t, ok) {
+		assert.Equal(t, resource.SecretSig, secret[string(resource.SigKey)])
+		assert.NotContains(t, secret["ciphertext"], "hunter2")
+	}
+
+	// And decrypted when deserialized.
+	deserialized, err := DeserializeProperties(serialized, crypter)
+	assert.NoError(t, err)
+	assert.True(t, props.DeepEquals(deserialized))
+
+	// Without a decrypter, secrets are masked.
+	masked, err := DeserializeProperties(serialized, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, resource.MakeSecret(resource.NewStringProperty("[secret]")), masked["secret"])
+
+	// As they are when serialized without an encrypter.
+	serialized, err = SerializeProperties(props, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "[secret]", serialized["secret"])
+
+	// Decrypting with the wrong key fails.
+	serialized, err = SerializeProperties(props, crypter)
+	assert.NoError(t, err)
+	_, err = DeserializeProperties(serialized, config.NewSymmetricCrypterFromPassphrase("wrong", []byte("salt")))
+	assert.Error(t, err)
+}
@@ -0,0 +1,132 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+)
+
+// ReplayJournal applies the given journal entries, in order, to a snapshot and returns the resulting snapshot. The
+// snapshot given is not modified. A nil snapshot is treated as an empty one. Secret values are decrypted with the given
+// decrypter; if it is nil, they are masked instead.
+func ReplayJournal(snap *deploy.Snapshot, journal []apitype.JournalEntryV1,
+	dec config.Decrypter) (*deploy.Snapshot, error) {
+	if len(journal) == 0 {
+		return snap, nil
+	}
+
+	// Number the snapshot's resources and operations as the journal expects: in order, starting from zero.
+	var manifest deploy.Manifest
+	var resources []*resource.State
+	var ops []resource.Operation
+	if snap != nil {
+		manifest = snap.Manifest
+		manifest.Plugins = append(manifest.Plugins[:0:0], snap.Manifest.Plugins...)
+		resources = append(resources, snap.Resources...)
+		ops = append(ops, snap.PendingOperations...)
+	}
+	resourceIDs := make(map[int]*resource.State)
+	for i, res := range resources {
+		resourceIDs[i] = res
+	}
+	opIDs := make([]int, len(ops))
+	for i := range ops {
+		opIDs[i] = i
+	}
+	nextResourceID, nextOpID := len(resources), len(ops)
+
+	// indexOf returns the position of the given resource in the list of resources.
+	indexOf := func(res *resource.State) int {
+		for i, r := range resources {
+			if r == res {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for i, entry := range journal {
+		var state *resource.State
+		if entry.Resource != nil {
+			des, err := DeserializeResource(*entry.Resource, dec)
+			if err != nil {
+				return nil, errors.Wrapf(err, "journal entry %d", i)
+			}
+			state = des
+		}
+
+		switch entry.Kind {
+		case apitype.JournalEntryBeginOperation:
+			if state == nil || entry.ID != nextOpID {
+				return nil, errors.Errorf("journal entry %d: malformed begin of operation %d", i, entry.ID)
+			}
+			ops = append(ops, resource.NewOperation(state, resource.OperationType(entry.OperationType)))
+			opIDs = append(opIDs, entry.ID)
+			nextOpID++
+		case apitype.JournalEntryCompleteOperation:
+			j := 0
+			for j < len(opIDs) && opIDs[j] != entry.ID {
+				j++
+			}
+			if j == len(opIDs) {
+				return nil, errors.Errorf("journal entry %d: operation %d is not pending", i, entry.ID)
+			}
+			ops = append(ops[:j], ops[j+1:]...)
+			opIDs = append(opIDs[:j], opIDs[j+1:]...)
+		case apitype.JournalEntryAddResource:
+			if state == nil || entry.ID != nextResourceID || entry.Index < 0 || entry.Index > len(resources) {
+				return nil, errors.Errorf("journal entry %d: malformed addition of resource %d", i, entry.ID)
+			}
+			resources = append(resources, nil)
+			copy(resources[entry.Index+1:], resources[entry.Index:])
+			resources[entry.Index] = state
+			resourceIDs[entry.ID] = state
+			nextResourceID++
+		case apitype.JournalEntryRemoveResource:
+			res, has := resourceIDs[entry.ID]
+			if !has {
+				return nil, errors.Errorf("journal entry %d: unknown resource %d", i, entry.ID)
+			}
+			j := indexOf(res)
+			resources = append(resources[:j], resources[j+1:]...)
+			delete(resourceIDs, entry.ID)
+		case apitype.JournalEntryUpdateResource:
+			res, has := resourceIDs[entry.ID]
+			if !has || state == nil {
+				return nil, errors.Errorf("journal entry %d: malformed update of resource %d", i, entry.ID)
+			}
+			resources[indexOf(res)] = state
+			resourceIDs[entry.ID] = state
+		case apitype.JournalEntryAddPlugin:
+			if entry.Plugin == nil {
+				return nil, errors.Errorf("journal entry %d: missing plugin", i)
+			}
+			plug, err := DeserializePlugin(*entry.Plugin)
+			if err != nil {
+				return nil, errors.Wrapf(err, "journal entry %d", i)
+			}
+			manifest.Plugins = append(manifest.Plugins, plug)
+		default:
+			return nil, errors.Errorf("journal entry %d: unknown kind '%s'", i, entry.Kind)
+		}
+	}
+
+	return deploy.NewSnapshot(manifest, resources, ops), nil
+}
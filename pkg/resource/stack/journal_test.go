@@ -0,0 +1,112 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/apitype"
+	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/tokens"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+func newJournalResource(urn string) *apitype.ResourceV2 {
+	res, err := SerializeResource(&resource.State{
+		Type:    tokens.Type("test"),
+		URN:     resource.URN(urn),
+		Inputs:  resource.PropertyMap{},
+		Outputs: resource.PropertyMap{},
+	}, config.NewPanicCrypter())
+	contract.AssertNoError(err)
+	return &res
+}
+
+func TestReplayJournal(t *testing.T) {
+	base := deploy.NewSnapshot(deploy.Manifest{Time: time.Now()}, []*resource.State{
+		{Type: "test", URN: "a"},
+		{Type: "test", URN: "b"},
+	}, nil)
+
+	snap, err := ReplayJournal(base, []apitype.JournalEntryV1{
+		{Kind: apitype.JournalEntryBeginOperation, ID: 0, Resource: newJournalResource("c"),
+			OperationType: apitype.OperationTypeCreating},
+		{Kind: apitype.JournalEntryCompleteOperation, ID: 0},
+		{Kind: apitype.JournalEntryAddResource, ID: 2, Index: 0, Resource: newJournalResource("c")},
+		{Kind: apitype.JournalEntryRemoveResource, ID: 0},
+		{Kind: apitype.JournalEntryUpdateResource, ID: 1, Resource: newJournalResource("b2")},
+		{Kind: apitype.JournalEntryBeginOperation, ID: 1, Resource: newJournalResource("d"),
+			OperationType: apitype.OperationTypeCreating},
+		{Kind: apitype.JournalEntryAddPlugin, Plugin: &apitype.PluginInfoV1{Name: "p", Type: "resource"}},
+	}, nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// The base snapshot is left untouched.
+	assert.Len(t, base.Resources, 2)
+	assert.Len(t, base.Manifest.Plugins, 0)
+
+	if assert.Len(t, snap.Resources, 2) {
+		assert.Equal(t, resource.URN("c"), snap.Resources[0].URN)
+		assert.Equal(t, resource.URN("b2"), snap.Resources[1].URN)
+	}
+	if assert.Len(t, snap.PendingOperations, 1) {
+		assert.Equal(t, resource.URN("d"), snap.PendingOperations[0].Resource.URN)
+		assert.Equal(t, resource.OperationTypeCreating, snap.PendingOperations[0].Type)
+	}
+	if assert.Len(t, snap.Manifest.Plugins, 1) {
+		assert.Equal(t, "p", snap.Manifest.Plugins[0].Name)
+	}
+}
+
+func TestReplayMalformedJournal(t *testing.T) {
+	base := deploy.NewSnapshot(deploy.Manifest{Time: time.Now()}, []*resource.State{
+		{Type: "test", URN: "a"},
+	}, nil)
+
+	journals := [][]apitype.JournalEntryV1{
+		{{Kind: apitype.JournalEntryAddResource, ID: 5, Resource: newJournalResource("b")}},
+		{{Kind: apitype.JournalEntryAddResource, ID: 1, Index: 2, Resource: newJournalResource("b")}},
+		{{Kind: apitype.JournalEntryRemoveResource, ID: 1}},
+		{{Kind: apitype.JournalEntryUpdateResource, ID: 0}},
+		{{Kind: apitype.JournalEntryCompleteOperation, ID: 0}},
+		{{Kind: "bogus"}},
+	}
+	for _, journal := range journals {
+		_, err := ReplayJournal(base, journal, nil)
+		assert.Error(t, err)
+	}
+}
+
+func TestCompressedCheckpoint(t *testing.T) {
+	bytes, err := ioutil.ReadFile("testdata/checkpoint-v1.json")
+	assert.NoError(t, err)
+
+	compressed, err := CompressCheckpoint(bytes)
+	assert.NoError(t, err)
+	assert.True(t, len(compressed) < len(bytes))
+
+	chk, err := UnmarshalVersionedCheckpointToLatestCheckpoint(compressed)
+	assert.NoError(t, err)
+	assert.NotNil(t, chk.Latest)
+	assert.Len(t, chk.Latest.Resources, 30)
+}
@@ -0,0 +1,90 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"sync"
+
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+// cachingCrypter is a crypter that constructs its underlying crypter on first use, and that remembers the values it
+// has encrypted and decrypted. Constructing a crypter may prompt for a passphrase or contact a service, so deferring
+// it means that stacks without secrets never pay that cost; remembering values keeps ciphertexts stable as a stack's
+// state is repeatedly saved, and avoids repeatedly decrypting the same values as it is loaded.
+type cachingCrypter struct {
+	factory     Factory
+	lock        sync.Mutex
+	crypter     config.Crypter
+	err         error
+	ciphertexts map[string]string // plaintexts to ciphertexts
+	plaintexts  map[string]string // ciphertexts to plaintexts
+}
+
+// NewCachingCrypter returns a crypter that constructs its underlying crypter using the given factory the first time
+// that a value is encrypted or decrypted, and that caches the results of encrypting and decrypting values.
+func NewCachingCrypter(factory Factory) config.Crypter {
+	return &cachingCrypter{
+		factory:     factory,
+		ciphertexts: make(map[string]string),
+		plaintexts:  make(map[string]string),
+	}
+}
+
+// getCrypter returns the underlying crypter, constructing it if necessary. The lock must be held.
+func (c *cachingCrypter) getCrypter() (config.Crypter, error) {
+	if c.crypter == nil && c.err == nil {
+		c.crypter, c.err = c.factory()
+	}
+	return c.crypter, c.err
+}
+
+func (c *cachingCrypter) EncryptValue(plaintext string) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if ciphertext, has := c.ciphertexts[plaintext]; has {
+		return ciphertext, nil
+	}
+	crypter, err := c.getCrypter()
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := crypter.EncryptValue(plaintext)
+	if err != nil {
+		return "", err
+	}
+	c.ciphertexts[plaintext], c.plaintexts[ciphertext] = ciphertext, plaintext
+	return ciphertext, nil
+}
+
+func (c *cachingCrypter) DecryptValue(ciphertext string) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if plaintext, has := c.plaintexts[ciphertext]; has {
+		return plaintext, nil
+	}
+	crypter, err := c.getCrypter()
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := crypter.DecryptValue(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	c.ciphertexts[plaintext], c.plaintexts[ciphertext] = ciphertext, plaintext
+	return plaintext, nil
+}
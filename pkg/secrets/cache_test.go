@@ -0,0 +1,67 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+func TestCachingCrypter(t *testing.T) {
+	constructed := 0
+	crypter := NewCachingCrypter(func() (config.Crypter, error) {
+		constructed++
+		return config.NewSymmetricCrypter(make([]byte, config.SymmetricCrypterKeyBytes)), nil
+	})
+	assert.Equal(t, 0, constructed)
+
+	// The underlying crypter is constructed once, on first use, and ciphertexts are stable.
+	first, err := crypter.EncryptValue("hunter2")
+	assert.NoError(t, err)
+	second, err := crypter.EncryptValue("hunter2")
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, constructed)
+
+	// Values encrypted elsewhere are decrypted, and then re-encrypted to the same ciphertext.
+	other := config.NewSymmetricCrypter(make([]byte, config.SymmetricCrypterKeyBytes))
+	ciphertext, err := other.EncryptValue("correct horse")
+	assert.NoError(t, err)
+	plaintext, err := crypter.DecryptValue(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "correct horse", plaintext)
+	reencrypted, err := crypter.EncryptValue("correct horse")
+	assert.NoError(t, err)
+	assert.Equal(t, ciphertext, reencrypted)
+	assert.Equal(t, 1, constructed)
+}
+
+func TestCachingCrypterError(t *testing.T) {
+	constructed := 0
+	crypter := NewCachingCrypter(func() (config.Crypter, error) {
+		constructed++
+		return nil, errors.New("incorrect passphrase")
+	})
+
+	_, err := crypter.EncryptValue("hunter2")
+	assert.EqualError(t, err, "incorrect passphrase")
+	_, err = crypter.DecryptValue("ciphertext")
+	assert.EqualError(t, err, "incorrect passphrase")
+	assert.Equal(t, 1, constructed)
+}
@@ -0,0 +1,71 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+// execCrypter is an encrypter/decrypter that delegates to an external command, allowing secrets to be protected by
+// systems such as Vault or a cloud KMS without teaching Pulumi about them. The command is invoked with a single
+// additional argument, either `encrypt` or `decrypt`, receives its input on stdin, and writes its result to stdout.
+// Ciphertext is trimmed of surrounding whitespace; decrypted plaintext is used verbatim.
+type execCrypter struct {
+	name string
+	args []string
+}
+
+// NewExecCrypter creates a crypter that runs the given command line to encrypt and decrypt values. The command line is
+// split on whitespace; it is not interpreted by a shell.
+func NewExecCrypter(command string) (config.Crypter, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, errors.New("the exec secrets provider requires a command, as in 'exec:<command>'")
+	}
+	return &execCrypter{name: fields[0], args: fields[1:]}, nil
+}
+
+func (c *execCrypter) EncryptValue(plaintext string) (string, error) {
+	ciphertext, err := c.run("encrypt", plaintext)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(ciphertext), nil
+}
+
+func (c *execCrypter) DecryptValue(ciphertext string) (string, error) {
+	return c.run("decrypt", ciphertext)
+}
+
+func (c *execCrypter) run(op string, input string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(c.name, append(append([]string{}, c.args...), op)...)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", errors.Wrapf(err, "secrets provider '%s' failed to %s: %s", c.name, op, msg)
+		}
+		return "", errors.Wrapf(err, "secrets provider '%s' failed to %s", c.name, op)
+	}
+	return stdout.String(), nil
+}
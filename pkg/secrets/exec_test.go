@@ -0,0 +1,98 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/resource/config"
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// rot13Script encrypts and decrypts with rot13, and fails on any other operation.
+const rot13Script = `#!/bin/sh
+case "$1" in
+encrypt|decrypt) tr 'A-Za-z' 'N-ZA-Mn-za-m' ;;
+*) echo "unknown operation $1" >&2; exit 3 ;;
+esac
+`
+
+func writeScript(t *testing.T) (string, func()) {
+	if runtime.GOOS == "windows" {
+		t.Skip("exec provider tests require a POSIX shell")
+	}
+
+	dir, err := ioutil.TempDir("", "pulumi-secrets-")
+	assert.NoError(t, err)
+	script := filepath.Join(dir, "rot13")
+	assert.NoError(t, ioutil.WriteFile(script, []byte(rot13Script), 0700))
+	return script, func() { contract.IgnoreError(os.RemoveAll(dir)) }
+}
+
+func TestExecCrypter(t *testing.T) {
+	script, cleanup := writeScript(t)
+	defer cleanup()
+
+	crypter, err := NewCrypter(ExecProviderPrefix+script, nil)
+	assert.NoError(t, err)
+
+	ciphertext, err := crypter.EncryptValue("hunter2\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "uhagre2", ciphertext)
+
+	plaintext, err := crypter.DecryptValue(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", plaintext)
+}
+
+func TestExecCrypterFailure(t *testing.T) {
+	script, cleanup := writeScript(t)
+	defer cleanup()
+
+	crypter, err := NewExecCrypter(script + " --key")
+	assert.NoError(t, err)
+
+	_, err = crypter.EncryptValue("hunter2")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown operation --key")
+	}
+}
+
+func TestNewCrypter(t *testing.T) {
+	builtins := map[string]Factory{
+		"":                 func() (config.Crypter, error) { return config.NewPanicCrypter(), nil },
+		PassphraseProvider: func() (config.Crypter, error) { return config.NewPanicCrypter(), nil },
+	}
+
+	for _, name := range []string{"", PassphraseProvider} {
+		crypter, err := NewCrypter(name, builtins)
+		assert.NoError(t, err)
+		assert.NotNil(t, crypter)
+	}
+
+	_, err := NewCrypter(ServiceProvider, builtins)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "supported providers are: passphrase, exec:<command>")
+	}
+
+	_, err = NewCrypter(ExecProviderPrefix+"  ", builtins)
+	assert.Error(t, err)
+}
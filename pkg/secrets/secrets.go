@@ -0,0 +1,66 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets implements the providers that may be chosen, per stack, to encrypt and decrypt configuration secrets.
+package secrets
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/resource/config"
+)
+
+const (
+	// PassphraseProvider encrypts secrets with a key derived from a user-supplied passphrase.
+	PassphraseProvider = "passphrase"
+	// ServiceProvider encrypts secrets using the Pulumi service.
+	ServiceProvider = "service"
+	// ExecProviderPrefix prefixes a provider that shells out to a user-supplied command, as in `exec:vault-crypt`.
+	ExecProviderPrefix = "exec:"
+)
+
+// Factory constructs a crypter for one of a backend's built-in providers.
+type Factory func() (config.Crypter, error)
+
+// IsExecProvider returns true if the given provider name refers to an external command.
+func IsExecProvider(provider string) bool {
+	return strings.HasPrefix(provider, ExecProviderPrefix)
+}
+
+// NewCrypter returns the crypter for the given provider name. Names that refer to an external command are handled
+// here; all others are looked up in the backend-specific set of built-in providers, where the empty name denotes the
+// backend's default.
+func NewCrypter(provider string, builtins map[string]Factory) (config.Crypter, error) {
+	if IsExecProvider(provider) {
+		return NewExecCrypter(strings.TrimPrefix(provider, ExecProviderPrefix))
+	}
+
+	if factory, has := builtins[provider]; has {
+		return factory()
+	}
+
+	var names []string
+	for name := range builtins {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	names = append(names, ExecProviderPrefix+"<command>")
+	return nil, errors.Errorf("unknown secrets provider '%s'; supported providers are: %s",
+		provider, strings.Join(names, ", "))
+}
@@ -0,0 +1,34 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !windows
+
+package cmdutil
+
+import (
+	"syscall"
+)
+
+// ProcessExists returns true if a process with the given PID is running on this machine.
+func ProcessExists(pid int) bool {
+	// Signal 0 performs error checking only: it fails with ESRCH if the process does not exist. If it fails with
+	// EPERM, the process exists but belongs to another user.
+	err := syscall.Kill(pid, syscall.Signal(0))
+	return err == nil || err == syscall.EPERM
+}
+
+// InterruptProcess asks the process with the given PID to stop, as though the user had pressed ^C.
+func InterruptProcess(pid int) error {
+	return syscall.Kill(pid, syscall.SIGINT)
+}
@@ -0,0 +1,35 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build windows
+
+package cmdutil
+
+import (
+	"github.com/pkg/errors"
+
+	ps "github.com/mitchellh/go-ps"
+)
+
+// ProcessExists returns true if a process with the given PID is running on this machine.
+func ProcessExists(pid int) bool {
+	proc, err := ps.FindProcess(pid)
+	return err == nil && proc != nil
+}
+
+// InterruptProcess asks the process with the given PID to stop, as though the user had pressed ^C. This function is
+// not implemented on Windows, where console control events cannot be sent to arbitrary processes.
+func InterruptProcess(pid int) error {
+	return errors.New("interrupting other processes is not supported on Windows")
+}
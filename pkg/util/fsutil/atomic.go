@@ -0,0 +1,62 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fsutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
+)
+
+// WriteFileAtomic writes data to the file named by path, much like ioutil.WriteFile. Unlike ioutil.WriteFile, the data
+// is first written to a temporary file in the same directory, flushed to disk, and then renamed over the destination.
+// Readers therefore observe either the old contents of the file or the new contents, never a partial write, even if
+// the process crashes or the disk fills up midway through.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// Write and sync the temporary file, removing it if anything goes wrong.
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, perm)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		contract.IgnoreError(os.Remove(tmpPath))
+		return err
+	}
+
+	// Finally, sync the containing directory so that the rename itself is durable. Not all platforms support syncing
+	// directories, so failures here are ignored.
+	if dir, dirErr := os.Open(filepath.Dir(path)); dirErr == nil {
+		contract.IgnoreError(dir.Sync())
+		contract.IgnoreClose(dir)
+	}
+	return nil
+}
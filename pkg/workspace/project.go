@@ -32,6 +32,48 @@ import (
 // Analyzers is a list of analyzers to run on this project.
 type Analyzers []tokens.QName
 
+// EnforcementLevel indicates how a policy violation reported by an analyzer is enforced.
+type EnforcementLevel string
+
+const (
+	// Mandatory violations are reported as errors, and fail the preview or update that produced them.
+	Mandatory EnforcementLevel = "mandatory"
+	// Advisory violations are reported as warnings, but do not fail the preview or update that produced them.
+	Advisory EnforcementLevel = "advisory"
+	// Disabled policies are not enforced at all; any violations of them are ignored.
+	Disabled EnforcementLevel = "disabled"
+)
+
+// IsValid returns true if the enforcement level is one of the known levels.
+func (level EnforcementLevel) IsValid() bool {
+	switch level {
+	case Mandatory, Advisory, Disabled:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProjectPolicy configures how the policies checked by a project's analyzers are enforced.
+// nolint: lll
+type ProjectPolicy struct {
+	Rules map[string]EnforcementLevel `json:"rules,omitempty" yaml:"rules,omitempty"` // enforcement level overrides, keyed by policy name.
+}
+
+// EnforcementLevel returns the enforcement level for a violation of the given policy, given the level that the analyzer
+// that reported the violation asked for.
+func (policy *ProjectPolicy) EnforcementLevel(name string, level EnforcementLevel) EnforcementLevel {
+	if policy != nil {
+		if override, has := policy.Rules[name]; has {
+			return override
+		}
+	}
+	if level == "" {
+		return Mandatory
+	}
+	return level
+}
+
 // ProjectTemplate is a Pulumi project template manifest.
 // nolint: lll
 type ProjectTemplate struct {
@@ -66,7 +108,8 @@ type Project struct {
 	Website     *string `json:"website,omitempty" yaml:"website,omitempty"`         // an optional website for additional info.
 	License     *string `json:"license,omitempty" yaml:"license,omitempty"`         // an optional license governing this project's usage.
 
-	Analyzers *Analyzers `json:"analyzers,omitempty" yaml:"analyzers,omitempty"` // any analyzers enabled for this project.
+	Analyzers *Analyzers     `json:"analyzers,omitempty" yaml:"analyzers,omitempty"` // any analyzers enabled for this project.
+	Policy    *ProjectPolicy `json:"policy,omitempty" yaml:"policy,omitempty"`       // optional enforcement settings for the analyzers' policies.
 
 	Context          string `json:"context,omitempty" yaml:"context,omitempty"`                   // an optional path (combined with the on disk location of Pulumi.yaml) to control the data uploaded to the service.
 	NoDefaultIgnores *bool  `json:"nodefaultignores,omitempty" yaml:"nodefaultignores,omitempty"` // true if we should only respect .pulumiignore when archiving
@@ -83,6 +126,14 @@ func (proj *Project) Validate() error {
 	if proj.RuntimeInfo.Name() == "" {
 		return errors.New("project is missing a 'runtime' attribute")
 	}
+	if proj.Policy != nil {
+		for name, level := range proj.Policy.Rules {
+			if !level.IsValid() {
+				return errors.Errorf("policy '%v' has an unknown enforcement level '%v'; expected one of "+
+					"'mandatory', 'advisory', or 'disabled'", name, level)
+			}
+		}
+	}
 
 	return nil
 }
@@ -117,8 +168,9 @@ func (proj *Project) Save(path string) error {
 // ProjectStack holds stack specific information about a project.
 // nolint: lll
 type ProjectStack struct {
-	EncryptionSalt string     `json:"encryptionsalt,omitempty" yaml:"encryptionsalt,omitempty"` // base64 encoded encryption salt.
-	Config         config.Map `json:"config,omitempty" yaml:"config,omitempty"`                 // optional config.
+	SecretsProvider string     `json:"secretsprovider,omitempty" yaml:"secretsprovider,omitempty"` // secrets provider.
+	EncryptionSalt  string     `json:"encryptionsalt,omitempty" yaml:"encryptionsalt,omitempty"`   // base64 encoded encryption salt.
+	Config          config.Map `json:"config,omitempty" yaml:"config,omitempty"`                   // optional config.
 }
 
 // Save writes a project definition to a file.
@@ -36,3 +36,28 @@ func TestProjectRuntimeInfoRoundtripYAML(t *testing.T) {
 	doTest(yaml.Marshal, yaml.Unmarshal)
 	doTest(json.Marshal, json.Unmarshal)
 }
+
+func TestProjectPolicy(t *testing.T) {
+	var proj Project
+	err := yaml.Unmarshal([]byte(`name: test
+runtime: nodejs
+policy:
+  rules:
+    no-public-buckets: advisory
+    required-tags: disabled
+`), &proj)
+	assert.NoError(t, err)
+	assert.NoError(t, proj.Validate())
+
+	assert.Equal(t, Advisory, proj.Policy.EnforcementLevel("no-public-buckets", Mandatory))
+	assert.Equal(t, Disabled, proj.Policy.EnforcementLevel("required-tags", Advisory))
+	assert.Equal(t, Advisory, proj.Policy.EnforcementLevel("other", Advisory))
+	assert.Equal(t, Mandatory, proj.Policy.EnforcementLevel("other", ""))
+
+	// A project without a policy section leaves every level as the analyzer reported it.
+	var nopolicy *ProjectPolicy
+	assert.Equal(t, Advisory, nopolicy.EnforcementLevel("no-public-buckets", Advisory))
+
+	proj.Policy.Rules["required-tags"] = "sometimes"
+	assert.Error(t, proj.Validate())
+}
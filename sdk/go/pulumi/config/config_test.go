@@ -77,3 +77,39 @@ func TestConfig(t *testing.T) {
 	_, err = cfg.Try("missing")
 	assert.NotNil(t, err)
 }
+
+// TestStructuredConfig tests loading structured config values.
+func TestStructuredConfig(t *testing.T) {
+	ctx, err := pulumi.NewContext(context.Background(), pulumi.RunInfo{
+		Config: map[string]string{
+			"testpkg:vpc":     `{"cidr":"10.0.0.0/16","subnets":["10.0.0.0/24","10.0.1.0/24"]}`,
+			"testpkg:invalid": "not json",
+		},
+	})
+	assert.Nil(t, err)
+
+	cfg := New(ctx, "testpkg")
+
+	type vpc struct {
+		CIDR    string   `json:"cidr"`
+		Subnets []string `json:"subnets"`
+	}
+	expected := vpc{CIDR: "10.0.0.0/16", Subnets: []string{"10.0.0.0/24", "10.0.1.0/24"}}
+
+	var v1 vpc
+	assert.Nil(t, cfg.GetObject("vpc", &v1))
+	assert.Equal(t, expected, v1)
+	var missing vpc
+	assert.Nil(t, cfg.GetObject("missing", &missing))
+	assert.Equal(t, vpc{}, missing)
+
+	var v2 vpc
+	cfg.RequireObject("vpc", &v2)
+	assert.Equal(t, expected, v2)
+
+	var v3 vpc
+	assert.Nil(t, cfg.TryObject("vpc", &v3))
+	assert.Equal(t, expected, v3)
+	assert.NotNil(t, cfg.TryObject("missing", &v3))
+	assert.NotNil(t, cfg.TryObject("invalid", &v3))
+}
@@ -15,6 +15,8 @@
 package config
 
 import (
+	"encoding/json"
+
 	"github.com/spf13/cast"
 
 	"github.com/pulumi/pulumi/sdk/go/pulumi"
@@ -90,6 +92,15 @@ func GetInt64(ctx *pulumi.Context, key string) int64 {
 	return 0
 }
 
+// GetObject loads an optional structured configuration value by its key, unmarshaling its JSON representation into
+// output, which must be a pointer.  If the key doesn't exist, output is left untouched.
+func GetObject(ctx *pulumi.Context, key string, output interface{}) error {
+	if v, ok := ctx.GetConfig(key); ok {
+		return json.Unmarshal([]byte(v), output)
+	}
+	return nil
+}
+
 // GetUint loads an optional configuration value by its key, as a uint, or returns 0 if it doesn't exist.
 func GetUint(ctx *pulumi.Context, key string) uint {
 	if v, ok := ctx.GetConfig(key); ok {
@@ -15,6 +15,8 @@
 package config
 
 import (
+	"encoding/json"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cast"
 
@@ -103,6 +105,17 @@ func TryInt64(ctx *pulumi.Context, key string) (int64, error) {
 	return cast.ToInt64(v), nil
 }
 
+// TryObject loads a structured configuration value by its key, unmarshaling its JSON representation into output,
+// which must be a pointer.  It returns an error if the key doesn't exist or the value can't be unmarshaled.
+func TryObject(ctx *pulumi.Context, key string, output interface{}) error {
+	v, err := Try(ctx, key)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(json.Unmarshal([]byte(v), output),
+		"unable to unmarshal configuration variable '%s'", key)
+}
+
 // TryUint loads an optional configuration value by its key, as a uint, or returns an error if it doesn't exist.
 func TryUint(ctx *pulumi.Context, key string) (uint, error) {
 	v, err := Try(ctx, key)
@@ -41,6 +41,10 @@ type Context struct {
 	rpcs        int         // the number of outstanding RPC requests.
 	rpcsDone    *sync.Cond  // an event signaling completion of RPCs.
 	rpcsLock    *sync.Mutex // a lock protecting the RPC count and event.
+
+	transformations      []ResourceTransformation         // stack transformations, applied to every resource.
+	childTransformations map[URN][]ResourceTransformation // the transformations inherited by each resource's children.
+	transformationsLock  *sync.Mutex                      // a lock protecting the transformations.
 }
 
 // NewContext creates a fresh run context out of the given metadata.
@@ -70,16 +74,18 @@ func NewContext(ctx context.Context, info RunInfo) (*Context, error) {
 
 	mutex := &sync.Mutex{}
 	return &Context{
-		ctx:         ctx,
-		info:        info,
-		exports:     make(map[string]interface{}),
-		monitorConn: monitorConn,
-		monitor:     monitor,
-		engineConn:  engineConn,
-		engine:      engine,
-		rpcs:        0,
-		rpcsLock:    mutex,
-		rpcsDone:    sync.NewCond(mutex),
+		ctx:                  ctx,
+		info:                 info,
+		exports:              make(map[string]interface{}),
+		monitorConn:          monitorConn,
+		monitor:              monitor,
+		engineConn:           engineConn,
+		engine:               engine,
+		rpcs:                 0,
+		rpcsLock:             mutex,
+		rpcsDone:             sync.NewCond(mutex),
+		childTransformations: make(map[URN][]ResourceTransformation),
+		transformationsLock:  &sync.Mutex{},
 	}, nil
 }
 
@@ -175,8 +181,14 @@ func (ctx *Context) ReadResource(
 		return nil, errors.New("resource ID is required for lookup and cannot be empty")
 	}
 
+	// Apply any transformations to the resource.
+	name, props, opt, inherited, err := ctx.transformResource(t, name, true, props, opts...)
+	if err != nil {
+		return nil, err
+	}
+
 	// Prepare the inputs for an impending operation.
-	op, err := ctx.newResourceOperation(true, props, opts...)
+	op, err := ctx.newResourceOperation(true, props, opt)
 	if err != nil {
 		return nil, err
 	}
@@ -207,6 +219,7 @@ func (ctx *Context) ReadResource(
 		if resp != nil {
 			urn, resID = resp.Urn, string(id)
 			props = resp.Properties
+			ctx.recordChildTransformations(URN(urn), inherited)
 		}
 		op.complete(err, urn, resID, props)
 
@@ -236,8 +249,14 @@ func (ctx *Context) RegisterResource(
 		return nil, errors.New("resource name argument (for URN creation) cannot be empty")
 	}
 
+	// Apply any transformations to the resource.
+	name, props, opt, inherited, err := ctx.transformResource(t, name, custom, props, opts...)
+	if err != nil {
+		return nil, err
+	}
+
 	// Prepare the inputs for an impending operation.
-	op, err := ctx.newResourceOperation(custom, props, opts...)
+	op, err := ctx.newResourceOperation(custom, props, opt)
 	if err != nil {
 		return nil, err
 	}
@@ -252,13 +271,15 @@ func (ctx *Context) RegisterResource(
 	go func() {
 		glog.V(9).Infof("RegisterResource(%s, %s): Goroutine spawned, RPC call being made", t, name)
 		resp, err := ctx.monitor.RegisterResource(ctx.ctx, &pulumirpc.RegisterResourceRequest{
-			Type:         t,
-			Name:         name,
-			Parent:       op.parent,
-			Object:       op.rpcProps,
-			Custom:       custom,
-			Protect:      op.protect,
-			Dependencies: op.deps,
+			Type:          t,
+			Name:          name,
+			Parent:        op.parent,
+			Object:        op.rpcProps,
+			Custom:        custom,
+			Protect:       op.protect,
+			Dependencies:  op.deps,
+			ImportId:      string(ctx.getOptsImport(opt)),
+			IgnoreChanges: ctx.getOptsIgnoreChanges(opt),
 		})
 		if err != nil {
 			glog.V(9).Infof("RegisterResource(%s, %s): error: %v", t, name, err)
@@ -272,6 +293,7 @@ func (ctx *Context) RegisterResource(
 		if resp != nil {
 			urn, resID = resp.Urn, resp.Id
 			props = resp.Object
+			ctx.recordChildTransformations(URN(urn), inherited)
 		}
 		op.complete(err, urn, resID, props)
 
@@ -421,6 +443,93 @@ type resourceOutput struct {
 	reject  func(error)
 }
 
+// RegisterStackTransformation registers a transformation that is applied to every resource subsequently registered or
+// read in the stack, including the children of component resources. Stack transformations are applied after any
+// transformations in a resource's own options or inherited from its parents, in the order they were registered.
+func (ctx *Context) RegisterStackTransformation(t ResourceTransformation) error {
+	if t == nil {
+		return errors.New("transformation cannot be nil")
+	}
+
+	ctx.transformationsLock.Lock()
+	defer ctx.transformationsLock.Unlock()
+	ctx.transformations = append(ctx.transformations, t)
+	return nil
+}
+
+// transformResource applies a resource's transformations, followed by those inherited from its parents and finally the
+// stack's transformations, to the given resource. It returns the resulting name, properties and options, along with
+// the transformations that the resource's children are to inherit.
+func (ctx *Context) transformResource(t, name string, custom bool, props map[string]interface{},
+	opts ...ResourceOpt) (string, map[string]interface{}, ResourceOpt, []ResourceTransformation, error) {
+	opt := mergeOpts(opts...)
+
+	// Note that we look up the parent's URN before taking the lock, as it may block.
+	var parent URN
+	if opt.Parent != nil {
+		parent = opt.Parent.URN()
+	}
+
+	ctx.transformationsLock.Lock()
+	var inherited []ResourceTransformation
+	inherited = append(inherited, opt.Transformations...)
+	inherited = append(inherited, ctx.childTransformations[parent]...)
+	transformations := append(append([]ResourceTransformation(nil), inherited...), ctx.transformations...)
+	ctx.transformationsLock.Unlock()
+
+	for _, transformation := range transformations {
+		// Give each transformation its own copy of the properties, so that the caller's are left untouched.
+		args := &ResourceTransformationArgs{Type: t, Name: name, Custom: custom, Opts: opt}
+		if props != nil {
+			args.Props = make(map[string]interface{}, len(props))
+			for k, v := range props {
+				args.Props[k] = v
+			}
+		}
+
+		result := transformation(args)
+		if result == nil {
+			continue
+		}
+		if result.Name == "" {
+			return "", nil, ResourceOpt{}, nil,
+				errors.Errorf("transformation of resource %s (%s) returned an empty name", name, t)
+		}
+		name, props, opt = result.Name, result.Props, result.Opts
+	}
+
+	return name, props, opt, inherited, nil
+}
+
+// recordChildTransformations records the transformations that the children of the given resource are to inherit.
+func (ctx *Context) recordChildTransformations(urn URN, transformations []ResourceTransformation) {
+	if urn == "" || len(transformations) == 0 {
+		return
+	}
+
+	ctx.transformationsLock.Lock()
+	defer ctx.transformationsLock.Unlock()
+	ctx.childTransformations[urn] = transformations
+}
+
+// mergeOpts merges an array of resource options into a single set of options.
+func mergeOpts(opts ...ResourceOpt) ResourceOpt {
+	var merged ResourceOpt
+	for _, opt := range opts {
+		if merged.Parent == nil {
+			merged.Parent = opt.Parent
+		}
+		merged.DependsOn = append(merged.DependsOn, opt.DependsOn...)
+		merged.Protect = merged.Protect || opt.Protect
+		if merged.Import == "" {
+			merged.Import = opt.Import
+		}
+		merged.IgnoreChanges = append(merged.IgnoreChanges, opt.IgnoreChanges...)
+		merged.Transformations = append(merged.Transformations, opt.Transformations...)
+	}
+	return merged
+}
+
 // getOpts returns a set of resource options from an array of them.  This includes the parent URN, any
 // dependency URNs, and a boolean indicating whether the resource is to be protected.
 func (ctx *Context) getOpts(opts ...ResourceOpt) (URN, []URN, bool) {
@@ -460,6 +569,25 @@ func (ctx *Context) getOptsProtect(opts ...ResourceOpt) bool {
 	return false
 }
 
+// getOptsImport returns the ID of the existing resource, if any, that a resource's options indicate is to be imported.
+func (ctx *Context) getOptsImport(opts ...ResourceOpt) ID {
+	for _, opt := range opts {
+		if opt.Import != "" {
+			return opt.Import
+		}
+	}
+	return ""
+}
+
+// getOptsIgnoreChanges returns the property paths whose changes a resource's options indicate are to be ignored.
+func (ctx *Context) getOptsIgnoreChanges(opts ...ResourceOpt) []string {
+	var paths []string
+	for _, opt := range opts {
+		paths = append(paths, opt.IgnoreChanges...)
+	}
+	return paths
+}
+
 // noMoreRPCs is a sentinel value used to stop subsequent RPCs from occurring.
 const noMoreRPCs = -1
 
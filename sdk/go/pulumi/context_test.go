@@ -0,0 +1,162 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	pulumirpc "github.com/pulumi/pulumi/sdk/proto/go"
+)
+
+// testMonitor is a resource monitor that records registrations and echoes back their inputs.
+type testMonitor struct {
+	lock          sync.Mutex
+	registrations map[string]*pulumirpc.RegisterResourceRequest
+}
+
+func (m *testMonitor) Invoke(ctx context.Context, in *pulumirpc.InvokeRequest,
+	opts ...grpc.CallOption) (*pulumirpc.InvokeResponse, error) {
+	return &pulumirpc.InvokeResponse{}, nil
+}
+
+func (m *testMonitor) ReadResource(ctx context.Context, in *pulumirpc.ReadResourceRequest,
+	opts ...grpc.CallOption) (*pulumirpc.ReadResourceResponse, error) {
+	return &pulumirpc.ReadResourceResponse{
+		Urn:        fmt.Sprintf("urn:pulumi:stack::project::%s::%s", in.Type, in.Name),
+		Properties: in.Properties,
+	}, nil
+}
+
+func (m *testMonitor) RegisterResource(ctx context.Context, in *pulumirpc.RegisterResourceRequest,
+	opts ...grpc.CallOption) (*pulumirpc.RegisterResourceResponse, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.registrations[in.Name] = in
+	return &pulumirpc.RegisterResourceResponse{
+		Urn:    fmt.Sprintf("urn:pulumi:stack::project::%s::%s", in.Type, in.Name),
+		Id:     in.Name + "-id",
+		Object: in.Object,
+	}, nil
+}
+
+func (m *testMonitor) RegisterResourceOutputs(ctx context.Context, in *pulumirpc.RegisterResourceOutputsRequest,
+	opts ...grpc.CallOption) (*empty.Empty, error) {
+	return &empty.Empty{}, nil
+}
+
+// testComponent is a component resource whose URN is known.
+type testComponent struct {
+	urn URN
+}
+
+func (c *testComponent) URN() URN { return c.urn }
+
+func TestTransformations(t *testing.T) {
+	monitor := &testMonitor{registrations: make(map[string]*pulumirpc.RegisterResourceRequest)}
+	ctx, err := NewContext(context.Background(), RunInfo{Project: "project", Stack: "stack"})
+	assert.NoError(t, err)
+	ctx.monitor = monitor
+
+	// The stack transformation sets the owner of every bucket and protects everything.
+	var order []string
+	err = ctx.RegisterStackTransformation(func(args *ResourceTransformationArgs) *ResourceTransformationResult {
+		order = append(order, "stack:"+args.Name)
+		if args.Type == "test:index:Bucket" {
+			args.Props["owner"] = "ops"
+		}
+		args.Opts.Protect = true
+		return &ResourceTransformationResult{Name: args.Name, Props: args.Props, Opts: args.Opts}
+	})
+	assert.NoError(t, err)
+	assert.Error(t, ctx.RegisterStackTransformation(nil))
+
+	// The component's transformation prefixes the names of the component and all of its children.
+	prefix := func(args *ResourceTransformationArgs) *ResourceTransformationResult {
+		order = append(order, "component:"+args.Name)
+		return &ResourceTransformationResult{Name: "prefix-" + args.Name, Props: args.Props, Opts: args.Opts}
+	}
+	comp, err := ctx.RegisterResource("test:index:Component", "comp", false, nil,
+		ResourceOpt{Transformations: []ResourceTransformation{prefix}})
+	assert.NoError(t, err)
+	compURN, err := comp.URN.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, URN("urn:pulumi:stack::project::test:index:Component::prefix-comp"), compURN)
+
+	props := map[string]interface{}{"acl": "private"}
+	_, err = ctx.RegisterResource("test:index:Bucket", "child", true, props,
+		ResourceOpt{Parent: &testComponent{urn: compURN}})
+	assert.NoError(t, err)
+	_, err = ctx.RegisterResource("test:index:Bucket", "sibling", true, props)
+	assert.NoError(t, err)
+
+	// Transformations that return nil leave the resource unchanged, and empty names are rejected.
+	_, err = ctx.RegisterResource("test:index:Other", "unchanged", true, nil,
+		ResourceOpt{Transformations: []ResourceTransformation{
+			func(*ResourceTransformationArgs) *ResourceTransformationResult { return nil },
+		}})
+	assert.NoError(t, err)
+	_, err = ctx.RegisterResource("test:index:Other", "nameless", true, nil,
+		ResourceOpt{Transformations: []ResourceTransformation{
+			func(*ResourceTransformationArgs) *ResourceTransformationResult {
+				return &ResourceTransformationResult{}
+			},
+		}})
+	assert.Error(t, err)
+
+	ctx.waitForRPCs()
+
+	// The caller's properties are left untouched.
+	assert.Equal(t, map[string]interface{}{"acl": "private"}, props)
+
+	assert.Equal(t, []string{
+		"component:comp", "stack:prefix-comp",
+		"component:child", "stack:prefix-child",
+		"stack:sibling",
+		"stack:unchanged",
+	}, order)
+
+	assert.Len(t, monitor.registrations, 4)
+	assert.True(t, monitor.registrations["prefix-comp"].Protect)
+
+	child := monitor.registrations["prefix-child"]
+	if assert.NotNil(t, child) {
+		assert.Equal(t, string(compURN), child.Parent)
+		assert.True(t, child.Protect)
+		childProps, err := unmarshalOutputs(child.Object)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"acl":   "private",
+			"owner": "ops",
+		}, childProps)
+	}
+
+	sibling := monitor.registrations["sibling"]
+	if assert.NotNil(t, sibling) {
+		assert.Equal(t, "", sibling.Parent)
+		assert.True(t, sibling.Protect)
+	}
+
+	unchanged := monitor.registrations["unchanged"]
+	if assert.NotNil(t, unchanged) {
+		assert.Equal(t, "test:index:Other", unchanged.Type)
+	}
+}
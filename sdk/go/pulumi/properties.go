@@ -32,9 +32,10 @@ type Output struct {
 
 // outputState is a heap-allocated block of state for each output property, in case of aliasing.
 type outputState struct {
-	sync chan *valueOrError // the channel for outputs whose values are not yet known.
-	voe  *valueOrError      // the value or error, after the channel has been rendezvoused with.
-	deps []Resource         // the dependencies associated with this output property.
+	sync   chan *valueOrError // the channel for outputs whose values are not yet known.
+	voe    *valueOrError      // the value or error, after the channel has been rendezvoused with.
+	deps   []Resource         // the dependencies associated with this output property.
+	secret bool               // true if this output's value is secret.
 }
 
 // valueOrError is a discriminated union between a value (possibly nil) or an error.
@@ -86,6 +87,7 @@ func (out *Output) reject(err error) {
 // does not block awaiting the value; instead, it spawns a Goroutine that will await its availability.
 func (out *Output) Apply(applier func(v interface{}) (interface{}, error)) *Output {
 	result, resolve, reject := NewOutput(out.Deps())
+	result.s.secret = out.IsSecret() // values derived from secrets are themselves secret.
 	go func() {
 		for {
 			v, known, err := out.Value()
@@ -125,6 +127,26 @@ func (out *Output) Apply(applier func(v interface{}) (interface{}, error)) *Outp
 // Deps returns the dependencies for this output property.
 func (out *Output) Deps() []Resource { return out.s.deps }
 
+// IsSecret returns true if this output property's value is secret.
+func (out *Output) IsSecret() bool { return out.s.secret }
+
+// Secret returns an output property that resolves to the same value as the given one, but marked secret.  Secret
+// values are encrypted when they are stored in the stack's checkpoint and are masked when they are displayed.  Any
+// output derived from a secret by Apply is secret as well.
+func Secret(out *Output) *Output {
+	result, resolve, reject := NewOutput(out.Deps())
+	result.s.secret = true
+	go func() {
+		v, known, err := out.Value()
+		if err != nil {
+			reject(err)
+		} else {
+			resolve(v, known)
+		}
+	}()
+	return result
+}
+
 // Value retrieves the underlying value for this output property.
 func (out *Output) Value() (interface{}, bool, error) {
 	// If neither error nor value are available, first await the channel.  Only one Goroutine will make it through this
@@ -51,4 +51,47 @@ type ResourceOpt struct {
 	DependsOn []Resource
 	// Protect, when set to true, ensures that this resource cannot be deleted (without first setting it to false).
 	Protect bool
+	// Import, when provided with a resource ID, indicates that this resource's provider should import its state from
+	// the cloud resource with the given ID. The inputs to the resource's constructor must align with the resource's
+	// current state. Once a resource has been imported, the import property must be removed from the resource's
+	// options.
+	Import ID
+	// IgnoreChanges is an optional list of property paths whose changes should be ignored when the resource is
+	// updated. The values for these properties are taken from the resource's current state rather than from its
+	// constructor's inputs. Paths may refer to nested properties, e.g. "tags.owner" or "rules[0].port". If the
+	// resource must be replaced, the replacement is created using the constructor's inputs.
+	IgnoreChanges []string
+	// Transformations is an optional list of transformations to apply to this resource before it is registered, and
+	// to each of its children in turn. They are applied before any transformations inherited from the resource's
+	// parents, which are in turn applied before any stack transformations.
+	Transformations []ResourceTransformation
+}
+
+// ResourceTransformation is a callback that may rewrite the name, properties and options of a resource before the
+// resource is registered. It may return nil to leave the resource unchanged.
+type ResourceTransformation func(args *ResourceTransformationArgs) *ResourceTransformationResult
+
+// ResourceTransformationArgs describes a resource that is about to be registered.
+type ResourceTransformationArgs struct {
+	// Type is the type token of the resource.
+	Type string
+	// Name is the name of the resource.
+	Name string
+	// Custom is true if the resource is a custom resource, and false if it is a component resource.
+	Custom bool
+	// Props is a copy of the resource's input properties, which the transformation is free to modify.
+	Props map[string]interface{}
+	// Opts is the resource's options, merged into a single set.
+	Opts ResourceOpt
+}
+
+// ResourceTransformationResult is the result of a transformation, replacing the resource's name, properties and
+// options. Note that changes to the transformations in the options have no effect.
+type ResourceTransformationResult struct {
+	// Name is the new name of the resource, which must not be empty.
+	Name string
+	// Props is the new set of input properties for the resource.
+	Props map[string]interface{}
+	// Opts is the new set of options for the resource.
+	Opts ResourceOpt
 }
@@ -55,7 +55,7 @@ func marshalInputs(props map[string]interface{}) ([]string, *structpb.Struct, []
 	// Marshal all properties for the RPC call.
 	m, err := plugin.MarshalProperties(
 		resource.NewPropertyMapFromMap(pmap),
-		plugin.MarshalOptions{KeepUnknowns: true},
+		plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true},
 	)
 	return keys, m, depURNs, err
 }
@@ -188,6 +188,9 @@ func marshalInputOutput(out *Output) (interface{}, []Resource, error) {
 		if merr != nil {
 			return nil, nil, merr
 		}
+		if out.IsSecret() {
+			e = resource.Secret{Element: resource.NewPropertyValue(e)}
+		}
 		return e, append(out.Deps(), d...), nil
 	}
 
@@ -19,6 +19,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/pulumi/pulumi/pkg/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/go/pulumi/asset"
 )
 
@@ -86,3 +87,32 @@ func TestMarshalRoundtrip(t *testing.T) {
 		}
 	}
 }
+
+// TestMarshalSecret ensures that secret outputs are marked as such on the wire, and that derived outputs stay secret.
+func TestMarshalSecret(t *testing.T) {
+	out, resolve, _ := NewOutput(nil)
+	resolve("hunter2", true)
+	secret := Secret(out)
+	assert.False(t, out.IsSecret())
+	assert.True(t, secret.IsSecret())
+
+	derived := secret.Apply(func(v interface{}) (interface{}, error) { return v.(string) + "!", nil })
+	assert.True(t, derived.IsSecret())
+
+	_, m, _, err := marshalInputs(map[string]interface{}{"password": derived})
+	if assert.Nil(t, err) {
+		props, err := plugin.UnmarshalProperties(m, plugin.MarshalOptions{KeepSecrets: true})
+		if assert.Nil(t, err) {
+			password := props["password"]
+			if assert.True(t, password.IsSecret()) {
+				assert.Equal(t, "hunter2!", password.SecretValue().Element.StringValue())
+			}
+		}
+
+		// Without secret support, the plain value is returned.
+		res, err := unmarshalOutputs(m)
+		if assert.Nil(t, err) {
+			assert.Equal(t, "hunter2!", res["password"])
+		}
+	}
+}
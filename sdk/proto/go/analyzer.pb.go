@@ -25,6 +25,34 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+// EnforcementLevel indicates how a policy violation is enforced.  Mandatory violations fail the operation, advisory
+// violations are reported as warnings, and violations of disabled policies are ignored altogether.
+type EnforcementLevel int32
+
+const (
+	EnforcementLevel_MANDATORY EnforcementLevel = 0
+	EnforcementLevel_ADVISORY  EnforcementLevel = 1
+	EnforcementLevel_DISABLED  EnforcementLevel = 2
+)
+
+var EnforcementLevel_name = map[int32]string{
+	0: "MANDATORY",
+	1: "ADVISORY",
+	2: "DISABLED",
+}
+var EnforcementLevel_value = map[string]int32{
+	"MANDATORY": 0,
+	"ADVISORY":  1,
+	"DISABLED":  2,
+}
+
+func (x EnforcementLevel) String() string {
+	return proto.EnumName(EnforcementLevel_name, int32(x))
+}
+func (EnforcementLevel) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_analyzer_4d94ca02b59f2385, []int{0}
+}
+
 type AnalyzeRequest struct {
 	Type                 string          `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
 	Properties           *_struct.Struct `protobuf:"bytes,2,opt,name=properties" json:"properties,omitempty"`
@@ -71,6 +99,122 @@ func (m *AnalyzeRequest) GetProperties() *_struct.Struct {
 	return nil
 }
 
+type AnalyzeStackRequest struct {
+	Resources            []*AnalyzerResource `protobuf:"bytes,1,rep,name=resources" json:"resources,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *AnalyzeStackRequest) Reset()         { *m = AnalyzeStackRequest{} }
+func (m *AnalyzeStackRequest) String() string { return proto.CompactTextString(m) }
+func (*AnalyzeStackRequest) ProtoMessage()    {}
+func (*AnalyzeStackRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_analyzer_4d94ca02b59f2385, []int{1}
+}
+func (m *AnalyzeStackRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AnalyzeStackRequest.Unmarshal(m, b)
+}
+func (m *AnalyzeStackRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AnalyzeStackRequest.Marshal(b, m, deterministic)
+}
+func (dst *AnalyzeStackRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AnalyzeStackRequest.Merge(dst, src)
+}
+func (m *AnalyzeStackRequest) XXX_Size() int {
+	return xxx_messageInfo_AnalyzeStackRequest.Size(m)
+}
+func (m *AnalyzeStackRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AnalyzeStackRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AnalyzeStackRequest proto.InternalMessageInfo
+
+func (m *AnalyzeStackRequest) GetResources() []*AnalyzerResource {
+	if m != nil {
+		return m.Resources
+	}
+	return nil
+}
+
+type AnalyzerResource struct {
+	Type                 string          `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	Properties           *_struct.Struct `protobuf:"bytes,2,opt,name=properties" json:"properties,omitempty"`
+	Urn                  string          `protobuf:"bytes,3,opt,name=urn" json:"urn,omitempty"`
+	Parent               string          `protobuf:"bytes,4,opt,name=parent" json:"parent,omitempty"`
+	Dependencies         []string        `protobuf:"bytes,5,rep,name=dependencies" json:"dependencies,omitempty"`
+	Provider             string          `protobuf:"bytes,6,opt,name=provider" json:"provider,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *AnalyzerResource) Reset()         { *m = AnalyzerResource{} }
+func (m *AnalyzerResource) String() string { return proto.CompactTextString(m) }
+func (*AnalyzerResource) ProtoMessage()    {}
+func (*AnalyzerResource) Descriptor() ([]byte, []int) {
+	return fileDescriptor_analyzer_4d94ca02b59f2385, []int{2}
+}
+func (m *AnalyzerResource) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AnalyzerResource.Unmarshal(m, b)
+}
+func (m *AnalyzerResource) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AnalyzerResource.Marshal(b, m, deterministic)
+}
+func (dst *AnalyzerResource) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AnalyzerResource.Merge(dst, src)
+}
+func (m *AnalyzerResource) XXX_Size() int {
+	return xxx_messageInfo_AnalyzerResource.Size(m)
+}
+func (m *AnalyzerResource) XXX_DiscardUnknown() {
+	xxx_messageInfo_AnalyzerResource.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AnalyzerResource proto.InternalMessageInfo
+
+func (m *AnalyzerResource) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *AnalyzerResource) GetProperties() *_struct.Struct {
+	if m != nil {
+		return m.Properties
+	}
+	return nil
+}
+
+func (m *AnalyzerResource) GetUrn() string {
+	if m != nil {
+		return m.Urn
+	}
+	return ""
+}
+
+func (m *AnalyzerResource) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *AnalyzerResource) GetDependencies() []string {
+	if m != nil {
+		return m.Dependencies
+	}
+	return nil
+}
+
+func (m *AnalyzerResource) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
 type AnalyzeResponse struct {
 	Failures             []*AnalyzeFailure `protobuf:"bytes,1,rep,name=failures" json:"failures,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
@@ -82,7 +226,7 @@ func (m *AnalyzeResponse) Reset()         { *m = AnalyzeResponse{} }
 func (m *AnalyzeResponse) String() string { return proto.CompactTextString(m) }
 func (*AnalyzeResponse) ProtoMessage()    {}
 func (*AnalyzeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_analyzer_4d94ca02b59f2385, []int{1}
+	return fileDescriptor_analyzer_4d94ca02b59f2385, []int{3}
 }
 func (m *AnalyzeResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AnalyzeResponse.Unmarshal(m, b)
@@ -110,18 +254,23 @@ func (m *AnalyzeResponse) GetFailures() []*AnalyzeFailure {
 }
 
 type AnalyzeFailure struct {
-	Property             string   `protobuf:"bytes,1,opt,name=property" json:"property,omitempty"`
-	Reason               string   `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Property             string           `protobuf:"bytes,1,opt,name=property" json:"property,omitempty"`
+	Reason               string           `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+	Urn                  string           `protobuf:"bytes,3,opt,name=urn" json:"urn,omitempty"`
+	PolicyName           string           `protobuf:"bytes,4,opt,name=policyName" json:"policyName,omitempty"`
+	Description          string           `protobuf:"bytes,5,opt,name=description" json:"description,omitempty"`
+	Severity             string           `protobuf:"bytes,6,opt,name=severity" json:"severity,omitempty"`
+	EnforcementLevel     EnforcementLevel `protobuf:"varint,7,opt,name=enforcementLevel,enum=pulumirpc.EnforcementLevel" json:"enforcementLevel,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *AnalyzeFailure) Reset()         { *m = AnalyzeFailure{} }
 func (m *AnalyzeFailure) String() string { return proto.CompactTextString(m) }
 func (*AnalyzeFailure) ProtoMessage()    {}
 func (*AnalyzeFailure) Descriptor() ([]byte, []int) {
-	return fileDescriptor_analyzer_4d94ca02b59f2385, []int{2}
+	return fileDescriptor_analyzer_4d94ca02b59f2385, []int{4}
 }
 func (m *AnalyzeFailure) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AnalyzeFailure.Unmarshal(m, b)
@@ -155,10 +304,48 @@ func (m *AnalyzeFailure) GetReason() string {
 	return ""
 }
 
+func (m *AnalyzeFailure) GetUrn() string {
+	if m != nil {
+		return m.Urn
+	}
+	return ""
+}
+
+func (m *AnalyzeFailure) GetPolicyName() string {
+	if m != nil {
+		return m.PolicyName
+	}
+	return ""
+}
+
+func (m *AnalyzeFailure) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *AnalyzeFailure) GetSeverity() string {
+	if m != nil {
+		return m.Severity
+	}
+	return ""
+}
+
+func (m *AnalyzeFailure) GetEnforcementLevel() EnforcementLevel {
+	if m != nil {
+		return m.EnforcementLevel
+	}
+	return EnforcementLevel_MANDATORY
+}
+
 func init() {
 	proto.RegisterType((*AnalyzeRequest)(nil), "pulumirpc.AnalyzeRequest")
+	proto.RegisterType((*AnalyzeStackRequest)(nil), "pulumirpc.AnalyzeStackRequest")
+	proto.RegisterType((*AnalyzerResource)(nil), "pulumirpc.AnalyzerResource")
 	proto.RegisterType((*AnalyzeResponse)(nil), "pulumirpc.AnalyzeResponse")
 	proto.RegisterType((*AnalyzeFailure)(nil), "pulumirpc.AnalyzeFailure")
+	proto.RegisterEnum("pulumirpc.EnforcementLevel", EnforcementLevel_name, EnforcementLevel_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -174,6 +361,9 @@ const _ = grpc.SupportPackageIsVersion4
 type AnalyzerClient interface {
 	// Analyze analyzes a single resource object, and returns any errors that it finds.
 	Analyze(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error)
+	// AnalyzeStack analyzes all resources within a stack, at the end of a successful preview or update. The
+	// resources are given in dependency order, and any failures reported fail the operation.
+	AnalyzeStack(ctx context.Context, in *AnalyzeStackRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error)
 	// GetPluginInfo returns generic information about this plugin, like its version.
 	GetPluginInfo(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*PluginInfo, error)
 }
@@ -195,6 +385,15 @@ func (c *analyzerClient) Analyze(ctx context.Context, in *AnalyzeRequest, opts .
 	return out, nil
 }
 
+func (c *analyzerClient) AnalyzeStack(ctx context.Context, in *AnalyzeStackRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error) {
+	out := new(AnalyzeResponse)
+	err := grpc.Invoke(ctx, "/pulumirpc.Analyzer/AnalyzeStack", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *analyzerClient) GetPluginInfo(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*PluginInfo, error) {
 	out := new(PluginInfo)
 	err := grpc.Invoke(ctx, "/pulumirpc.Analyzer/GetPluginInfo", in, out, c.cc, opts...)
@@ -209,6 +408,9 @@ func (c *analyzerClient) GetPluginInfo(ctx context.Context, in *empty.Empty, opt
 type AnalyzerServer interface {
 	// Analyze analyzes a single resource object, and returns any errors that it finds.
 	Analyze(context.Context, *AnalyzeRequest) (*AnalyzeResponse, error)
+	// AnalyzeStack analyzes all resources within a stack, at the end of a successful preview or update. The
+	// resources are given in dependency order, and any failures reported fail the operation.
+	AnalyzeStack(context.Context, *AnalyzeStackRequest) (*AnalyzeResponse, error)
 	// GetPluginInfo returns generic information about this plugin, like its version.
 	GetPluginInfo(context.Context, *empty.Empty) (*PluginInfo, error)
 }
@@ -235,6 +437,24 @@ func _Analyzer_Analyze_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Analyzer_AnalyzeStack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzeStackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyzerServer).AnalyzeStack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pulumirpc.Analyzer/AnalyzeStack",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyzerServer).AnalyzeStack(ctx, req.(*AnalyzeStackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Analyzer_GetPluginInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(empty.Empty)
 	if err := dec(in); err != nil {
@@ -261,6 +481,10 @@ var _Analyzer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Analyze",
 			Handler:    _Analyzer_Analyze_Handler,
 		},
+		{
+			MethodName: "AnalyzeStack",
+			Handler:    _Analyzer_AnalyzeStack_Handler,
+		},
 		{
 			MethodName: "GetPluginInfo",
 			Handler:    _Analyzer_GetPluginInfo_Handler,
@@ -273,23 +497,38 @@ var _Analyzer_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("analyzer.proto", fileDescriptor_analyzer_4d94ca02b59f2385) }
 
 var fileDescriptor_analyzer_4d94ca02b59f2385 = []byte{
-	// 285 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x91, 0x4f, 0x4b, 0xc3, 0x40,
-	0x10, 0xc5, 0x1b, 0x95, 0x9a, 0x8c, 0x5a, 0x61, 0xc0, 0x5a, 0x57, 0x0f, 0x25, 0x27, 0x4f, 0x5b,
-	0xa8, 0x88, 0x57, 0x15, 0xff, 0xde, 0x24, 0x9e, 0x3d, 0xa4, 0x65, 0x12, 0x02, 0x69, 0x76, 0xdd,
-	0x3f, 0x87, 0xf8, 0x29, 0xfc, 0xc8, 0xc6, 0xdd, 0x35, 0x16, 0xed, 0x6d, 0x86, 0xf7, 0xf8, 0xcd,
-	0x9b, 0x19, 0x18, 0xe5, 0x4d, 0x5e, 0xb7, 0x1f, 0xa4, 0xb8, 0x54, 0xc2, 0x08, 0x4c, 0xa4, 0xad,
-	0xed, 0xaa, 0x52, 0x72, 0xc9, 0xf6, 0x65, 0x6d, 0xcb, 0xaa, 0xf1, 0x02, 0x3b, 0x2d, 0x85, 0x28,
-	0x6b, 0x9a, 0xb9, 0x6e, 0x61, 0x8b, 0x19, 0xad, 0xa4, 0x69, 0x83, 0x78, 0xf6, 0x57, 0xd4, 0x46,
-	0xd9, 0xa5, 0xf1, 0x6a, 0xfa, 0x06, 0xa3, 0x1b, 0x3f, 0x25, 0xa3, 0x77, 0x4b, 0xda, 0x20, 0xc2,
-	0x8e, 0x69, 0x25, 0x4d, 0xa2, 0x69, 0x74, 0x9e, 0x64, 0xae, 0xc6, 0x2b, 0x80, 0xce, 0x2e, 0x49,
-	0x99, 0x8a, 0xf4, 0x64, 0xab, 0x53, 0xf6, 0xe6, 0xc7, 0xdc, 0x83, 0xf9, 0x0f, 0x98, 0xbf, 0x3a,
-	0x70, 0xb6, 0x66, 0x4d, 0x9f, 0xe0, 0xb0, 0xc7, 0x6b, 0x29, 0x1a, 0x4d, 0x78, 0x09, 0x71, 0x91,
-	0x57, 0xb5, 0x55, 0x1d, 0x29, 0x9a, 0x6e, 0x77, 0xa4, 0x13, 0xde, 0x2f, 0xc6, 0x83, 0xfb, 0xc1,
-	0x3b, 0xb2, 0xde, 0x9a, 0xde, 0xf5, 0x41, 0x83, 0x86, 0x0c, 0xe2, 0x30, 0xa9, 0x0d, 0x61, 0xfb,
-	0x1e, 0xc7, 0x30, 0x54, 0x94, 0x6b, 0xd1, 0xb8, 0xb0, 0x49, 0x16, 0xba, 0xf9, 0x67, 0x04, 0x71,
-	0xc0, 0x28, 0xbc, 0x85, 0xdd, 0x50, 0xe3, 0x86, 0x08, 0xe1, 0x1e, 0x8c, 0x6d, 0x92, 0xfc, 0x2e,
-	0xe9, 0x00, 0xaf, 0xe1, 0xe0, 0x91, 0xcc, 0x8b, 0xfb, 0xc6, 0x73, 0x53, 0x08, 0x1c, 0xff, 0x3b,
-	0xcb, 0xfd, 0xf7, 0x33, 0xd8, 0xd1, 0x1a, 0xe6, 0xd7, 0x9e, 0x0e, 0x16, 0x43, 0x67, 0xbc, 0xf8,
-	0x0a, 0x00, 0x00, 0xff, 0xff, 0xff, 0x6d, 0x9c, 0x46, 0xee, 0x01, 0x00, 0x00,
+	// 517 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x53, 0x5d, 0x8b, 0xd3, 0x40,
+	0x14, 0x6d, 0xb6, 0xbb, 0xdd, 0xe6, 0xb6, 0x5b, 0xc3, 0x88, 0x6b, 0xcc, 0xca, 0x12, 0xf2, 0x54,
+	0x7c, 0xc8, 0x42, 0x45, 0xc4, 0x07, 0xc1, 0x2e, 0xad, 0x6b, 0xa1, 0xae, 0x4b, 0x2a, 0x82, 0x0f,
+	0x3e, 0x64, 0xd3, 0xdb, 0x12, 0x4c, 0x67, 0xc6, 0xc9, 0xa4, 0x10, 0xff, 0xa1, 0x7f, 0xc2, 0x7f,
+	0x22, 0x48, 0x26, 0xd3, 0x6c, 0xfa, 0x01, 0xbe, 0xf8, 0x36, 0xe7, 0x9e, 0x33, 0xe7, 0xe6, 0x9e,
+	0xdc, 0x81, 0x5e, 0x48, 0xc3, 0x24, 0xff, 0x89, 0xc2, 0xe7, 0x82, 0x49, 0x46, 0x4c, 0x9e, 0x25,
+	0xd9, 0x2a, 0x16, 0x3c, 0x72, 0xba, 0x3c, 0xc9, 0x96, 0x31, 0x2d, 0x09, 0xe7, 0x62, 0xc9, 0xd8,
+	0x32, 0xc1, 0x2b, 0x85, 0xee, 0xb3, 0xc5, 0x15, 0xae, 0xb8, 0xcc, 0x35, 0xf9, 0x7c, 0x97, 0x4c,
+	0xa5, 0xc8, 0x22, 0x59, 0xb2, 0xde, 0x37, 0xe8, 0x0d, 0xcb, 0x2e, 0x01, 0xfe, 0xc8, 0x30, 0x95,
+	0x84, 0xc0, 0xb1, 0xcc, 0x39, 0xda, 0x86, 0x6b, 0xf4, 0xcd, 0x40, 0x9d, 0xc9, 0x6b, 0x00, 0x2e,
+	0x18, 0x47, 0x21, 0x63, 0x4c, 0xed, 0x23, 0xd7, 0xe8, 0x77, 0x06, 0x4f, 0xfd, 0xd2, 0xd8, 0xdf,
+	0x18, 0xfb, 0x33, 0x65, 0x1c, 0xd4, 0xa4, 0xde, 0x1d, 0x3c, 0xd6, 0xf6, 0x33, 0x19, 0x46, 0xdf,
+	0x37, 0x3d, 0xde, 0x80, 0x29, 0x30, 0x65, 0x99, 0x88, 0x30, 0xb5, 0x0d, 0xb7, 0xd9, 0xef, 0x0c,
+	0x2e, 0xfc, 0x6a, 0x3a, 0x5f, 0x5f, 0x11, 0x81, 0xd6, 0x04, 0x0f, 0x6a, 0xef, 0x97, 0x01, 0xd6,
+	0x2e, 0xff, 0x5f, 0xbf, 0x99, 0x58, 0xd0, 0xcc, 0x04, 0xb5, 0x9b, 0xca, 0xab, 0x38, 0x92, 0x73,
+	0x68, 0xf1, 0x50, 0x20, 0x95, 0xf6, 0xb1, 0x2a, 0x6a, 0x44, 0x3c, 0xe8, 0xce, 0x91, 0x23, 0x9d,
+	0x23, 0x8d, 0x8a, 0x26, 0x27, 0x6e, 0xb3, 0x6f, 0x06, 0x5b, 0x35, 0xe2, 0x40, 0x9b, 0x0b, 0xb6,
+	0x8e, 0xe7, 0x28, 0xec, 0x96, 0xba, 0x5d, 0x61, 0xef, 0x03, 0x3c, 0xaa, 0xc2, 0x4f, 0x39, 0xa3,
+	0x29, 0x92, 0x57, 0xd0, 0x5e, 0x84, 0x71, 0x92, 0x89, 0x2a, 0x98, 0x67, 0xfb, 0xc1, 0xbc, 0x2f,
+	0x15, 0x41, 0x25, 0xf5, 0xfe, 0x18, 0xd0, 0xdb, 0x26, 0x75, 0xe3, 0x62, 0xa8, 0x5c, 0xe7, 0x52,
+	0xe1, 0x62, 0x20, 0x81, 0x61, 0xca, 0xa8, 0xca, 0xc5, 0x0c, 0x34, 0x3a, 0x30, 0xfa, 0x25, 0x00,
+	0x67, 0x49, 0x1c, 0xe5, 0xb7, 0xe1, 0x0a, 0xf5, 0xf8, 0xb5, 0x0a, 0x71, 0xa1, 0x33, 0xc7, 0x34,
+	0x12, 0x31, 0x97, 0x31, 0xa3, 0xf6, 0x89, 0x12, 0xd4, 0x4b, 0xc5, 0x77, 0xa4, 0xb8, 0x46, 0x11,
+	0xcb, 0x7c, 0x13, 0xc0, 0x06, 0x93, 0x1b, 0xb0, 0x90, 0x2e, 0x98, 0x88, 0x70, 0x85, 0x54, 0x4e,
+	0x71, 0x8d, 0x89, 0x7d, 0xea, 0x1a, 0xfd, 0xde, 0xd6, 0x3a, 0x8c, 0x77, 0x24, 0xc1, 0xde, 0xa5,
+	0x17, 0x6f, 0xc1, 0xda, 0x55, 0x91, 0x33, 0x30, 0x3f, 0x0e, 0x6f, 0x47, 0xc3, 0xcf, 0x9f, 0x82,
+	0xaf, 0x56, 0x83, 0x74, 0xa1, 0x3d, 0x1c, 0x7d, 0x99, 0xcc, 0x0a, 0x64, 0x14, 0x68, 0x34, 0x99,
+	0x0d, 0xaf, 0xa7, 0xe3, 0x91, 0x75, 0x34, 0xf8, 0x6d, 0x40, 0x7b, 0xb3, 0x54, 0xe4, 0x1a, 0x4e,
+	0xf5, 0x99, 0x1c, 0xc8, 0x5e, 0xaf, 0xb0, 0xe3, 0x1c, 0xa2, 0xca, 0x9f, 0xe8, 0x35, 0xc8, 0x14,
+	0xba, 0xf5, 0xbd, 0x27, 0x97, 0xfb, 0xea, 0xfa, 0x83, 0xf8, 0x87, 0xdb, 0x3b, 0x38, 0xbb, 0x41,
+	0x79, 0xa7, 0x9e, 0xfc, 0x84, 0x2e, 0x18, 0x39, 0xdf, 0xdb, 0xe3, 0x71, 0xf1, 0xe2, 0x9d, 0x27,
+	0x35, 0x9b, 0x07, 0xb9, 0xd7, 0xb8, 0x6f, 0x29, 0xe1, 0xcb, 0xbf, 0x03, 0x00, 0x2a, 0xca, 0x01,
+	0x4d, 0x53, 0x04, 0x00, 0x00,
 }
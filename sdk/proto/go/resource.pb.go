@@ -169,6 +169,8 @@ type RegisterResourceRequest struct {
 	Protect              bool            `protobuf:"varint,6,opt,name=protect" json:"protect,omitempty"`
 	Dependencies         []string        `protobuf:"bytes,7,rep,name=dependencies" json:"dependencies,omitempty"`
 	Provider             string          `protobuf:"bytes,8,opt,name=provider" json:"provider,omitempty"`
+	ImportId             string          `protobuf:"bytes,9,opt,name=importId" json:"importId,omitempty"`
+	IgnoreChanges        []string        `protobuf:"bytes,10,rep,name=ignoreChanges" json:"ignoreChanges,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -254,6 +256,20 @@ func (m *RegisterResourceRequest) GetProvider() string {
 	return ""
 }
 
+func (m *RegisterResourceRequest) GetImportId() string {
+	if m != nil {
+		return m.ImportId
+	}
+	return ""
+}
+
+func (m *RegisterResourceRequest) GetIgnoreChanges() []string {
+	if m != nil {
+		return m.IgnoreChanges
+	}
+	return nil
+}
+
 // RegisterResourceResponse is returned by the engine after a resource has finished being initialized.  It includes the
 // auto-assigned URN, the provider-assigned ID, and any other properties initialized by the engine.
 type RegisterResourceResponse struct {
@@ -555,36 +571,38 @@ var _ResourceMonitor_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("resource.proto", fileDescriptor_resource_5aa1dff965971124) }
 
 var fileDescriptor_resource_5aa1dff965971124 = []byte{
-	// 491 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x94, 0xcd, 0x6e, 0xd3, 0x40,
-	0x10, 0xc7, 0x6b, 0xbb, 0x38, 0xe9, 0x50, 0x85, 0x6a, 0x41, 0xad, 0x31, 0xa8, 0x54, 0xe6, 0x02,
-	0x17, 0x47, 0x94, 0x03, 0x47, 0x4e, 0x1c, 0x38, 0x20, 0x84, 0x39, 0x83, 0xe4, 0xd8, 0x43, 0x64,
-	0x48, 0xbc, 0xcb, 0x7e, 0x54, 0xea, 0xd3, 0xf0, 0x66, 0x9c, 0x78, 0x0c, 0x0e, 0x78, 0x77, 0xbd,
-	0x26, 0xfe, 0x68, 0xd3, 0x53, 0xe6, 0x6b, 0x67, 0xe7, 0xff, 0xf3, 0x6c, 0x60, 0xc1, 0x51, 0x50,
-	0xc5, 0x0b, 0x4c, 0x19, 0xa7, 0x92, 0x92, 0x23, 0xa6, 0x36, 0x6a, 0x5b, 0x71, 0x56, 0xc4, 0x4f,
-	0xd6, 0x94, 0xae, 0x37, 0xb8, 0x34, 0x89, 0x95, 0xfa, 0xb6, 0xc4, 0x2d, 0x93, 0xd7, 0xb6, 0x2e,
-	0x7e, 0x3a, 0x4c, 0x0a, 0xc9, 0x55, 0x21, 0xdb, 0xec, 0xa2, 0xf9, 0xb9, 0xaa, 0x4a, 0xe4, 0xd6,
-	0x4f, 0x7e, 0x7b, 0xf0, 0x30, 0xc3, 0xbc, 0xcc, 0xda, 0xcb, 0x32, 0xfc, 0xa9, 0x50, 0x48, 0xb2,
-	0x00, 0xbf, 0x2a, 0x23, 0xef, 0xc2, 0x7b, 0x71, 0x94, 0x35, 0x16, 0x21, 0x70, 0x28, 0xaf, 0x19,
-	0x46, 0xbe, 0x89, 0x18, 0x5b, 0xc7, 0xea, 0x7c, 0x8b, 0x51, 0x60, 0x63, 0xda, 0x26, 0xa7, 0x10,
-	0xb2, 0x9c, 0x63, 0x2d, 0xa3, 0x43, 0x13, 0x6d, 0x3d, 0xf2, 0x06, 0xa0, 0xb9, 0x90, 0x21, 0x97,
-	0x15, 0x8a, 0xe8, 0x5e, 0x93, 0xbb, 0x7f, 0x79, 0x96, 0xda, 0x51, 0x53, 0x37, 0x6a, 0xfa, 0xd9,
-	0x8c, 0x9a, 0xed, 0x94, 0x92, 0x04, 0x8e, 0x4b, 0x64, 0x58, 0x97, 0x58, 0x17, 0xfa, 0x68, 0x78,
-	0x11, 0x34, 0x6d, 0x7b, 0x31, 0x12, 0xc3, 0xdc, 0xc9, 0x8a, 0x66, 0xe6, 0xda, 0xce, 0x4f, 0x72,
-	0x78, 0xd4, 0xd7, 0x27, 0x18, 0xad, 0x05, 0x92, 0x13, 0x08, 0x14, 0xaf, 0x5b, 0x85, 0xda, 0x1c,
-	0x8c, 0xe8, 0xdf, 0x79, 0xc4, 0xe4, 0xaf, 0x07, 0x67, 0x19, 0xae, 0x2b, 0x21, 0x91, 0x0f, 0x39,
-	0x3a, 0x6e, 0xde, 0x04, 0x37, 0x7f, 0x92, 0x5b, 0xd0, 0xe3, 0xd6, 0xc4, 0x0b, 0x25, 0x24, 0xdd,
-	0x1a, 0x9e, 0xf3, 0xac, 0xf5, 0xc8, 0x12, 0x42, 0xba, 0xfa, 0x8e, 0x85, 0xdc, 0xc7, 0xb2, 0x2d,
-	0x23, 0x11, 0xcc, 0x74, 0x4a, 0x9f, 0x08, 0x4d, 0x27, 0xe7, 0x8e, 0x08, 0xcf, 0xf6, 0x10, 0x9e,
-	0x0f, 0x08, 0xff, 0xf2, 0x20, 0x1a, 0xcb, 0xbf, 0x11, 0xb3, 0xdd, 0x2c, 0xbf, 0xdb, 0xac, 0xff,
-	0x4a, 0x82, 0xbb, 0x29, 0x69, 0x90, 0x08, 0x99, 0xaf, 0x36, 0xe8, 0x90, 0x58, 0x4f, 0x2b, 0xb4,
-	0x96, 0xde, 0x2f, 0x2d, 0xc1, 0xb9, 0x09, 0xc2, 0xf9, 0x70, 0xc0, 0x8f, 0x4a, 0x32, 0x25, 0x85,
-	0xfb, 0x4c, 0xe3, 0x31, 0x5f, 0xc1, 0x8c, 0xda, 0x9a, 0x7d, 0xab, 0xe0, 0xea, 0x2e, 0xff, 0xf8,
-	0xf0, 0xc0, 0xf5, 0xff, 0x40, 0xeb, 0x4a, 0x52, 0x4e, 0xde, 0x42, 0xf8, 0xbe, 0xbe, 0xa2, 0x3f,
-	0x9a, 0xf1, 0xd2, 0xee, 0x01, 0xa7, 0x36, 0xd4, 0x5e, 0x1e, 0x3f, 0x9e, 0xc8, 0x58, 0x7c, 0xc9,
-	0x01, 0xf9, 0x04, 0xc7, 0xbb, 0xfb, 0x4b, 0xce, 0x77, 0x8a, 0x27, 0x1e, 0x6e, 0xfc, 0xec, 0xc6,
-	0x7c, 0xd7, 0xf2, 0x0b, 0x9c, 0x0c, 0x71, 0x90, 0xa4, 0x77, 0x6c, 0x72, 0x97, 0xe3, 0xe7, 0xb7,
-	0xd6, 0x74, 0xed, 0xbf, 0x8e, 0x5f, 0x43, 0x4b, 0x9b, 0xbc, 0xbc, 0xa5, 0x43, 0xff, 0x8b, 0xc4,
-	0xa7, 0x23, 0xdc, 0xef, 0xf4, 0x9f, 0x5c, 0x72, 0xb0, 0x0a, 0x4d, 0xe4, 0xf5, 0xbf, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0xa3, 0x97, 0x3d, 0x93, 0x21, 0x05, 0x00, 0x00,
+	// 522 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x94, 0xcd, 0x8e, 0xd3, 0x30,
+	0x10, 0xc7, 0x37, 0xe9, 0x92, 0x6e, 0x87, 0xa5, 0xac, 0x0c, 0x6a, 0x4d, 0x40, 0x4b, 0x15, 0x38,
+	0x94, 0x4b, 0x2a, 0x96, 0x03, 0x47, 0x0e, 0x88, 0xc3, 0x1e, 0x10, 0x22, 0x9c, 0x41, 0x4a, 0x93,
+	0xa1, 0x04, 0xda, 0xd8, 0xf8, 0x63, 0xa5, 0x7d, 0x1a, 0xde, 0x84, 0x47, 0xe1, 0xc4, 0x83, 0x20,
+	0xdb, 0x71, 0x69, 0xda, 0x74, 0xbb, 0x37, 0xcf, 0xfc, 0xc7, 0xe3, 0xbf, 0x7f, 0xfe, 0x80, 0xa1,
+	0x40, 0xc9, 0xb4, 0x28, 0x30, 0xe5, 0x82, 0x29, 0x46, 0x06, 0x5c, 0x2f, 0xf5, 0xaa, 0x12, 0xbc,
+	0x88, 0x1f, 0x2f, 0x18, 0x5b, 0x2c, 0x71, 0x66, 0x85, 0xb9, 0xfe, 0x3a, 0xc3, 0x15, 0x57, 0xd7,
+	0xae, 0x2e, 0x7e, 0xb2, 0x2d, 0x4a, 0x25, 0x74, 0xa1, 0x1a, 0x75, 0xc8, 0x05, 0xbb, 0xaa, 0x4a,
+	0x14, 0x2e, 0x4e, 0xfe, 0x04, 0xf0, 0x20, 0xc3, 0xbc, 0xcc, 0x9a, 0xc5, 0x32, 0xfc, 0xa9, 0x51,
+	0x2a, 0x32, 0x84, 0xb0, 0x2a, 0x69, 0x30, 0x09, 0xa6, 0x83, 0x2c, 0xac, 0x4a, 0x42, 0xe0, 0x58,
+	0x5d, 0x73, 0xa4, 0xa1, 0xcd, 0xd8, 0xb1, 0xc9, 0xd5, 0xf9, 0x0a, 0x69, 0xcf, 0xe5, 0xcc, 0x98,
+	0x8c, 0x20, 0xe2, 0xb9, 0xc0, 0x5a, 0xd1, 0x63, 0x9b, 0x6d, 0x22, 0xf2, 0x1a, 0x80, 0x0b, 0xc6,
+	0x51, 0xa8, 0x0a, 0x25, 0xbd, 0x33, 0x09, 0xa6, 0x77, 0x2f, 0xc6, 0xa9, 0xb3, 0x9a, 0x7a, 0xab,
+	0xe9, 0x27, 0x6b, 0x35, 0xdb, 0x28, 0x25, 0x09, 0x9c, 0x96, 0xc8, 0xb1, 0x2e, 0xb1, 0x2e, 0xcc,
+	0xd4, 0x68, 0xd2, 0x9b, 0x0e, 0xb2, 0x56, 0x8e, 0xc4, 0x70, 0xe2, 0xb7, 0x45, 0xfb, 0x76, 0xd9,
+	0x75, 0x9c, 0xe4, 0xf0, 0xb0, 0xbd, 0x3f, 0xc9, 0x59, 0x2d, 0x91, 0x9c, 0x41, 0x4f, 0x8b, 0xba,
+	0xd9, 0xa1, 0x19, 0x6e, 0x59, 0x0c, 0x6f, 0x6d, 0x31, 0xf9, 0x1d, 0xc2, 0x38, 0xc3, 0x45, 0x25,
+	0x15, 0x8a, 0x6d, 0x8e, 0x9e, 0x5b, 0xd0, 0xc1, 0x2d, 0xec, 0xe4, 0xd6, 0x6b, 0x71, 0x1b, 0x41,
+	0x54, 0x68, 0xa9, 0xd8, 0xca, 0xf2, 0x3c, 0xc9, 0x9a, 0x88, 0xcc, 0x20, 0x62, 0xf3, 0xef, 0x58,
+	0xa8, 0x43, 0x2c, 0x9b, 0x32, 0x42, 0xa1, 0x6f, 0x24, 0x33, 0x23, 0xb2, 0x9d, 0x7c, 0xb8, 0x43,
+	0xb8, 0x7f, 0x80, 0xf0, 0x49, 0x9b, 0xb0, 0xd1, 0xaa, 0x15, 0x67, 0x42, 0x5d, 0x96, 0x74, 0xe0,
+	0x34, 0x1f, 0x93, 0xe7, 0x70, 0xaf, 0x5a, 0xd4, 0x4c, 0xe0, 0xdb, 0x6f, 0x79, 0xbd, 0x40, 0x49,
+	0xc1, 0x36, 0x6f, 0x27, 0x93, 0x5f, 0x01, 0xd0, 0x5d, 0x80, 0x7b, 0x0f, 0xca, 0xdd, 0xcd, 0x70,
+	0x7d, 0x37, 0xff, 0xb3, 0xe8, 0xdd, 0x8e, 0xc5, 0x08, 0x22, 0xa9, 0xf2, 0xf9, 0x12, 0x3d, 0x54,
+	0x17, 0x19, 0x46, 0x6e, 0x64, 0x6e, 0xa8, 0xf1, 0xe9, 0xc3, 0x04, 0xe1, 0x7c, 0xdb, 0xe0, 0x07,
+	0xad, 0xb8, 0x56, 0xd2, 0x1f, 0xf4, 0xae, 0xcd, 0x97, 0xd0, 0x67, 0xae, 0xe6, 0xd0, 0x65, 0xf2,
+	0x75, 0x17, 0x7f, 0x43, 0xb8, 0xef, 0xfb, 0xbf, 0x67, 0x75, 0xa5, 0x98, 0x20, 0x6f, 0x20, 0xba,
+	0xac, 0xaf, 0xd8, 0x0f, 0x24, 0x34, 0x5d, 0x7f, 0x01, 0xa9, 0x4b, 0x35, 0x8b, 0xc7, 0x8f, 0x3a,
+	0x14, 0x87, 0x2f, 0x39, 0x22, 0x1f, 0xe1, 0x74, 0xf3, 0x05, 0x90, 0xf3, 0x8d, 0xe2, 0x8e, 0xa7,
+	0x1f, 0x3f, 0xdd, 0xab, 0xaf, 0x5b, 0x7e, 0x86, 0xb3, 0x6d, 0x1c, 0x24, 0x69, 0x4d, 0xeb, 0x7c,
+	0x0d, 0xf1, 0xb3, 0x1b, 0x6b, 0xd6, 0xed, 0xbf, 0xc0, 0x78, 0x0f, 0x6d, 0xf2, 0xe2, 0x86, 0x0e,
+	0xed, 0x13, 0x89, 0x47, 0x3b, 0xb8, 0xdf, 0x99, 0x6f, 0x32, 0x39, 0x9a, 0x47, 0x36, 0xf3, 0xea,
+	0xdf, 0x00, 0xde, 0x19, 0x78, 0x9b, 0x63, 0x05, 0x00, 0x00,
 }
@@ -19,6 +19,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/apitype"
 	"github.com/pulumi/pulumi/pkg/backend/local"
 	"github.com/pulumi/pulumi/pkg/resource"
+	"github.com/pulumi/pulumi/pkg/resource/config"
 	"github.com/pulumi/pulumi/pkg/resource/stack"
 	"github.com/pulumi/pulumi/pkg/testing/integration"
 	"github.com/pulumi/pulumi/pkg/util/contract"
@@ -221,7 +222,7 @@ func TestStackCommands(t *testing.T) {
 		if !assert.NoError(t, err) {
 			t.FailNow()
 		}
-		snap, err := stack.DeserializeUntypedDeployment(&deployment)
+		snap, err := stack.DeserializeUntypedDeployment(&deployment, config.NewPanicCrypter())
 		if !assert.NoError(t, err) {
 			t.FailNow()
 		}
@@ -232,8 +233,11 @@ func TestStackCommands(t *testing.T) {
 			Resource: res,
 			Type:     resource.OperationTypeDeleting,
 		})
-		v2deployment := stack.SerializeDeployment(snap)
-		data, err := json.Marshal(&v2deployment)
+		v2deployment, err := stack.SerializeDeployment(snap, config.NewPanicCrypter())
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		data, err := json.Marshal(v2deployment)
 		if !assert.NoError(t, err) {
 			t.FailNow()
 		}